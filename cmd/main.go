@@ -13,6 +13,12 @@
 // @description     			To use authenticated endpoints:
 // @description     			1. Click "Authorize" button and enter: Bearer YOUR_TOKEN
 // @description     			2. All subsequent requests will include the token
+// @description
+// @description     			## Errors
+// @description     			Errors carry a machine-readable code, and the invalid fields of a request when known.
+// @description     			Send "Accept: application/problem+json" to get them as RFC 9457 problem details
+// @description     			(type, title, status, detail, instance, code, errors); other clients get
+// @description     			{"success": false, "error": ..., "code": ..., "details": [...]}.
 //
 //	@termsOfService				http://swagger.io/terms/
 //
@@ -60,11 +66,17 @@ func main() {
 	// Initialize service layer
 	svc := service.NewFavouriteService(repo)
 
+	// Relay the events recorded with each change to the change feed and webhooks until shutdown
+	dispatching, stopDispatching := context.WithCancel(context.Background())
+	defer stopDispatching()
+	go svc.DispatchEvents(dispatching)
+
 	// Initialize HTTP handlers
 	h := handler.NewHandler(svc)
 
 	// Setup middleware chain
 	mw := server.NewChain(
+		middleware.RequestID(),
 		middleware.Logger(), // TODO: add other middleware as needed e.g.
 		// middleware.RateLimit(cfg.RateLimitRequests, cfg.RateLimitWindow),
 	)
@@ -92,6 +104,7 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	stopDispatching()
 
 	log.Println("Server exited")
 }
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxFavouriteTitleLength = 200
+	maxFavouriteNoteLength  = 2000
+)
+
+// FavouriteUpdate changes a favourite's annotations. Nil fields are left as they are; an empty
+// string clears the field.
+type FavouriteUpdate struct {
+	Title *string
+	Note  *string
+}
+
+// Normalize returns the update with the title trimmed and its inner whitespace collapsed, and
+// the note trimmed. Titles may be at most 200 and notes at most 2000 characters long.
+func (u FavouriteUpdate) Normalize() (FavouriteUpdate, error) {
+	var normalized FavouriteUpdate
+	if u.Title != nil {
+		title := strings.Join(strings.Fields(*u.Title), " ")
+		if utf8.RuneCountInString(title) > maxFavouriteTitleLength {
+			return FavouriteUpdate{}, InvalidField(ErrInvalidAnnotation, "title", "title is longer than %d characters", maxFavouriteTitleLength)
+		}
+		normalized.Title = &title
+	}
+	if u.Note != nil {
+		note := strings.TrimSpace(*u.Note)
+		if utf8.RuneCountInString(note) > maxFavouriteNoteLength {
+			return FavouriteUpdate{}, InvalidField(ErrInvalidAnnotation, "note", "note is longer than %d characters", maxFavouriteNoteLength)
+		}
+		normalized.Note = &note
+	}
+	return normalized, nil
+}
+
+// Apply sets the fields of the update on a favourite
+func (u FavouriteUpdate) Apply(f *Favourite) {
+	if u.Title != nil {
+		f.Title = *u.Title
+	}
+	if u.Note != nil {
+		f.Note = *u.Note
+	}
+}
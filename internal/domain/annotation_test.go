@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavouriteUpdate(t *testing.T) {
+	title, note := "  Board \t deck  ", "\n Check the Q4 numbers\nwith finance \n"
+	update, err := FavouriteUpdate{Title: &title, Note: &note}.Normalize()
+	require.NoError(t, err)
+
+	fav := &Favourite{Title: "old", Note: "old"}
+	update.Apply(fav)
+	assert.Equal(t, "Board deck", fav.Title)
+	assert.Equal(t, "Check the Q4 numbers\nwith finance", fav.Note, "notes keep their line breaks")
+
+	// Nil fields are left alone, empty ones clear
+	empty := " "
+	update, err = FavouriteUpdate{Note: &empty}.Normalize()
+	require.NoError(t, err)
+	update.Apply(fav)
+	assert.Equal(t, "Board deck", fav.Title)
+	assert.Empty(t, fav.Note)
+
+	// Lengths are counted in characters
+	long := strings.Repeat("é", 200)
+	_, err = FavouriteUpdate{Title: &long}.Normalize()
+	assert.NoError(t, err)
+	for _, u := range []FavouriteUpdate{
+		{Title: ptr(strings.Repeat("a", 201))},
+		{Note: ptr(strings.Repeat("a", 2001))},
+	} {
+		_, err := u.Normalize()
+		assert.ErrorIs(t, err, ErrInvalidAnnotation)
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
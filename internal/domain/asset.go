@@ -4,6 +4,9 @@ package domain
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,34 +23,63 @@ const (
 
 // Asset represents a generic asset that can be favourited
 type Asset struct {
-	ID          uuid.UUID       `json:"id"`
-	Type        AssetType       `json:"type"`
-	Description string          `json:"description"`
-	Data        json.RawMessage `json:"data" swaggertype:"object,string" example:"{\"title\":\"Sample Chart\"}"` // Polymorphic data field
-	CreatedAt   time.Time       `json:"created_at"`
-	UpdatedAt   time.Time       `json:"updated_at"`
+	ID             uuid.UUID       `json:"id"`
+	Type           AssetType       `json:"type"`
+	Description    string          `json:"description"`
+	Data           json.RawMessage `json:"data" swaggertype:"object,string" example:"{\"title\":\"Sample Chart\"}"` // Polymorphic data field
+	Tags           []string        `json:"tags,omitempty"`
+	FavouriteCount int             `json:"favourite_count"` // Number of users who favourited the asset, maintained by the repository
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
 }
 
-// ChartData represents chart-specific data
+// ChartKind represents how a chart is drawn
+type ChartKind string
+
+const (
+	ChartKindLine    ChartKind = "line"
+	ChartKindBar     ChartKind = "bar"
+	ChartKindPie     ChartKind = "pie"
+	ChartKindScatter ChartKind = "scatter"
+)
+
+// maxChartDecimals bounds NumberFormat.Decimals
+const maxChartDecimals = 10
+
+// ChartData represents chart-specific data. Series are either plotted against the category
+// axis (one value per category) or, without categories, as numeric x/y points.
 type ChartData struct {
-	Title      string      `json:"title"`
-	AxisXTitle string      `json:"axis_x_title"`
-	AxisYTitle string      `json:"axis_y_title"`
-	Data       [][]float64 `json:"data"` // 2D array of data points
+	Title        string        `json:"title"`
+	Kind         ChartKind     `json:"kind,omitempty"` // defaults to line
+	AxisXTitle   string        `json:"axis_x_title"`
+	AxisYTitle   string        `json:"axis_y_title"`
+	AxisXUnit    string        `json:"axis_x_unit,omitempty"`
+	AxisYUnit    string        `json:"axis_y_unit,omitempty"`
+	Categories   []string      `json:"categories,omitempty"` // category axis labels (slice labels for pie charts)
+	Series       []ChartSeries `json:"series,omitempty"`
+	NumberFormat *NumberFormat `json:"number_format,omitempty"`
+	Data         [][]float64   `json:"data,omitempty"` // Deprecated: legacy rows of [x, y1, y2, ...], read as point series
+}
+
+// ChartSeries is a named sequence of values or points
+type ChartSeries struct {
+	Name   string       `json:"name"`
+	Values []float64    `json:"values,omitempty"` // one value per category
+	Points []ChartPoint `json:"points,omitempty"` // x/y pairs when the chart has no categories
 }
 
-// InsightData represents insight-specific data
-type InsightData struct {
-	Text string `json:"text"`
+// ChartPoint is a single x/y data point
+type ChartPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
 }
 
-// AudienceData represents audience-specific data
-type AudienceData struct {
-	Gender             string   `json:"gender"` // "Male", "Female"
-	BirthCountry       string   `json:"birth_country"`
-	AgeGroups          []string `json:"age_groups"` // e.g., ["18-24", "25-34"]
-	HoursSocialDaily   float64  `json:"hours_social_daily"`
-	PurchasesLastMonth int      `json:"purchases_last_month"`
+// NumberFormat describes how values are displayed
+type NumberFormat struct {
+	Decimals           int    `json:"decimals"`
+	Prefix             string `json:"prefix,omitempty"` // e.g. "$"
+	Suffix             string `json:"suffix,omitempty"` // e.g. "%"
+	ThousandsSeparator bool   `json:"thousands_separator,omitempty"`
 }
 
 // Validate ensures the asset is properly formed
@@ -70,36 +102,64 @@ func (a *Asset) Validate() error {
 	// Type-specific validation
 	switch a.Type {
 	case AssetTypeChart:
-		var chartData ChartData
-		if err := json.Unmarshal(a.Data, &chartData); err != nil {
-			return fmt.Errorf("invalid chart data: %w", err)
-		}
-		if chartData.Title == "" {
-			return ErrInvalidChartData
+		if _, err := ParseChartData(a.Data); err != nil {
+			return err
 		}
 
 	case AssetTypeInsight:
-		var insightData InsightData
-		if err := json.Unmarshal(a.Data, &insightData); err != nil {
-			return fmt.Errorf("invalid insight data: %w", err)
-		}
-		if insightData.Text == "" {
-			return ErrInvalidInsightData
+		if _, err := ParseInsightData(a.Data); err != nil {
+			return err
 		}
 
 	case AssetTypeAudience:
-		var audienceData AudienceData
-		if err := json.Unmarshal(a.Data, &audienceData); err != nil {
-			return fmt.Errorf("invalid audience data: %w", err)
-		}
-		if audienceData.Gender == "" {
-			return ErrInvalidAudienceData
+		if _, err := ParseAudienceData(a.Data); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// SetTags normalizes and replaces the asset's tags, keeping them sorted and unique
+func (a *Asset) SetTags(tags []string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	if len(normalized) > maxTagsPerAsset {
+		return InvalidField(ErrInvalidTag, "tags", "an asset can have at most %d tags", maxTagsPerAsset)
+	}
+	a.Tags = normalized
+	return nil
+}
+
+// AssetReference is a link from one asset to another asset of the given type
+type AssetReference struct {
+	AssetID uuid.UUID
+	Type    AssetType
+}
+
+// References returns the assets this asset links to. Repositories use it to enforce
+// referential integrity; a malformed asset has no references.
+func (a *Asset) References() []AssetReference {
+	if a.Type != AssetTypeInsight {
+		return nil
+	}
+	insight, err := ParseInsightData(a.Data)
+	if err != nil || insight.AudienceID == nil {
+		return nil
+	}
+	return []AssetReference{{AssetID: *insight.AudienceID, Type: AssetTypeAudience}}
+}
+
+// DeletePolicy controls what happens to assets that reference an asset being deleted
+type DeletePolicy string
+
+const (
+	DeleteRestrict DeletePolicy = "restrict" // refuse to delete a referenced asset
+	DeleteCascade  DeletePolicy = "cascade"  // delete referencing assets as well
+)
+
 // NewAsset creates a new asset with generated ID and timestamps
 func NewAsset(assetType AssetType, description string, data interface{}) (*Asset, error) {
 	dataBytes, err := json.Marshal(data)
@@ -123,3 +183,187 @@ func NewAsset(assetType AssetType, description string, data interface{}) (*Asset
 
 	return asset, nil
 }
+
+// ParseChartData decodes and validates raw chart asset data. Charts stored with the legacy
+// Data rows are converted to point series, and a missing kind defaults to line.
+func ParseChartData(raw json.RawMessage) (*ChartData, error) {
+	var data ChartData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid chart data: %w", err)
+	}
+	if err := data.normalize(); err != nil {
+		return nil, err
+	}
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// normalize upgrades legacy Data rows to series and applies the default kind
+func (c *ChartData) normalize() error {
+	if c.Kind == "" {
+		c.Kind = ChartKindLine
+	}
+	if len(c.Series) > 0 || len(c.Data) == 0 {
+		c.Data = nil
+		return nil
+	}
+
+	// A legacy row is [x, y1, y2, ...]; single-value rows are plotted against their index
+	width := len(c.Data[0])
+	if width == 0 {
+		return fmt.Errorf("%w: data rows cannot be empty", ErrInvalidChartData)
+	}
+	columns := width - 1
+	if columns == 0 {
+		columns = 1
+	}
+	series := make([]ChartSeries, columns)
+	for i := range series {
+		series[i] = ChartSeries{Name: fmt.Sprintf("Series %d", i+1), Points: make([]ChartPoint, 0, len(c.Data))}
+	}
+	for rowIdx, row := range c.Data {
+		if len(row) != width {
+			return fmt.Errorf("%w: data row %d has %d values, expected %d", ErrInvalidChartData, rowIdx, len(row), width)
+		}
+		if width == 1 {
+			series[0].Points = append(series[0].Points, ChartPoint{X: float64(rowIdx), Y: row[0]})
+			continue
+		}
+		for col := 1; col < width; col++ {
+			series[col-1].Points = append(series[col-1].Points, ChartPoint{X: row[0], Y: row[col]})
+		}
+	}
+
+	c.Series = series
+	c.Data = nil
+	return nil
+}
+
+// Validate checks the chart title, kind, number format and the shape of the series for the kind
+func (c *ChartData) Validate() error {
+	if c.Title == "" {
+		return InvalidField(ErrInvalidChartData, "title", "title is required")
+	}
+
+	switch c.Kind {
+	case "", ChartKindLine, ChartKindBar, ChartKindPie, ChartKindScatter:
+	default:
+		return InvalidField(ErrInvalidChartData, "kind", "unknown kind %q", c.Kind)
+	}
+
+	if c.NumberFormat != nil && (c.NumberFormat.Decimals < 0 || c.NumberFormat.Decimals > maxChartDecimals) {
+		return InvalidField(ErrInvalidChartData, "number_format.decimals", "number_format.decimals must be between 0 and %d", maxChartDecimals)
+	}
+
+	for i, category := range c.Categories {
+		if strings.TrimSpace(category) == "" {
+			return InvalidField(ErrInvalidChartData, fmt.Sprintf("categories[%d]", i), "categories[%d] is empty", i)
+		}
+	}
+
+	names := make(map[string]bool, len(c.Series))
+	for i, series := range c.Series {
+		if series.Name == "" {
+			return InvalidField(ErrInvalidChartData, fmt.Sprintf("series[%d].name", i), "series[%d] has no name", i)
+		}
+		if names[series.Name] {
+			return fmt.Errorf("%w: duplicate series name %q", ErrInvalidChartData, series.Name)
+		}
+		names[series.Name] = true
+
+		for _, v := range series.Values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("%w: series %q contains a non-finite value", ErrInvalidChartData, series.Name)
+			}
+		}
+	}
+
+	switch c.Kind {
+	case ChartKindPie:
+		return c.validatePie()
+	case ChartKindScatter:
+		if len(c.Categories) > 0 {
+			return fmt.Errorf("%w: scatter charts do not use categories", ErrInvalidChartData)
+		}
+		return c.validateSeriesShape(false)
+	default: // line, bar
+		return c.validateSeriesShape(len(c.Categories) > 0)
+	}
+}
+
+// validateSeriesShape checks that every series uses values (categorical charts) or points (numeric charts)
+func (c *ChartData) validateSeriesShape(categorical bool) error {
+	for _, series := range c.Series {
+		if categorical {
+			if len(series.Points) > 0 {
+				return fmt.Errorf("%w: series %q must use values when categories are set", ErrInvalidChartData, series.Name)
+			}
+			if len(series.Values) != len(c.Categories) {
+				return fmt.Errorf("%w: series %q has %d values for %d categories", ErrInvalidChartData, series.Name, len(series.Values), len(c.Categories))
+			}
+			continue
+		}
+		if len(series.Values) > 0 {
+			return fmt.Errorf("%w: series %q must use points when no categories are set", ErrInvalidChartData, series.Name)
+		}
+	}
+	return nil
+}
+
+func (c *ChartData) validatePie() error {
+	if len(c.Series) == 0 {
+		return nil
+	}
+	if len(c.Series) != 1 {
+		return fmt.Errorf("%w: pie charts have exactly one series", ErrInvalidChartData)
+	}
+	if len(c.Categories) == 0 {
+		return fmt.Errorf("%w: pie charts require categories", ErrInvalidChartData)
+	}
+	if err := c.validateSeriesShape(true); err != nil {
+		return err
+	}
+
+	total := 0.0
+	for _, v := range c.Series[0].Values {
+		if v < 0 {
+			return fmt.Errorf("%w: pie chart values cannot be negative", ErrInvalidChartData)
+		}
+		total += v
+	}
+	if total == 0 {
+		return fmt.Errorf("%w: pie chart values sum to zero", ErrInvalidChartData)
+	}
+	return nil
+}
+
+// Format renders v using the number format, e.g. 1234.5 as "$1,234.50"
+func (f *NumberFormat) Format(v float64) string {
+	if f == nil {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+
+	s := strconv.FormatFloat(math.Abs(v), 'f', f.Decimals, 64)
+	if f.ThousandsSeparator {
+		intPart, fracPart, hasFrac := strings.Cut(s, ".")
+		var b strings.Builder
+		for i, digit := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				b.WriteByte(',')
+			}
+			b.WriteRune(digit)
+		}
+		s = b.String()
+		if hasFrac {
+			s += "." + fracPart
+		}
+	}
+
+	sign := ""
+	if v < 0 && strings.Trim(s, "0.,") != "" {
+		sign = "-"
+	}
+	return sign + f.Prefix + s + f.Suffix
+}
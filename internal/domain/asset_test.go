@@ -45,10 +45,10 @@ func TestNewAsset(t *testing.T) {
 			description: "Target Audience",
 			data: AudienceData{
 				Gender:             "Female",
-				BirthCountry:       "UK",
-				AgeGroups:          []string{"25-34"},
-				HoursSocialDaily:   4.5,
-				PurchasesLastMonth: 15,
+				BirthCountry:       "GB",
+				AgeGroups:          []AgeGroup{"25-34"},
+				HoursSocialDaily:   AtLeast(4.5),
+				PurchasesLastMonth: AtLeast(15),
 			},
 			wantErr: nil,
 		},
@@ -74,11 +74,18 @@ func TestNewAsset(t *testing.T) {
 			wantErr: ErrInvalidInsightData,
 		},
 		{
-			name:        "invalid audience - empty gender",
+			name:        "invalid audience - no criteria",
+			assetType:   AssetTypeAudience,
+			description: "Invalid Audience",
+			data:        AudienceData{},
+			wantErr:     ErrInvalidAudienceData,
+		},
+		{
+			name:        "invalid audience - unknown gender",
 			assetType:   AssetTypeAudience,
 			description: "Invalid Audience",
 			data: AudienceData{
-				Gender:       "",
+				Gender:       "Other",
 				BirthCountry: "USA",
 			},
 			wantErr: ErrInvalidAudienceData,
@@ -192,3 +199,135 @@ func TestAssetType_Constants(t *testing.T) {
 	assert.Equal(t, AssetType("insight"), AssetTypeInsight)
 	assert.Equal(t, AssetType("audience"), AssetTypeAudience)
 }
+
+func TestParseChartData(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{
+			name: "categorical line chart",
+			raw: `{"title":"Revenue","kind":"line","categories":["Oct","Nov","Dec"],
+				"series":[{"name":"2024","values":[1,2,3]},{"name":"2025","values":[2,3,4]}]}`,
+		},
+		{
+			name: "bar chart with units and format",
+			raw: `{"title":"Revenue","kind":"bar","axis_y_unit":"USD","categories":["EU","US"],
+				"series":[{"name":"Q4","values":[1200.5,3400]}],"number_format":{"decimals":2,"prefix":"$","thousands_separator":true}}`,
+		},
+		{
+			name: "pie chart",
+			raw:  `{"title":"Share","kind":"pie","categories":["A","B"],"series":[{"name":"share","values":[30,70]}]}`,
+		},
+		{
+			name: "scatter chart",
+			raw:  `{"title":"Correlation","kind":"scatter","series":[{"name":"users","points":[{"x":1,"y":2},{"x":3,"y":4}]}]}`,
+		},
+		{name: "title only", raw: `{"title":"Placeholder"}`},
+		{name: "missing title", raw: `{"kind":"line"}`, wantErr: true},
+		{name: "unknown kind", raw: `{"title":"T","kind":"radar"}`, wantErr: true},
+		{
+			name:    "values do not match categories",
+			raw:     `{"title":"T","kind":"bar","categories":["A","B"],"series":[{"name":"s","values":[1]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "points on categorical chart",
+			raw:     `{"title":"T","categories":["A"],"series":[{"name":"s","points":[{"x":1,"y":1}]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "values without categories",
+			raw:     `{"title":"T","kind":"line","series":[{"name":"s","values":[1,2]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "pie with two series",
+			raw:     `{"title":"T","kind":"pie","categories":["A"],"series":[{"name":"a","values":[1]},{"name":"b","values":[1]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "pie with negative value",
+			raw:     `{"title":"T","kind":"pie","categories":["A","B"],"series":[{"name":"a","values":[-1,2]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "scatter with categories",
+			raw:     `{"title":"T","kind":"scatter","categories":["A"],"series":[{"name":"a","points":[{"x":1,"y":1}]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate series names",
+			raw:     `{"title":"T","series":[{"name":"a","points":[]},{"name":"a","points":[]}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unnamed series",
+			raw:     `{"title":"T","series":[{"points":[{"x":1,"y":1}]}]}`,
+			wantErr: true,
+		},
+		{name: "ragged legacy rows", raw: `{"title":"T","data":[[1,2],[3]]}`, wantErr: true},
+		{name: "decimals out of range", raw: `{"title":"T","number_format":{"decimals":11}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart, err := ParseChartData(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrInvalidChartData)
+				assert.Nil(t, chart)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, chart)
+		})
+	}
+}
+
+func TestParseChartData_LegacyData(t *testing.T) {
+	// Assets stored before series existed only carry title, axis titles and raw rows
+	chart, err := ParseChartData(json.RawMessage(`{
+		"title": "Q4 Sales",
+		"axis_x_title": "Month",
+		"axis_y_title": "Revenue",
+		"data": [[1, 100, 90], [2, 200, 180]]
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, ChartKindLine, chart.Kind)
+	assert.Nil(t, chart.Data)
+	require.Len(t, chart.Series, 2)
+	assert.Equal(t, "Series 1", chart.Series[0].Name)
+	assert.Equal(t, []ChartPoint{{X: 1, Y: 100}, {X: 2, Y: 200}}, chart.Series[0].Points)
+	assert.Equal(t, []ChartPoint{{X: 1, Y: 90}, {X: 2, Y: 180}}, chart.Series[1].Points)
+
+	// Single-value rows are plotted against their index
+	chart, err = ParseChartData(json.RawMessage(`{"title": "T", "data": [[5], [7]]}`))
+	require.NoError(t, err)
+	require.Len(t, chart.Series, 1)
+	assert.Equal(t, []ChartPoint{{X: 0, Y: 5}, {X: 1, Y: 7}}, chart.Series[0].Points)
+}
+
+func TestNumberFormat_Format(t *testing.T) {
+	tests := []struct {
+		name   string
+		format *NumberFormat
+		value  float64
+		want   string
+	}{
+		{name: "no format", format: nil, value: 1234.5, want: "1234.5"},
+		{name: "decimals", format: &NumberFormat{Decimals: 2}, value: 3.14159, want: "3.14"},
+		{name: "currency", format: &NumberFormat{Decimals: 2, Prefix: "$", ThousandsSeparator: true}, value: 1234567.891, want: "$1,234,567.89"},
+		{name: "negative", format: &NumberFormat{Decimals: 0, Prefix: "$", ThousandsSeparator: true}, value: -1500, want: "-$1,500"},
+		{name: "percent", format: &NumberFormat{Decimals: 1, Suffix: "%"}, value: 40, want: "40.0%"},
+		{name: "negative zero", format: &NumberFormat{Decimals: 0}, value: -0.2, want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.format.Format(tt.value))
+		})
+	}
+}
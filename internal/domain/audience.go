@@ -0,0 +1,356 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxAudienceDepth bounds the nesting of AND/OR criteria groups
+const maxAudienceDepth = 5
+
+// Gender represents the gender criterion of an audience
+type Gender string
+
+const (
+	GenderMale   Gender = "Male"
+	GenderFemale Gender = "Female"
+)
+
+// Valid reports whether the gender is one of the supported values
+func (g Gender) Valid() bool {
+	return g == GenderMale || g == GenderFemale
+}
+
+// UnmarshalJSON accepts gender values case-insensitively
+func (g *Gender) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "male":
+		*g = GenderMale
+	case "female":
+		*g = GenderFemale
+	default:
+		*g = Gender(s)
+	}
+	return nil
+}
+
+// AgeGroup is an inclusive age bracket written as "25-34" or open-ended as "65+"
+type AgeGroup string
+
+// Bounds returns the inclusive lower and upper age of the group
+func (a AgeGroup) Bounds() (int, int, error) {
+	s := strings.TrimSpace(string(a))
+	if lower, ok := strings.CutSuffix(s, "+"); ok {
+		min, err := strconv.Atoi(lower)
+		if err != nil || min < 0 {
+			return 0, 0, fmt.Errorf("invalid age group %q", a)
+		}
+		return min, math.MaxInt, nil
+	}
+
+	lower, upper, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid age group %q", a)
+	}
+	min, err1 := strconv.Atoi(lower)
+	max, err2 := strconv.Atoi(upper)
+	if err1 != nil || err2 != nil || min < 0 || min > max {
+		return 0, 0, fmt.Errorf("invalid age group %q", a)
+	}
+	return min, max, nil
+}
+
+// Contains reports whether the given age falls inside the group
+func (a AgeGroup) Contains(age int) bool {
+	min, max, err := a.Bounds()
+	if err != nil {
+		return false
+	}
+	return age >= min && age <= max
+}
+
+// NumericRange constrains a numeric attribute with optional exclusive (gt, lt) and
+// inclusive (gte, lte) bounds, e.g. {"gt": 3} for "more than 3"
+type NumericRange struct {
+	Gt  *float64 `json:"gt,omitempty"`
+	Gte *float64 `json:"gte,omitempty"`
+	Lt  *float64 `json:"lt,omitempty"`
+	Lte *float64 `json:"lte,omitempty"`
+}
+
+// MoreThan returns a range matching values strictly greater than v
+func MoreThan(v float64) *NumericRange { return &NumericRange{Gt: &v} }
+
+// AtLeast returns a range matching values greater than or equal to v
+func AtLeast(v float64) *NumericRange { return &NumericRange{Gte: &v} }
+
+// LessThan returns a range matching values strictly less than v
+func LessThan(v float64) *NumericRange { return &NumericRange{Lt: &v} }
+
+// Between returns a range matching values in the inclusive interval [min, max]
+func Between(min, max float64) *NumericRange { return &NumericRange{Gte: &min, Lte: &max} }
+
+// Exactly returns a range matching v only
+func Exactly(v float64) *NumericRange { return Between(v, v) }
+
+// UnmarshalJSON accepts either a range object or, for backward compatibility with the
+// former single-number representation, a bare number. A bare number states a value and no
+// bound, so it is read as that value exactly, {"gte": number, "lte": number}, rather than
+// guessing at the bound meant. Assets are stored as re-encoded from their decoded data, so
+// audiences created with bare numbers are kept in the range form from then on.
+func (r *NumericRange) UnmarshalJSON(b []byte) error {
+	var n float64
+	if err := json.Unmarshal(b, &n); err == nil {
+		*r = *Exactly(n)
+		return nil
+	}
+
+	type rangeAlias NumericRange // avoid recursing into this method
+	var alias rangeAlias
+	if err := json.Unmarshal(b, &alias); err != nil {
+		return err
+	}
+	*r = NumericRange(alias)
+	return nil
+}
+
+// Validate ensures the range is bounded and not empty
+func (r *NumericRange) Validate() error {
+	if r.Gt == nil && r.Gte == nil && r.Lt == nil && r.Lte == nil {
+		return fmt.Errorf("range needs at least one of gt, gte, lt, lte")
+	}
+	if r.Gt != nil && r.Gte != nil {
+		return fmt.Errorf("gt and gte are mutually exclusive")
+	}
+	if r.Lt != nil && r.Lte != nil {
+		return fmt.Errorf("lt and lte are mutually exclusive")
+	}
+
+	lower, lowerInclusive := math.Inf(-1), false
+	if r.Gt != nil {
+		lower = *r.Gt
+	} else if r.Gte != nil {
+		lower, lowerInclusive = *r.Gte, true
+	}
+	upper, upperInclusive := math.Inf(1), false
+	if r.Lt != nil {
+		upper = *r.Lt
+	} else if r.Lte != nil {
+		upper, upperInclusive = *r.Lte, true
+	}
+
+	if lower > upper || (lower == upper && !(lowerInclusive && upperInclusive)) {
+		return fmt.Errorf("range is empty")
+	}
+	return nil
+}
+
+// Contains reports whether v satisfies every bound of the range
+func (r *NumericRange) Contains(v float64) bool {
+	if r.Gt != nil && !(v > *r.Gt) {
+		return false
+	}
+	if r.Gte != nil && !(v >= *r.Gte) {
+		return false
+	}
+	if r.Lt != nil && !(v < *r.Lt) {
+		return false
+	}
+	if r.Lte != nil && !(v <= *r.Lte) {
+		return false
+	}
+	return true
+}
+
+// AudienceData represents audience-specific data as a group of typed criteria.
+// All criteria set on a group must hold (AND); nested groups in All must all match,
+// while at least one group in Any must match (OR).
+// e.g. Males aged 25-34 that spend more than 3 hours on social media daily:
+//
+//	{"gender": "Male", "age_groups": ["25-34"], "hours_social_daily": {"gt": 3}}
+type AudienceData struct {
+	Gender             Gender         `json:"gender,omitempty"`
+	BirthCountry       CountryCode    `json:"birth_country,omitempty"` // ISO 3166-1 alpha-2 (alpha-3 accepted on input)
+	AgeGroups          []AgeGroup     `json:"age_groups,omitempty"`    // matches if the age falls into any group
+	HoursSocialDaily   *NumericRange  `json:"hours_social_daily,omitempty"`
+	PurchasesLastMonth *NumericRange  `json:"purchases_last_month,omitempty"`
+	All                []AudienceData `json:"all,omitempty"` // AND group
+	Any                []AudienceData `json:"any,omitempty"` // OR group
+}
+
+// ParseAudienceData decodes and validates raw audience asset data
+func ParseAudienceData(raw json.RawMessage) (*AudienceData, error) {
+	var data AudienceData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid audience data: %w", err)
+	}
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Validate ensures every criterion of the audience is well formed
+func (a *AudienceData) Validate() error {
+	return a.validate("audience", 0)
+}
+
+func (a *AudienceData) validate(path string, depth int) error {
+	if depth > maxAudienceDepth {
+		return InvalidField(ErrInvalidAudienceData, path, "%s: groups nested deeper than %d levels", path, maxAudienceDepth)
+	}
+	if a.isEmpty() {
+		return InvalidField(ErrInvalidAudienceData, path, "%s: at least one criterion is required", path)
+	}
+
+	if a.Gender != "" && !a.Gender.Valid() {
+		return InvalidField(ErrInvalidAudienceData, path+".gender", "%s.gender: must be one of %q, %q", path, GenderMale, GenderFemale)
+	}
+	if a.BirthCountry != "" && !a.BirthCountry.Valid() {
+		return InvalidField(ErrInvalidAudienceData, path+".birth_country", "%s.birth_country: %q is not an ISO 3166-1 country code", path, a.BirthCountry)
+	}
+	for _, group := range a.AgeGroups {
+		if _, _, err := group.Bounds(); err != nil {
+			return InvalidField(ErrInvalidAudienceData, path+".age_groups", "%s.age_groups: %v", path, err)
+		}
+	}
+	if a.HoursSocialDaily != nil {
+		if err := validateBoundedRange(a.HoursSocialDaily, 0, 24); err != nil {
+			return InvalidField(ErrInvalidAudienceData, path+".hours_social_daily", "%s.hours_social_daily: %v", path, err)
+		}
+	}
+	if a.PurchasesLastMonth != nil {
+		if err := validateBoundedRange(a.PurchasesLastMonth, 0, math.Inf(1)); err != nil {
+			return InvalidField(ErrInvalidAudienceData, path+".purchases_last_month", "%s.purchases_last_month: %v", path, err)
+		}
+	}
+
+	for i := range a.All {
+		if err := a.All[i].validate(fmt.Sprintf("%s.all[%d]", path, i), depth+1); err != nil {
+			return err
+		}
+	}
+	for i := range a.Any {
+		if err := a.Any[i].validate(fmt.Sprintf("%s.any[%d]", path, i), depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBoundedRange validates r and checks that its bounds lie within [min, max]
+func validateBoundedRange(r *NumericRange, min, max float64) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	for _, bound := range []*float64{r.Gt, r.Gte, r.Lt, r.Lte} {
+		if bound != nil && (*bound < min || *bound > max) {
+			return fmt.Errorf("bound %v outside [%v, %v]", *bound, min, max)
+		}
+	}
+	return nil
+}
+
+// Countries returns the birth countries the audience and its nested groups refer to
+func (a *AudienceData) Countries() []CountryCode {
+	var countries []CountryCode
+	a.walk(func(criteria *AudienceData) {
+		if criteria.BirthCountry != "" {
+			countries = append(countries, criteria.BirthCountry)
+		}
+	})
+	return countries
+}
+
+// walk calls fn for the audience and every nested group
+func (a *AudienceData) walk(fn func(*AudienceData)) {
+	fn(a)
+	for i := range a.All {
+		a.All[i].walk(fn)
+	}
+	for i := range a.Any {
+		a.Any[i].walk(fn)
+	}
+}
+
+func (a *AudienceData) isEmpty() bool {
+	return a.Gender == "" && a.BirthCountry == "" && len(a.AgeGroups) == 0 &&
+		a.HoursSocialDaily == nil && a.PurchasesLastMonth == nil &&
+		len(a.All) == 0 && len(a.Any) == 0
+}
+
+// RespondentProfile holds the attributes of a single respondent to be tested against an audience
+type RespondentProfile struct {
+	Gender             Gender      `json:"gender" example:"Male"`
+	BirthCountry       CountryCode `json:"birth_country" swaggertype:"string" example:"GB"`
+	Age                int         `json:"age" example:"29"`
+	HoursSocialDaily   float64     `json:"hours_social_daily" example:"3.5"`
+	PurchasesLastMonth int         `json:"purchases_last_month" example:"4"`
+}
+
+// Validate ensures the profile is complete and within sensible limits
+func (p *RespondentProfile) Validate() error {
+	switch {
+	case !p.Gender.Valid():
+		return InvalidField(ErrInvalidRespondentProfile, "gender", "gender must be one of %q, %q", GenderMale, GenderFemale)
+	case !p.BirthCountry.Valid():
+		return InvalidField(ErrInvalidRespondentProfile, "birth_country", "birth_country %q is not an ISO 3166-1 country code", p.BirthCountry)
+	case p.Age < 0 || p.Age > 150:
+		return InvalidField(ErrInvalidRespondentProfile, "age", "age must be between 0 and 150")
+	case p.HoursSocialDaily < 0 || p.HoursSocialDaily > 24:
+		return InvalidField(ErrInvalidRespondentProfile, "hours_social_daily", "hours_social_daily must be between 0 and 24")
+	case p.PurchasesLastMonth < 0:
+		return InvalidField(ErrInvalidRespondentProfile, "purchases_last_month", "purchases_last_month cannot be negative")
+	}
+	return nil
+}
+
+// Matches evaluates the audience criteria against a respondent profile
+func (a *AudienceData) Matches(p *RespondentProfile) bool {
+	if a.Gender != "" && a.Gender != p.Gender {
+		return false
+	}
+	if a.BirthCountry != "" && a.BirthCountry != p.BirthCountry {
+		return false
+	}
+	if len(a.AgeGroups) > 0 {
+		inGroup := false
+		for _, group := range a.AgeGroups {
+			if group.Contains(p.Age) {
+				inGroup = true
+				break
+			}
+		}
+		if !inGroup {
+			return false
+		}
+	}
+	if a.HoursSocialDaily != nil && !a.HoursSocialDaily.Contains(p.HoursSocialDaily) {
+		return false
+	}
+	if a.PurchasesLastMonth != nil && !a.PurchasesLastMonth.Contains(float64(p.PurchasesLastMonth)) {
+		return false
+	}
+
+	for i := range a.All {
+		if !a.All[i].Matches(p) {
+			return false
+		}
+	}
+	if len(a.Any) > 0 {
+		for i := range a.Any {
+			if a.Any[i].Matches(p) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCountryCode(t *testing.T) {
+	tests := []struct {
+		input string
+		want  CountryCode
+		ok    bool
+	}{
+		{input: "GB", want: "GB", ok: true},
+		{input: "gb", want: "GB", ok: true},
+		{input: "USA", want: "US", ok: true},
+		{input: " deu ", want: "DE", ok: true},
+		{input: "UK", want: "GB", ok: true},
+		{input: "el", want: "GR", ok: true},
+		{input: "XX", ok: false},
+		{input: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := ParseCountryCode(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAgeGroup_Bounds(t *testing.T) {
+	min, max, err := AgeGroup("25-34").Bounds()
+	require.NoError(t, err)
+	assert.Equal(t, 25, min)
+	assert.Equal(t, 34, max)
+
+	min, max, err = AgeGroup("65+").Bounds()
+	require.NoError(t, err)
+	assert.Equal(t, 65, min)
+	assert.Equal(t, math.MaxInt, max)
+
+	for _, invalid := range []AgeGroup{"", "abc", "34-25", "-5", "+"} {
+		_, _, err := invalid.Bounds()
+		assert.Error(t, err, "age group %q", invalid)
+	}
+
+	assert.True(t, AgeGroup("25-34").Contains(25))
+	assert.True(t, AgeGroup("25-34").Contains(34))
+	assert.False(t, AgeGroup("25-34").Contains(35))
+	assert.True(t, AgeGroup("65+").Contains(90))
+}
+
+func TestNumericRange(t *testing.T) {
+	three := 3.0
+
+	t.Run("bounds are applied", func(t *testing.T) {
+		assert.False(t, MoreThan(3).Contains(3))
+		assert.True(t, MoreThan(3).Contains(3.1))
+		assert.True(t, AtLeast(3).Contains(3))
+		assert.False(t, LessThan(3).Contains(3))
+		assert.True(t, Between(1, 3).Contains(3))
+		assert.False(t, Between(1, 3).Contains(0.5))
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		assert.NoError(t, MoreThan(3).Validate())
+		assert.NoError(t, Between(3, 3).Validate())
+		assert.Error(t, (&NumericRange{}).Validate())
+		assert.Error(t, (&NumericRange{Gt: &three, Gte: &three}).Validate())
+		assert.Error(t, (&NumericRange{Gt: &three, Lt: &three}).Validate())
+		assert.Error(t, Between(5, 1).Validate())
+	})
+
+	t.Run("decodes range objects and legacy numbers", func(t *testing.T) {
+		var r NumericRange
+		require.NoError(t, json.Unmarshal([]byte(`{"gt": 3, "lte": 8}`), &r))
+		require.NotNil(t, r.Gt)
+		require.NotNil(t, r.Lte)
+		assert.Equal(t, 3.0, *r.Gt)
+		assert.Equal(t, 8.0, *r.Lte)
+
+		var legacy NumericRange
+		require.NoError(t, json.Unmarshal([]byte(`2.5`), &legacy))
+		assert.Equal(t, Exactly(2.5), &legacy, "bare numbers are read as the value exactly")
+		assert.True(t, legacy.Contains(2.5))
+		assert.False(t, legacy.Contains(3))
+	})
+}
+
+func TestParseAudienceData(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{
+			name: "typed criteria",
+			raw:  `{"gender":"male","birth_country":"USA","age_groups":["25-34"],"hours_social_daily":{"gt":3}}`,
+		},
+		{
+			name: "legacy flat representation",
+			raw:  `{"gender":"Female","birth_country":"GB","age_groups":["18-24"],"hours_social_daily":2.5,"purchases_last_month":5}`,
+		},
+		{
+			name: "nested groups",
+			raw:  `{"any":[{"birth_country":"GB"},{"all":[{"gender":"Male"},{"purchases_last_month":{"gte":2}}]}]}`,
+		},
+		{name: "no criteria", raw: `{}`, wantErr: true},
+		{name: "unknown country", raw: `{"birth_country":"XK"}`, wantErr: true},
+		{name: "invalid age group", raw: `{"age_groups":["old"]}`, wantErr: true},
+		{name: "hours out of range", raw: `{"hours_social_daily":{"gt":30}}`, wantErr: true},
+		{name: "negative purchases", raw: `{"purchases_last_month":{"lt":-1}}`, wantErr: true},
+		{name: "empty nested group", raw: `{"any":[{}]}`, wantErr: true},
+		{name: "malformed range", raw: `{"hours_social_daily":"lots"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ParseAudienceData(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Nil(t, data)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, data)
+		})
+	}
+
+	t.Run("codes and genders are normalized", func(t *testing.T) {
+		data, err := ParseAudienceData(json.RawMessage(`{"gender":"MALE","birth_country":"usa"}`))
+		require.NoError(t, err)
+		assert.Equal(t, GenderMale, data.Gender)
+		assert.Equal(t, CountryCode("US"), data.BirthCountry)
+	})
+
+	t.Run("legacy audiences are normalized on purpose", func(t *testing.T) {
+		data, err := ParseAudienceData(json.RawMessage(`{"gender":"Female","birth_country":"UK","hours_social_daily":2.5,"purchases_last_month":5}`))
+		require.NoError(t, err)
+		assert.Equal(t, CountryCode("GB"), data.BirthCountry)
+		assert.Equal(t, Exactly(2.5), data.HoursSocialDaily)
+		assert.Equal(t, Exactly(5), data.PurchasesLastMonth)
+
+		asset, err := NewAsset(AssetTypeAudience, "Legacy", data)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"gender":"Female","birth_country":"GB","hours_social_daily":{"gte":2.5,"lte":2.5},"purchases_last_month":{"gte":5,"lte":5}}`, string(asset.Data))
+	})
+
+	t.Run("nesting depth is bounded", func(t *testing.T) {
+		raw := `{"gender":"Male"}`
+		for i := 0; i <= maxAudienceDepth; i++ {
+			raw = `{"all":[` + raw + `]}`
+		}
+		_, err := ParseAudienceData(json.RawMessage(raw))
+		assert.ErrorIs(t, err, ErrInvalidAudienceData)
+	})
+}
+
+func TestAudienceData_Matches(t *testing.T) {
+	// Males aged 25-34 that spend more than 3 hours on social media daily,
+	// and were either born in GB or purchased at least 5 times last month
+	audience := AudienceData{
+		Gender:           GenderMale,
+		AgeGroups:        []AgeGroup{"25-34"},
+		HoursSocialDaily: MoreThan(3),
+		Any: []AudienceData{
+			{BirthCountry: "GB"},
+			{PurchasesLastMonth: AtLeast(5)},
+		},
+	}
+	require.NoError(t, audience.Validate())
+
+	base := RespondentProfile{
+		Gender:             GenderMale,
+		BirthCountry:       "GB",
+		Age:                29,
+		HoursSocialDaily:   3.5,
+		PurchasesLastMonth: 0,
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *RespondentProfile)
+		want   bool
+	}{
+		{name: "all criteria met", modify: func(p *RespondentProfile) {}, want: true},
+		{name: "wrong gender", modify: func(p *RespondentProfile) { p.Gender = GenderFemale }, want: false},
+		{name: "outside age group", modify: func(p *RespondentProfile) { p.Age = 40 }, want: false},
+		{name: "exactly 3 hours is not more than 3", modify: func(p *RespondentProfile) { p.HoursSocialDaily = 3 }, want: false},
+		{name: "OR group via purchases", modify: func(p *RespondentProfile) { p.BirthCountry = "US"; p.PurchasesLastMonth = 7 }, want: true},
+		{name: "OR group unmet", modify: func(p *RespondentProfile) { p.BirthCountry = "US"; p.PurchasesLastMonth = 2 }, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := base
+			tt.modify(&profile)
+			require.NoError(t, profile.Validate())
+			assert.Equal(t, tt.want, audience.Matches(&profile))
+		})
+	}
+}
+
+func TestRespondentProfile_Validate(t *testing.T) {
+	valid := RespondentProfile{Gender: GenderFemale, BirthCountry: "FR", Age: 30, HoursSocialDaily: 2}
+	require.NoError(t, valid.Validate())
+
+	invalid := []func(p *RespondentProfile){
+		func(p *RespondentProfile) { p.Gender = "" },
+		func(p *RespondentProfile) { p.BirthCountry = "ZZ" },
+		func(p *RespondentProfile) { p.Age = -1 },
+		func(p *RespondentProfile) { p.HoursSocialDaily = 25 },
+		func(p *RespondentProfile) { p.PurchasesLastMonth = -3 },
+	}
+	for _, modify := range invalid {
+		profile := valid
+		modify(&profile)
+		assert.ErrorIs(t, profile.Validate(), ErrInvalidRespondentProfile)
+	}
+}
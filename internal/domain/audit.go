@@ -0,0 +1,231 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is the kind of change an audit entry records
+type AuditAction string
+
+const (
+	AuditAssetCreate      AuditAction = "asset.create"
+	AuditAssetUpdate      AuditAction = "asset.update"
+	AuditAssetTag         AuditAction = "asset.tag"
+	AuditAssetUntag       AuditAction = "asset.untag"
+	AuditAssetDelete      AuditAction = "asset.delete"
+	AuditFavouriteAdd     AuditAction = "favourite.add"
+	AuditFavouriteRemove  AuditAction = "favourite.remove"
+	AuditFavouritesAdd    AuditAction = "favourite.batch_add"
+	AuditFavouritesRemove AuditAction = "favourite.batch_remove"
+	AuditFavouriteUpdate  AuditAction = "favourite.update"
+	AuditFavouriteMove    AuditAction = "favourite.move"
+	AuditFavouritePin     AuditAction = "favourite.pin"
+	AuditFavouriteUnpin   AuditAction = "favourite.unpin"
+	AuditCollectionCreate AuditAction = "collection.create"
+	AuditCollectionRename AuditAction = "collection.rename"
+	AuditCollectionDelete AuditAction = "collection.delete"
+	AuditCollectionAdd    AuditAction = "collection.add"
+	AuditCollectionRemove AuditAction = "collection.remove"
+	AuditShareCreate      AuditAction = "share.create"
+	AuditShareRedeem      AuditAction = "share.redeem"
+	AuditShareRevoke      AuditAction = "share.revoke"
+	AuditWebhookCreate    AuditAction = "webhook.create"
+	AuditWebhookDelete    AuditAction = "webhook.delete"
+	AuditWebhookRedeliver AuditAction = "webhook.redeliver"
+)
+
+const (
+	DefaultAuditLimit = 50
+	MaxAuditLimit     = 1000
+	// AuditGenesisHash is the PrevHash of the first entry
+	AuditGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+	// MaxRequestIDLength bounds the request IDs taken from clients
+	MaxRequestIDLength = 128
+)
+
+// AuditTarget identifies a resource an audited change applies to
+type AuditTarget struct {
+	Type string    `json:"type" example:"favourite"` // asset, user, favourite, collection, share, webhook or delivery
+	ID   uuid.UUID `json:"id"`
+}
+
+// AuditEntry records a change: who made it, in which request, to what, and the values before and
+// after it. Entries are numbered in the order they were appended in and chained by hash: each
+// entry's Hash covers its content and the Hash of the entry before, so that altering, removing or
+// reordering entries breaks the chain from there on.
+type AuditEntry struct {
+	Seq        uint64          `json:"seq" example:"42"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	ActorID    *uuid.UUID      `json:"actor_id,omitempty"` // the authenticated user; none when authentication is disabled
+	RequestID  string          `json:"request_id,omitempty" example:"6f1c2d1e-8a9b-4c3d-9e0f-1a2b3c4d5e6f"`
+	Action     AuditAction     `json:"action" example:"favourite.add"`
+	Targets    []AuditTarget   `json:"targets"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"` // the value changed, if it existed
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`  // the value made, unless removed
+	PrevHash   string          `json:"prev_hash" example:"0000000000000000000000000000000000000000000000000000000000000000"`
+	Hash       string          `json:"hash" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
+
+// NewAuditEntry creates an entry of a change made by an actor, with snapshots of the values before
+// and after it; nil values are left out
+func NewAuditEntry(actor Actor, action AuditAction, targets []AuditTarget, before, after interface{}) *AuditEntry {
+	return &AuditEntry{
+		OccurredAt: time.Now().UTC(),
+		ActorID:    actor.UserID,
+		RequestID:  actor.RequestID,
+		Action:     action,
+		Targets:    targets,
+		Before:     snapshot(before),
+		After:      snapshot(after),
+	}
+}
+
+// snapshot freezes a value as JSON, so that later changes to it do not alter the entry. Entries are
+// appended along with their change, which must not fail for want of a snapshot: a value that cannot
+// be encoded is recorded as the reason why.
+func snapshot(value interface{}) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"unrecorded": err.Error()})
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	return data
+}
+
+// Seal numbers the entry and chains it to the hash of the entry before
+func (e *AuditEntry) Seal(seq uint64, prevHash string) {
+	e.Seq = seq
+	e.PrevHash = prevHash
+	e.Hash = e.digest()
+}
+
+// digest hashes the entry's content, including its number and PrevHash but not its Hash
+func (e *AuditEntry) digest() string {
+	content := *e
+	content.Hash = ""
+	data, _ := json.Marshal(content) // cannot fail: snapshots are valid JSON
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditChain checks consecutive entries against the chain, starting from the hash of the entry
+// before the first one (AuditGenesisHash for the first entry), and returns the hash of the last one.
+// Errors are ErrDataIntegrity, telling the first entry off the chain.
+func VerifyAuditChain(prevHash string, entries []*AuditEntry) (string, error) {
+	for _, entry := range entries {
+		if entry.PrevHash != prevHash {
+			return "", fmt.Errorf("%w: audit entry %d does not follow the entry before", ErrDataIntegrity, entry.Seq)
+		}
+		if entry.Hash != entry.digest() {
+			return "", fmt.Errorf("%w: audit entry %d does not match its hash", ErrDataIntegrity, entry.Seq)
+		}
+		prevHash = entry.Hash
+	}
+	return prevHash, nil
+}
+
+// AuditVerification reports the outcome of checking the whole audit trail
+type AuditVerification struct {
+	Valid    bool   `json:"valid"`
+	Entries  int    `json:"entries" example:"42"` // entries checked
+	HeadHash string `json:"head_hash"`            // hash of the last entry checked
+	BrokenAt uint64 `json:"broken_at,omitempty"`  // first entry off the chain, if any
+	Error    string `json:"error,omitempty"`      // why the chain is broken
+}
+
+// AuditQuery selects audit entries, oldest first. Filters left zero match every entry; entries are
+// read from just after AfterSeq, at most Limit of them.
+type AuditQuery struct {
+	ActorID   *uuid.UUID
+	Action    AuditAction // an action, or a resource type such as "favourite" for all of its actions
+	TargetID  *uuid.UUID  // an ID among the entry's targets
+	RequestID string
+	Since     time.Time // inclusive
+	Until     time.Time // exclusive
+	AfterSeq  uint64
+	Limit     int
+}
+
+// NewAuditQuery creates a query reading from after a sequence number, applying the default limit
+// and rejecting invalid ones with ErrInvalidAuditQuery
+func NewAuditQuery(afterSeq uint64, limit int) (*AuditQuery, error) {
+	if limit == 0 {
+		limit = DefaultAuditLimit
+	}
+	if limit < 0 || limit > MaxAuditLimit {
+		return nil, InvalidField(ErrInvalidAuditQuery, "limit", "limit must be between 1 and %d", MaxAuditLimit)
+	}
+	return &AuditQuery{AfterSeq: afterSeq, Limit: limit}, nil
+}
+
+// Validate checks the query's filters
+func (q *AuditQuery) Validate() error {
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return InvalidField(ErrInvalidAuditQuery, "since", "since must be before until")
+	}
+	if len(q.RequestID) > MaxRequestIDLength {
+		return InvalidField(ErrInvalidAuditQuery, "request_id", "request ID longer than %d characters", MaxRequestIDLength)
+	}
+	return nil
+}
+
+// Matches tells whether an entry passes the query's filters
+func (q *AuditQuery) Matches(entry *AuditEntry) bool {
+	if q.ActorID != nil && (entry.ActorID == nil || *entry.ActorID != *q.ActorID) {
+		return false
+	}
+	if q.Action != "" && entry.Action != q.Action && !strings.HasPrefix(string(entry.Action), string(q.Action)+".") {
+		return false
+	}
+	if q.RequestID != "" && entry.RequestID != q.RequestID {
+		return false
+	}
+	if !q.Since.IsZero() && entry.OccurredAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !entry.OccurredAt.Before(q.Until) {
+		return false
+	}
+	if q.TargetID != nil {
+		for _, target := range entry.Targets {
+			if target.ID == *q.TargetID {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Actor tells who makes changes, and in which request, for the audit trail
+type Actor struct {
+	UserID    *uuid.UUID
+	RequestID string
+}
+
+type actorKey struct{}
+
+// WithActor returns a context carrying the actor of the changes made with it
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by a context; changes made without one are audited
+// as anonymous
+func ActorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorKey{}).(Actor)
+	return actor
+}
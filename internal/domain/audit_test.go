@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditChain seals entries one after the other, as the audit trail appends them
+func auditChain(entries ...*AuditEntry) []*AuditEntry {
+	prevHash := AuditGenesisHash
+	for i, entry := range entries {
+		entry.Seal(uint64(i+1), prevHash)
+		prevHash = entry.Hash
+	}
+	return entries
+}
+
+func TestAuditEntry_Chain(t *testing.T) {
+	userID, assetID := uuid.New(), uuid.New()
+	asset := &Asset{ID: assetID, Type: AssetTypeInsight, Description: "Before"}
+	actor := Actor{UserID: &userID, RequestID: "req-1"}
+	entry := NewAuditEntry(actor, AuditAssetUpdate, []AuditTarget{{Type: "asset", ID: assetID}}, asset, nil)
+	asset.Description = "After"
+	assert.JSONEq(t, `"Before"`, string(mustField(t, entry.Before, "description")), "snapshots do not follow later changes")
+	assert.Nil(t, entry.After)
+	assert.Equal(t, &userID, entry.ActorID)
+	assert.Equal(t, "req-1", entry.RequestID)
+
+	var missing *Asset
+	entries := auditChain(entry, NewAuditEntry(Actor{}, AuditAssetDelete, nil, asset, missing))
+	assert.Nil(t, entries[1].After, "typed nil values are left out too")
+	assert.Equal(t, AuditGenesisHash, entries[0].PrevHash)
+	assert.Equal(t, entries[0].Hash, entries[1].PrevHash)
+	head, err := VerifyAuditChain(AuditGenesisHash, entries)
+	require.NoError(t, err)
+	assert.Equal(t, entries[1].Hash, head)
+
+	// The chain survives encoding, as served to clients
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	var decoded []*AuditEntry
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	_, err = VerifyAuditChain(AuditGenesisHash, decoded)
+	assert.NoError(t, err)
+
+	// Altering, removing or reordering entries breaks the chain
+	altered := *entries[0]
+	altered.Action = AuditAssetCreate
+	_, err = VerifyAuditChain(AuditGenesisHash, []*AuditEntry{&altered, entries[1]})
+	assert.ErrorIs(t, err, ErrDataIntegrity)
+	_, err = VerifyAuditChain(AuditGenesisHash, entries[1:])
+	assert.ErrorIs(t, err, ErrDataIntegrity)
+	_, err = VerifyAuditChain(AuditGenesisHash, []*AuditEntry{entries[1], entries[0]})
+	assert.ErrorIs(t, err, ErrDataIntegrity)
+	resealed := *entries[0]
+	resealed.Action = AuditAssetCreate
+	resealed.Seal(1, AuditGenesisHash)
+	_, err = VerifyAuditChain(AuditGenesisHash, []*AuditEntry{&resealed, entries[1]})
+	assert.ErrorIs(t, err, ErrDataIntegrity, "an entry sealed again no longer leads to the next one")
+}
+
+func mustField(t *testing.T, data json.RawMessage, name string) json.RawMessage {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &fields))
+	return fields[name]
+}
+
+func TestAuditQuery(t *testing.T) {
+	userID, assetID := uuid.New(), uuid.New()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entry := &AuditEntry{ActorID: &userID, RequestID: "req-1", Action: AuditFavouriteAdd, OccurredAt: at,
+		Targets: []AuditTarget{{Type: "user", ID: userID}, {Type: "asset", ID: assetID}}}
+	other := uuid.New()
+
+	for name, tc := range map[string]struct {
+		query   AuditQuery
+		matches bool
+	}{
+		"no filter":      {query: AuditQuery{}, matches: true},
+		"actor":          {query: AuditQuery{ActorID: &userID}, matches: true},
+		"other actor":    {query: AuditQuery{ActorID: &other}},
+		"action":         {query: AuditQuery{Action: AuditFavouriteAdd}, matches: true},
+		"resource type":  {query: AuditQuery{Action: "favourite"}, matches: true},
+		"other action":   {query: AuditQuery{Action: AuditFavouriteRemove}},
+		"action prefix":  {query: AuditQuery{Action: "fav"}},
+		"target":         {query: AuditQuery{TargetID: &assetID}, matches: true},
+		"other target":   {query: AuditQuery{TargetID: &other}},
+		"request":        {query: AuditQuery{RequestID: "req-1"}, matches: true},
+		"other request":  {query: AuditQuery{RequestID: "req-2"}},
+		"since":          {query: AuditQuery{Since: at}, matches: true},
+		"later":          {query: AuditQuery{Since: at.Add(time.Second)}},
+		"until":          {query: AuditQuery{Until: at.Add(time.Second)}, matches: true},
+		"until excluded": {query: AuditQuery{Until: at}},
+	} {
+		assert.Equal(t, tc.matches, tc.query.Matches(entry), name)
+	}
+	assert.False(t, (&AuditQuery{ActorID: &userID}).Matches(&AuditEntry{}), "anonymous entries have no actor")
+
+	query, err := NewAuditQuery(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, AuditQuery{AfterSeq: 10, Limit: DefaultAuditLimit}, *query)
+	_, err = NewAuditQuery(0, MaxAuditLimit+1)
+	assert.ErrorIs(t, err, ErrInvalidAuditQuery)
+	_, err = NewAuditQuery(0, -1)
+	assert.ErrorIs(t, err, ErrInvalidAuditQuery)
+	assert.ErrorIs(t, (&AuditQuery{Since: at, Until: at}).Validate(), ErrInvalidAuditQuery)
+	assert.NoError(t, (&AuditQuery{Since: at, Until: at.Add(time.Hour)}).Validate())
+}
+
+func TestActorFromContext(t *testing.T) {
+	assert.Equal(t, Actor{}, ActorFromContext(context.Background()))
+	userID := uuid.New()
+	actor := Actor{UserID: &userID, RequestID: "req-1"}
+	assert.Equal(t, actor, ActorFromContext(WithActor(context.Background(), actor)))
+}
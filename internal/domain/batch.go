@@ -0,0 +1,29 @@
+package domain
+
+import "github.com/google/uuid"
+
+// BatchStatus is the outcome of one item of a batch operation
+type BatchStatus string
+
+const (
+	BatchCreated       BatchStatus = "created"
+	BatchAlreadyExists BatchStatus = "already_exists"
+	BatchAssetNotFound BatchStatus = "asset_not_found"
+	BatchRemoved       BatchStatus = "removed"
+	BatchNotFound      BatchStatus = "not_found"
+)
+
+// BatchResult reports the outcome of a batch operation for one asset. Favourite is the favourite
+// created or removed, if any.
+type BatchResult struct {
+	AssetID   uuid.UUID   `json:"asset_id"`
+	Status    BatchStatus `json:"status"`
+	Favourite *Favourite  `json:"favourite,omitempty"`
+}
+
+// FavouriteStatus tells whether a user has favourited an asset, and under which favourite
+type FavouriteStatus struct {
+	AssetID     uuid.UUID  `json:"asset_id"`
+	Favourited  bool       `json:"favourited"`
+	FavouriteID *uuid.UUID `json:"favourite_id,omitempty"`
+}
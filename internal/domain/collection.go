@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxCollectionNameLength = 100
+
+// Collection is a user-owned folder grouping some of the user's favourites. A favourite may
+// belong to any number of collections; deleting a collection leaves its favourites in place.
+type Collection struct {
+	ID             uuid.UUID `json:"id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Name           string    `json:"name"`
+	FavouriteCount int       `json:"favourite_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// NewCollection creates a new, empty collection
+func NewCollection(userID uuid.UUID, name string) (*Collection, error) {
+	normalized, err := NormalizeCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &Collection{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      normalized,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// NormalizeCollectionName trims a collection name and collapses inner whitespace. Names must be
+// non-empty and at most 100 characters long; a user's collection names are unique regardless
+// of case (see CollectionNameKey).
+func NormalizeCollectionName(name string) (string, error) {
+	normalized := strings.Join(strings.Fields(name), " ")
+	if normalized == "" {
+		return "", InvalidField(ErrInvalidCollection, "name", "name cannot be empty")
+	}
+	if utf8.RuneCountInString(normalized) > maxCollectionNameLength {
+		return "", InvalidField(ErrInvalidCollection, "name", "name is longer than %d characters", maxCollectionNameLength)
+	}
+	return normalized, nil
+}
+
+// CollectionNameKey returns the key under which collection names are compared for uniqueness
+func CollectionNameKey(name string) string {
+	return strings.ToLower(name)
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCollection(t *testing.T) {
+	userID := uuid.New()
+
+	collection, err := NewCollection(userID, "  UK \t audiences ")
+	require.NoError(t, err)
+	assert.Equal(t, "UK audiences", collection.Name)
+	assert.Equal(t, userID, collection.UserID)
+	assert.NotEqual(t, uuid.Nil, collection.ID)
+	assert.Equal(t, collection.CreatedAt, collection.UpdatedAt)
+
+	// Length is counted in characters, not bytes
+	_, err = NewCollection(userID, strings.Repeat("é", 100))
+	assert.NoError(t, err)
+
+	for _, name := range []string{"", " \n ", strings.Repeat("a", 101)} {
+		_, err := NewCollection(userID, name)
+		assert.ErrorIs(t, err, ErrInvalidCollection)
+	}
+}
+
+func TestCollectionNameKey(t *testing.T) {
+	assert.Equal(t, CollectionNameKey("Q4 Review"), CollectionNameKey("q4 review"))
+	assert.NotEqual(t, CollectionNameKey("Q4 review"), CollectionNameKey("Q3 review"))
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/config"
+	"github.com/google/uuid"
+)
+
+// CursorDirection tells whether a cursor fetches the page after or before its position
+type CursorDirection string
+
+const (
+	CursorNext CursorDirection = "next"
+	CursorPrev CursorDirection = "prev"
+)
+
+// Cursor marks a position in a sorted listing: the sort key and ID of the item at a page
+// boundary. Items are ordered by (key, ID), so positions stay stable when items are added or
+// removed between page fetches. Clients only see cursors as opaque, signed tokens.
+type Cursor struct {
+	SortBy    string          `json:"s"`
+	Order     string          `json:"o"`
+	Key       string          `json:"k"`
+	ID        uuid.UUID       `json:"i"`
+	Direction CursorDirection `json:"d"`
+}
+
+// PageInfo describes a page of results: the total number of matching items and the cursors
+// of the neighbouring pages, if any
+type PageInfo struct {
+	Total      int
+	NextCursor string
+	PrevCursor string
+}
+
+// EncodeCursor serializes and signs a cursor
+func EncodeCursor(c Cursor) string {
+	payload, _ := json.Marshal(c) // cannot fail for this struct
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(encoded))
+}
+
+// DecodeCursor verifies and parses a cursor token
+func DecodeCursor(token string) (*Cursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, signCursor(encoded)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidCursor)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidCursor)
+	}
+	if c.Direction != CursorNext && c.Direction != CursorPrev {
+		return nil, fmt.Errorf("%w: unknown direction %q", ErrInvalidCursor, c.Direction)
+	}
+	return &c, nil
+}
+
+func signCursor(encoded string) []byte {
+	mac := hmac.New(sha256.New, []byte(config.Get().CursorSecret))
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// AssetSortKey returns the key ordering an asset by the given sort field. Keys of the same
+// field compare lexicographically in sort order.
+func AssetSortKey(a *Asset, sortBy string) string {
+	switch sortBy {
+	case "type":
+		return string(a.Type)
+	case "description":
+		return a.Description
+	case "updated_at":
+		return TimeSortKey(a.UpdatedAt)
+	case "popularity":
+		return fmt.Sprintf("%010d", a.FavouriteCount)
+	default: // created_at
+		return TimeSortKey(a.CreatedAt)
+	}
+}
+
+// FavouriteSortKey returns the key ordering a favourite by the given sort field. Fields other
+// than created_at and position refer to the favourited asset, which must be attached.
+func FavouriteSortKey(f *Favourite, sortBy string) string {
+	switch sortBy {
+	case "position":
+		if f.Pinned {
+			return "0" + f.Position
+		}
+		return "1" + f.Position
+	case "type", "description", "updated_at":
+		return AssetSortKey(f.Asset, sortBy)
+	default: // created_at
+		return TimeSortKey(f.CreatedAt)
+	}
+}
+
+// TimeSortKey renders a timestamp as fixed width digits. Flipping the sign bit maps the signed
+// nanosecond range onto unsigned integers in the same order, so pre-1970 times sort correctly too.
+func TimeSortKey(t time.Time) string {
+	return fmt.Sprintf("%020d", uint64(t.UnixNano())^(1<<63))
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{SortBy: "description", Order: "asc", Key: "Sales", ID: uuid.New(), Direction: CursorPrev}
+
+	token := EncodeCursor(c)
+	decoded, err := DecodeCursor(token)
+	require.NoError(t, err)
+	assert.Equal(t, c, *decoded)
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	token := EncodeCursor(Cursor{SortBy: "created_at", Order: "desc", Key: "k", ID: uuid.New(), Direction: CursorNext})
+	payload, signature, _ := strings.Cut(token, ".")
+	forged := EncodeCursor(Cursor{SortBy: "created_at", Order: "desc", Key: "other", ID: uuid.New(), Direction: CursorNext})
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	for name, token := range map[string]string{
+		"empty":         "",
+		"no signature":  payload,
+		"bad encoding":  payload + ".%%%",
+		"swapped parts": forgedPayload + "." + signature,
+		"bad direction": EncodeCursor(Cursor{Direction: "sideways"}),
+		"truncated mac": payload + "." + signature[:10],
+		"garbage":       "not-a-cursor.at-all",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := DecodeCursor(token)
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestPageQuery_SetCursor(t *testing.T) {
+	id := uuid.New()
+	query := NewPageQuery(10, 30, "created_at", "desc")
+	require.NoError(t, query.SetCursor(""))
+	assert.Nil(t, query.Cursor)
+	assert.Equal(t, 30, query.Offset)
+
+	// A cursor brings its own sort and replaces the offset
+	require.NoError(t, query.SetCursor(EncodeCursor(Cursor{SortBy: "type", Order: "asc", Key: "chart", ID: id, Direction: CursorNext})))
+	require.NotNil(t, query.Cursor)
+	assert.Equal(t, "type", query.SortBy)
+	assert.Equal(t, "asc", query.Order)
+	assert.Equal(t, 0, query.Offset)
+	assert.Equal(t, id, query.Cursor.ID)
+
+	assert.ErrorIs(t, query.SetCursor("bogus"), ErrInvalidCursor)
+}
+
+func TestTimeSortKey(t *testing.T) {
+	times := []time.Time{
+		time.Date(1950, 6, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(1969, 12, 31, 23, 59, 59, 999, time.UTC),
+		time.Unix(0, 0),
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 0, 0, 0, 1, time.UTC),
+	}
+
+	for i := 1; i < len(times); i++ {
+		prev, cur := TimeSortKey(times[i-1]), TimeSortKey(times[i])
+		assert.Len(t, cur, 20)
+		assert.Less(t, prev, cur, "%s should sort before %s", times[i-1], times[i])
+	}
+}
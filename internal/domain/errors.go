@@ -1,17 +1,93 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Error is a domain error. Its code is machine-readable and stable, so that clients act upon it
+// rather than upon the message, which may change.
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
 
 // Domain-level errors
 var (
-	ErrNotFound            = errors.New("resource not found")
-	ErrAlreadyExists       = errors.New("resource already exists")
-	ErrInvalidAssetType    = errors.New("invalid asset type")
-	ErrMissingAssetData    = errors.New("missing asset data")
-	ErrInvalidChartData    = errors.New("invalid chart data")
-	ErrInvalidInsightData  = errors.New("invalid insight data")
-	ErrInvalidAudienceData = errors.New("invalid audience data")
-	ErrUnauthorized        = errors.New("unauthorized")
-	ErrForbidden           = errors.New("forbidden")
-	ErrDataIntegrity       = errors.New("data integrity error")
+	ErrNotFound                 = newError("not_found", "resource not found")
+	ErrAlreadyExists            = newError("already_exists", "resource already exists")
+	ErrInvalidAssetType         = newError("invalid_asset_type", "invalid asset type")
+	ErrMissingAssetData         = newError("missing_asset_data", "missing asset data")
+	ErrInvalidChartData         = newError("invalid_chart_data", "invalid chart data")
+	ErrInvalidInsightData       = newError("invalid_insight_data", "invalid insight data")
+	ErrInvalidAudienceData      = newError("invalid_audience_data", "invalid audience data")
+	ErrAssetTypeMismatch        = newError("asset_type_mismatch", "operation not supported for this asset type")
+	ErrInvalidRespondentProfile = newError("invalid_respondent_profile", "invalid respondent profile")
+	ErrInvalidRenderOptions     = newError("invalid_render_options", "invalid render options")
+	ErrInvalidReference         = newError("invalid_reference", "invalid asset reference")
+	ErrAssetReferenced          = newError("asset_referenced", "asset is referenced by other assets")
+	ErrInvalidTag               = newError("invalid_tag", "invalid tag")
+	ErrInvalidSearchQuery       = newError("invalid_search_query", "invalid search query")
+	ErrInvalidCursor            = newError("invalid_cursor", "invalid cursor")
+	ErrInvalidFilter            = newError("invalid_filter", "invalid filter")
+	ErrInvalidFields            = newError("invalid_fields", "invalid field selection")
+	ErrInvalidCollection        = newError("invalid_collection", "invalid collection")
+	ErrInvalidPosition          = newError("invalid_position", "invalid position")
+	ErrInvalidAnnotation        = newError("invalid_annotation", "invalid favourite annotation")
+	ErrInvalidBatch             = newError("invalid_batch", "invalid batch")
+	ErrInvalidShare             = newError("invalid_share", "invalid share")
+	ErrInvalidWindow            = newError("invalid_window", "invalid trending window")
+	ErrInvalidWebhook           = newError("invalid_webhook", "invalid webhook")
+	ErrInvalidAuditQuery        = newError("invalid_audit_query", "invalid audit query")
+	ErrUnauthorized             = newError("unauthorized", "unauthorized")
+	ErrForbidden                = newError("forbidden", "forbidden")
+	ErrDataIntegrity            = newError("data_integrity", "data integrity error")
 )
+
+// FieldViolation tells which field of a request is invalid, and why. Fields are named by their
+// JSON path within the value validated, e.g. "secret" or "audience.all[0].gender".
+type FieldViolation struct {
+	Field   string `json:"field" example:"secret"`
+	Message string `json:"message" example:"secret must be at least 16 characters"`
+}
+
+// FieldError is a domain error about invalid fields, which it wraps and tells apart
+type FieldError struct {
+	Err        error
+	Violations []FieldViolation
+}
+
+// InvalidField reports an invalid field as the given domain error, explained by the message
+func InvalidField(err error, field, format string, args ...interface{}) error {
+	return &FieldError{Err: err, Violations: []FieldViolation{{Field: field, Message: fmt.Sprintf(format, args...)}}}
+}
+
+func (e *FieldError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return e.Err.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldViolations returns the invalid fields an error reports, if any
+func FieldViolations(err error) []FieldViolation {
+	var fieldErr *FieldError
+	if errors.As(err, &fieldErr) {
+		return fieldErr.Violations
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidField(t *testing.T) {
+	err := InvalidField(ErrInvalidCollection, "name", "name is longer than %d characters", 100)
+	assert.ErrorIs(t, err, ErrInvalidCollection)
+	var domainErr *Error
+	if assert.ErrorAs(t, err, &domainErr, "field errors carry the domain error they wrap") {
+		assert.Equal(t, "invalid_collection", domainErr.Code)
+	}
+	assert.EqualError(t, err, "invalid collection: name is longer than 100 characters", "messages read as before")
+	assert.Equal(t, []FieldViolation{{Field: "name", Message: "name is longer than 100 characters"}}, FieldViolations(err))
+
+	wrapped := fmt.Errorf("create: %w", err)
+	assert.Len(t, FieldViolations(wrapped), 1)
+	assert.Nil(t, FieldViolations(ErrInvalidCollection))
+}
+
+func TestValidationReportsFields(t *testing.T) {
+	err := (&AudienceData{All: []AudienceData{{Gender: "other"}}}).Validate()
+	assert.ErrorIs(t, err, ErrInvalidAudienceData)
+	if violations := FieldViolations(err); assert.Len(t, violations, 1) {
+		assert.Equal(t, "audience.all[0].gender", violations[0].Field)
+	}
+}
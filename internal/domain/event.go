@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventType is the kind of change an event reports
+type EventType string
+
+const (
+	EventFavouriteAdded   EventType = "favourite_added"
+	EventFavouriteRemoved EventType = "favourite_removed"
+	EventAssetCreated     EventType = "asset_created"
+	EventAssetUpdated     EventType = "asset_updated"
+	EventAssetDeleted     EventType = "asset_deleted"
+	// EventReset tells a resuming subscriber that events it missed are no longer available,
+	// so that it has to reload its state
+	EventReset EventType = "reset"
+)
+
+// Event is a change to a user's favourites or to an asset. Favourite events carry the user they
+// concern; asset events concern every user and carry none. Events are numbered in the order they
+// were recorded in, with the change, and keep their ID when handed again, so that those delivered
+// more than once can be told apart from new ones.
+type Event struct {
+	ID          uint64     `json:"id" example:"42"`
+	Type        EventType  `json:"type" example:"favourite_added"`
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	AssetID     *uuid.UUID `json:"asset_id,omitempty"`
+	FavouriteID *uuid.UUID `json:"favourite_id,omitempty"`
+	Favourite   *Favourite `json:"favourite,omitempty"`
+	Asset       *Asset     `json:"asset,omitempty"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+}
+
+// NewFavouriteEvent creates an event about a favourite of a user
+func NewFavouriteEvent(eventType EventType, fav *Favourite) Event {
+	return Event{
+		Type:        eventType,
+		UserID:      &fav.UserID,
+		AssetID:     &fav.AssetID,
+		FavouriteID: &fav.ID,
+		Favourite:   fav,
+	}
+}
+
+// NewAssetEvent creates an event about an asset; asset is nil for deleted assets
+func NewAssetEvent(eventType EventType, assetID uuid.UUID, asset *Asset) Event {
+	return Event{
+		Type:    eventType,
+		AssetID: &assetID,
+		Asset:   asset,
+	}
+}
+
+// VisibleTo tells whether an event concerns a user: events about the user's favourites, and about
+// the assets the follows function reports the user follows, e.g. those the user favourited
+func (e Event) VisibleTo(userID uuid.UUID, follows func(assetID uuid.UUID) bool) bool {
+	if e.UserID != nil {
+		return *e.UserID == userID
+	}
+	return e.AssetID != nil && follows(*e.AssetID)
+}
@@ -14,6 +14,18 @@ type Favourite struct {
 	AssetID   uuid.UUID `json:"asset_id"`
 	Asset     *Asset    `json:"asset,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// Title and Note are the user's own annotations, private to the favourite. The title is
+	// a display name to use instead of the asset's description.
+	Title string `json:"title,omitempty"`
+	Note  string `json:"note,omitempty"`
+
+	// Position orders the user's favourites manually (see PositionBetween); pinned favourites
+	// come before all others and are ordered among themselves by position
+	Position string `json:"position"`
+	Pinned   bool   `json:"pinned"`
+
+	CollectionIDs []uuid.UUID `json:"collection_ids,omitempty"` // collections the favourite belongs to
 }
 
 // NewFavourite creates a new favourite entry
@@ -30,8 +42,23 @@ func NewFavourite(userID, assetID uuid.UUID) *Favourite {
 type PageQuery struct {
 	Limit  int    // Number of results per page (max 1000)
 	Offset int    // Starting position
-	SortBy string // Field to sort by: "created_at", "type", "description"
+	SortBy string // Field to sort by: "created_at", "updated_at", "type", "description", "popularity" for assets or "position" for favourites
 	Order  string // Sort order: "asc" or "desc"
+
+	Tags     []string // Optional normalized tag filter
+	TagMatch TagMatch // How Tags are combined: TagMatchAll (default) or TagMatchAny
+
+	Filter *Filter // Optional filter; nil lists everything
+
+	CollectionID *uuid.UUID // Optional collection the listed favourites must belong to
+	AssetID      *uuid.UUID // Optional asset the listed favourites must be of (at most one per user)
+
+	// IncludeAsset asks for favourites to come with their assets attached. Repositories
+	// storing assets apart must then fetch them along with the page; ones for which
+	// attaching is free may always do so.
+	IncludeAsset bool
+
+	Cursor *Cursor // Optional keyset position; when set, Offset is ignored
 }
 
 // NewPageQuery creates a PageQuery with defaults
@@ -48,7 +75,11 @@ func NewPageQuery(limit, offset int, sortBy, order string) *PageQuery {
 		sortBy = "created_at"
 	}
 	if order != "asc" && order != "desc" {
+		// Manual order reads top to bottom; everything else defaults to newest first
 		order = "desc"
+		if sortBy == "position" {
+			order = "asc"
+		}
 	}
 
 	return &PageQuery{
@@ -58,3 +89,45 @@ func NewPageQuery(limit, offset int, sortBy, order string) *PageQuery {
 		Order:  order,
 	}
 }
+
+// SetCursor decodes a cursor token and positions the query on it. The cursor carries the sort
+// field and order of the listing it was issued for, which take precedence over the query's own.
+func (q *PageQuery) SetCursor(token string) error {
+	if token == "" {
+		return nil
+	}
+	cursor, err := DecodeCursor(token)
+	if err != nil {
+		return err
+	}
+	q.Cursor = cursor
+	q.SortBy = cursor.SortBy
+	q.Order = cursor.Order
+	q.Offset = 0
+	return nil
+}
+
+// SetFilter parses filter expressions for the given listing into the query
+func (q *PageQuery) SetFilter(exprs []string, target FilterTarget) error {
+	filter, err := ParseFilter(exprs, target)
+	if err != nil {
+		return err
+	}
+	q.Filter = filter
+	return nil
+}
+
+// SetTagFilter normalizes and sets the tag filter of the query
+func (q *PageQuery) SetTagFilter(tags []string, match string) error {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return err
+	}
+	mode, err := ParseTagMatch(match)
+	if err != nil {
+		return err
+	}
+	q.Tags = normalized
+	q.TagMatch = mode
+	return nil
+}
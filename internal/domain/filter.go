@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// FilterTarget selects the listing a filter is parsed for, which determines the fields it accepts
+type FilterTarget int
+
+const (
+	FilterAssets     FilterTarget = iota // type, created_at, updated_at, description
+	FilterFavourites                     // the asset fields plus favourited_at
+)
+
+// Filter restricts a listing. A filter expression is a comma separated list of clauses, all of
+// which must hold:
+//
+//	type:chart|insight         asset type is one of the given types
+//	created_at>=2025-10-01     asset creation time; operators : > >= < <=
+//	updated_at<2025-10-01T12:00:00Z
+//	description~"q4, sales"    description contains the text, ignoring case
+//	favourited_at:2025-10-17   favourite creation time (favourites only)
+//
+// Times are RFC 3339 timestamps or dates. A date stands for the whole (UTC) day, so
+// created_at:2025-10-17 matches that day and created_at<=2025-10-17 includes it. Values
+// containing commas can be double-quoted.
+type Filter struct {
+	Types        []AssetType // asset type is one of these, when non-nil
+	CreatedAt    TimeRange
+	UpdatedAt    TimeRange
+	Description  []string // lowercase substrings the description must all contain
+	FavouritedAt TimeRange
+}
+
+// TimeRange is a half-open interval [From, To); a zero bound leaves that side open
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// IsZero reports whether the range is unbounded on both sides
+func (r TimeRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Contains reports whether t lies within the range
+func (r TimeRange) Contains(t time.Time) bool {
+	return (r.From.IsZero() || !t.Before(r.From)) && (r.To.IsZero() || t.Before(r.To))
+}
+
+// narrow intersects the range with [from, to)
+func (r *TimeRange) narrow(from, to time.Time) {
+	if !from.IsZero() && (r.From.IsZero() || from.After(r.From)) {
+		r.From = from
+	}
+	if !to.IsZero() && (r.To.IsZero() || to.Before(r.To)) {
+		r.To = to
+	}
+}
+
+// MatchAsset reports whether an asset satisfies the asset clauses of the filter
+func (f *Filter) MatchAsset(a *Asset) bool {
+	if f.Types != nil && !slices.Contains(f.Types, a.Type) {
+		return false
+	}
+	if !f.CreatedAt.Contains(a.CreatedAt) || !f.UpdatedAt.Contains(a.UpdatedAt) {
+		return false
+	}
+	if len(f.Description) > 0 {
+		description := strings.ToLower(a.Description)
+		for _, text := range f.Description {
+			if !strings.Contains(description, text) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// MatchFavourite reports whether a favourite of the given asset satisfies the filter
+func (f *Filter) MatchFavourite(fav *Favourite, a *Asset) bool {
+	return f.FavouritedAt.Contains(fav.CreatedAt) && f.MatchAsset(a)
+}
+
+// ParseFilter parses filter expressions for a listing; several expressions are combined like
+// the clauses of a single one. It returns nil when there are no clauses.
+func ParseFilter(exprs []string, target FilterTarget) (*Filter, error) {
+	var f Filter
+	clauses := 0
+	for _, expr := range exprs {
+		parts, err := splitClauses(expr)
+		if err != nil {
+			return nil, err
+		}
+		for _, clause := range parts {
+			if err := f.parseClause(clause, target); err != nil {
+				return nil, err
+			}
+			clauses++
+		}
+	}
+	if clauses == 0 {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// splitClauses splits an expression on commas outside double quotes, dropping empty clauses
+func splitClauses(expr string) ([]string, error) {
+	var clauses []string
+	var current strings.Builder
+	quoted := false
+	flush := func() {
+		if clause := strings.TrimSpace(current.String()); clause != "" {
+			clauses = append(clauses, clause)
+		}
+		current.Reset()
+	}
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ',' && !quoted:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("%w: unterminated quote in %q", ErrInvalidFilter, expr)
+	}
+	flush()
+	return clauses, nil
+}
+
+// filterOperators are tried longest first, so that >= is not read as >
+var filterOperators = []string{">=", "<=", ":", "~", ">", "<"}
+
+func (f *Filter) parseClause(clause string, target FilterTarget) error {
+	end := strings.IndexFunc(clause, func(r rune) bool { return (r < 'a' || r > 'z') && r != '_' })
+	if end < 0 {
+		end = len(clause)
+	}
+	if end == 0 {
+		return fmt.Errorf("%w: clause %q does not start with a field name", ErrInvalidFilter, clause)
+	}
+	field, rest := clause[:end], strings.TrimLeft(clause[end:], " ")
+
+	var op string
+	for _, candidate := range filterOperators {
+		if strings.HasPrefix(rest, candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return fmt.Errorf("%w: clause %q has no operator", ErrInvalidFilter, clause)
+	}
+	value := strings.TrimSpace(rest[len(op):])
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	} else if strings.Contains(value, `"`) {
+		return fmt.Errorf("%w: misplaced quote in %q", ErrInvalidFilter, clause)
+	}
+	if value == "" {
+		return fmt.Errorf("%w: clause %q has no value", ErrInvalidFilter, clause)
+	}
+
+	switch field {
+	case "type":
+		return f.parseTypes(op, value)
+	case "description":
+		if op != "~" {
+			return fmt.Errorf("%w: description only supports ~", ErrInvalidFilter)
+		}
+		f.Description = append(f.Description, strings.ToLower(value))
+		return nil
+	case "created_at":
+		return parseTimeClause(&f.CreatedAt, field, op, value)
+	case "updated_at":
+		return parseTimeClause(&f.UpdatedAt, field, op, value)
+	case "favourited_at":
+		if target == FilterFavourites {
+			return parseTimeClause(&f.FavouritedAt, field, op, value)
+		}
+	}
+	return fmt.Errorf("%w: unknown field %q", ErrInvalidFilter, field)
+}
+
+func (f *Filter) parseTypes(op, value string) error {
+	if op != ":" {
+		return fmt.Errorf("%w: type only supports :", ErrInvalidFilter)
+	}
+	var types []AssetType
+	for _, name := range strings.Split(value, "|") {
+		t := AssetType(strings.ToLower(strings.TrimSpace(name)))
+		switch t {
+		case AssetTypeChart, AssetTypeInsight, AssetTypeAudience:
+		default:
+			return fmt.Errorf("%w: unknown asset type %q", ErrInvalidFilter, name)
+		}
+		if f.Types == nil || slices.Contains(f.Types, t) {
+			types = append(types, t)
+		}
+	}
+	if types == nil {
+		types = []AssetType{} // disjoint type clauses match nothing
+	}
+	f.Types = types
+	return nil
+}
+
+// parseTimeClause narrows r by a time comparison. The value covers an interval, a day for a
+// date or a single nanosecond for a timestamp, which each operator compares against.
+func parseTimeClause(r *TimeRange, field, op, value string) error {
+	var start, end time.Time
+	if day, err := time.Parse(time.DateOnly, value); err == nil {
+		start, end = day, day.AddDate(0, 0, 1)
+	} else if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		start, end = t, t.Add(time.Nanosecond)
+	} else {
+		return fmt.Errorf("%w: %s value %q is neither a date nor an RFC 3339 timestamp", ErrInvalidFilter, field, value)
+	}
+
+	switch op {
+	case ":":
+		r.narrow(start, end)
+	case ">=":
+		r.narrow(start, time.Time{})
+	case ">":
+		r.narrow(end, time.Time{})
+	case "<":
+		r.narrow(time.Time{}, start)
+	case "<=":
+		r.narrow(time.Time{}, end)
+	default:
+		return fmt.Errorf("%w: %s does not support %s", ErrInvalidFilter, field, op)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name   string
+		exprs  []string
+		target FilterTarget
+		want   *Filter
+	}{
+		{name: "empty", exprs: []string{"", " , "}, want: nil},
+		{
+			name:  "types",
+			exprs: []string{"type:Chart|insight"},
+			want:  &Filter{Types: []AssetType{AssetTypeChart, AssetTypeInsight}},
+		},
+		{
+			name:  "disjoint types",
+			exprs: []string{"type:chart", "type:audience"},
+			want:  &Filter{Types: []AssetType{}},
+		},
+		{
+			name:  "date covers the whole day",
+			exprs: []string{"created_at:2025-10-17"},
+			want:  &Filter{CreatedAt: TimeRange{From: day(17), To: day(18)}},
+		},
+		{
+			name:  "inclusive and exclusive bounds",
+			exprs: []string{"created_at>2025-10-01,created_at<=2025-10-17, updated_at >= 2025-10-03, updated_at<2025-10-05"},
+			want: &Filter{
+				CreatedAt: TimeRange{From: day(2), To: day(18)},
+				UpdatedAt: TimeRange{From: day(3), To: day(5)},
+			},
+		},
+		{
+			name:  "timestamps",
+			exprs: []string{"updated_at>=2025-10-17T12:30:00+02:00"},
+			want:  &Filter{UpdatedAt: TimeRange{From: time.Date(2025, 10, 17, 10, 30, 0, 0, time.UTC)}},
+		},
+		{
+			name:  "quoted description",
+			exprs: []string{`description~"Q4, Sales",description~region`},
+			want:  &Filter{Description: []string{"q4, sales", "region"}},
+		},
+		{
+			name:   "favourited_at for favourites",
+			exprs:  []string{"favourited_at<2025-10-17"},
+			target: FilterFavourites,
+			want:   &Filter{FavouritedAt: TimeRange{To: day(17)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFilter(tt.exprs, tt.target)
+			require.NoError(t, err)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want.Types, got.Types)
+			assert.Equal(t, tt.want.Description, got.Description)
+			for _, pair := range [][2]TimeRange{
+				{tt.want.CreatedAt, got.CreatedAt},
+				{tt.want.UpdatedAt, got.UpdatedAt},
+				{tt.want.FavouritedAt, got.FavouritedAt},
+			} {
+				assert.True(t, pair[0].From.Equal(pair[1].From), "from: want %s, got %s", pair[0].From, pair[1].From)
+				assert.True(t, pair[0].To.Equal(pair[1].To), "to: want %s, got %s", pair[0].To, pair[1].To)
+			}
+		})
+	}
+}
+
+func TestParseFilter_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"colour:red",
+		"favourited_at>2025-10-01", // assets have no favourite time
+		"type",
+		"type~chart",
+		"type:dashboard",
+		"description:sales",
+		"description~",
+		`description~"sales`,
+		`description~sa"les`,
+		"created_at~2025",
+		"created_at>yesterday",
+		">2025-10-01",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseFilter([]string{expr}, FilterAssets)
+			assert.ErrorIs(t, err, ErrInvalidFilter)
+		})
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	asset, err := NewAsset(AssetTypeChart, "Q4 Sales by Region", ChartData{Title: "Sales"})
+	require.NoError(t, err)
+	asset.CreatedAt = time.Date(2025, 10, 17, 23, 59, 0, 0, time.UTC)
+	asset.UpdatedAt = asset.CreatedAt
+	fav := NewFavourite(asset.ID, asset.ID)
+	fav.CreatedAt = time.Date(2025, 10, 18, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "type:chart|audience", want: true},
+		{expr: "type:insight", want: false},
+		{expr: "created_at:2025-10-17", want: true},
+		{expr: "created_at<=2025-10-16", want: false},
+		{expr: "created_at>2025-10-17T23:59:00Z", want: false},
+		{expr: "created_at>=2025-10-17T23:59:00Z", want: true},
+		{expr: "description~sales,description~REGION", want: true},
+		{expr: "description~revenue", want: false},
+		{expr: "favourited_at:2025-10-18", want: true},
+		{expr: "favourited_at<2025-10-18", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := ParseFilter([]string{tt.expr}, FilterFavourites)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.MatchFavourite(fav, asset))
+		})
+	}
+}
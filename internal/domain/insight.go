@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InsightData represents insight-specific data. Besides the free text, an insight may carry
+// a structured fact: a numeric value with its unit, where it comes from, the period it covers
+// and the audience it is about.
+type InsightData struct {
+	Text       string         `json:"text"`
+	Value      *float64       `json:"value,omitempty"`       // e.g. 40
+	Unit       string         `json:"unit,omitempty"`        // e.g. "%"
+	Source     string         `json:"source,omitempty"`      // e.g. "GWI Core Q4 2025"
+	Period     *InsightPeriod `json:"period,omitempty"`      // time span the fact refers to
+	AudienceID *uuid.UUID     `json:"audience_id,omitempty"` // audience asset the fact is about
+}
+
+// InsightPeriod is the time span an insight refers to. Either bound may be omitted.
+type InsightPeriod struct {
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// ParseInsightData decodes and validates raw insight asset data
+func ParseInsightData(raw json.RawMessage) (*InsightData, error) {
+	var data InsightData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("invalid insight data: %w", err)
+	}
+	if err := data.Validate(); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Validate checks that the insight is well formed. Whether the referenced audience exists
+// is checked by the repository when the asset is stored.
+func (d *InsightData) Validate() error {
+	if d.Text == "" {
+		return InvalidField(ErrInvalidInsightData, "text", "text is required")
+	}
+	if d.Value != nil && (math.IsNaN(*d.Value) || math.IsInf(*d.Value, 0)) {
+		return InvalidField(ErrInvalidInsightData, "value", "value must be a finite number")
+	}
+	if d.Unit != "" && d.Value == nil {
+		return InvalidField(ErrInvalidInsightData, "unit", "unit requires a value")
+	}
+	if d.Period != nil {
+		if d.Period.Start == nil && d.Period.End == nil {
+			return InvalidField(ErrInvalidInsightData, "period", "period needs a start or an end")
+		}
+		if d.Period.Start != nil && d.Period.End != nil && d.Period.End.Before(*d.Period.Start) {
+			return InvalidField(ErrInvalidInsightData, "period", "period ends before it starts")
+		}
+	}
+	if d.AudienceID != nil && *d.AudienceID == uuid.Nil {
+		return InvalidField(ErrInvalidInsightData, "audience_id", "audience_id cannot be the nil UUID")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInsightData(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{name: "text only", raw: `{"text":"Millennials love short videos"}`},
+		{
+			name: "structured fact",
+			raw: `{"text":"40% of millennials spend 3+ hours daily on social media","value":40,"unit":"%",
+				"source":"GWI Core","period":{"start":"2025-10-01T00:00:00Z","end":"2025-12-31T23:59:59Z"},
+				"audience_id":"3fa85f64-5717-4562-b3fc-2c963f66afa6"}`,
+		},
+		{name: "open ended period", raw: `{"text":"t","period":{"start":"2025-10-01T00:00:00Z"}}`},
+		{name: "missing text", raw: `{"value":40}`, wantErr: true},
+		{name: "unit without value", raw: `{"text":"t","unit":"%"}`, wantErr: true},
+		{name: "empty period", raw: `{"text":"t","period":{}}`, wantErr: true},
+		{
+			name:    "period ends before it starts",
+			raw:     `{"text":"t","period":{"start":"2025-12-31T00:00:00Z","end":"2025-10-01T00:00:00Z"}}`,
+			wantErr: true,
+		},
+		{name: "nil audience", raw: `{"text":"t","audience_id":"00000000-0000-0000-0000-000000000000"}`, wantErr: true},
+		{name: "malformed audience", raw: `{"text":"t","audience_id":"not-a-uuid"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseInsightData(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAsset_References(t *testing.T) {
+	audienceID := uuid.New()
+
+	insight, err := NewAsset(AssetTypeInsight, "Insight", InsightData{Text: "t", AudienceID: &audienceID})
+	require.NoError(t, err)
+	assert.Equal(t, []AssetReference{{AssetID: audienceID, Type: AssetTypeAudience}}, insight.References())
+
+	plain, err := NewAsset(AssetTypeInsight, "Insight", InsightData{Text: "t"})
+	require.NoError(t, err)
+	assert.Empty(t, plain.References())
+
+	audience, err := NewAsset(AssetTypeAudience, "Audience", AudienceData{Gender: GenderFemale})
+	require.NoError(t, err)
+	assert.Empty(t, audience.References())
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// TrendingBucket is the granularity at which favourite activity is counted. Trending windows
+	// start at the beginning of the bucket they fall into.
+	TrendingBucket = time.Hour
+	// MaxTrendingWindow is how far back favourite activity is kept
+	MaxTrendingWindow = 30 * 24 * time.Hour
+	// DefaultTrendingWindow is the window used when none is given
+	DefaultTrendingWindow = 7 * 24 * time.Hour
+)
+
+// TrendingAsset reports how often an asset was favourited (Added) and unfavourited (Removed)
+// within a time window, with Net = Added - Removed
+type TrendingAsset struct {
+	Asset   *Asset `json:"asset"`
+	Added   int    `json:"added" example:"12"`
+	Removed int    `json:"removed" example:"2"`
+	Net     int    `json:"net" example:"10"`
+}
+
+// ParseTrendingWindow parses a trending window given in days ("7d") or hours ("24h"). Windows
+// must be at least one bucket and at most MaxTrendingWindow long; an empty window stands for
+// DefaultTrendingWindow.
+func ParseTrendingWindow(s string) (time.Duration, error) {
+	if s == "" {
+		return DefaultTrendingWindow, nil
+	}
+	var unit time.Duration
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "h"):
+		unit = time.Hour
+	default:
+		return 0, fmt.Errorf("%w: %q is not a number of days (7d) or hours (24h)", ErrInvalidWindow, s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a number of days (7d) or hours (24h)", ErrInvalidWindow, s)
+	}
+	window := time.Duration(n) * unit
+	if window < TrendingBucket || window > MaxTrendingWindow {
+		return 0, fmt.Errorf("%w: must be between 1h and %dd", ErrInvalidWindow, MaxTrendingWindow/(24*time.Hour))
+	}
+	return window, nil
+}
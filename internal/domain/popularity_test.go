@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrendingWindow(t *testing.T) {
+	for s, want := range map[string]time.Duration{
+		"":    DefaultTrendingWindow,
+		"7d":  7 * 24 * time.Hour,
+		"24h": 24 * time.Hour,
+		"1h":  time.Hour,
+		"30d": MaxTrendingWindow,
+	} {
+		got, err := ParseTrendingWindow(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, got, s)
+	}
+
+	for _, s := range []string{"7", "d", "7w", "1.5d", "-1d", "0h", "31d", "721h"} {
+		_, err := ParseTrendingWindow(s)
+		assert.ErrorIs(t, err, ErrInvalidWindow, s)
+	}
+}
+
+func TestAssetSortKey_Popularity(t *testing.T) {
+	popular, rare := &Asset{FavouriteCount: 10}, &Asset{FavouriteCount: 9}
+	assert.Less(t, AssetSortKey(rare, "popularity"), AssetSortKey(popular, "popularity"))
+}
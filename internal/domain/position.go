@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Positions order a user's favourites manually. They are fractional index keys: strings that
+// sort lexicographically, where a key can always be generated between any two others, so that
+// moving an item only rewrites that item. A key consists of an integer part, whose first
+// character encodes its length, and an optional fraction that never ends in the zero digit.
+// See https://observablehq.com/@dgreensp/implementing-fractional-indexing for the scheme.
+
+const positionDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// smallestInteger is the lowest integer part; keys with it need a fraction to stay above it
+var smallestInteger = "A" + strings.Repeat("0", 26)
+
+// PositionBetween returns a position sorting strictly between a and b. An empty a stands for
+// the start of the list and an empty b for its end.
+func PositionBetween(a, b string) (string, error) {
+	if a != "" {
+		if err := validatePosition(a); err != nil {
+			return "", err
+		}
+	}
+	if b != "" {
+		if err := validatePosition(b); err != nil {
+			return "", err
+		}
+	}
+	if a != "" && b != "" && a >= b {
+		return "", fmt.Errorf("%w: %q is not before %q", ErrInvalidPosition, a, b)
+	}
+
+	switch {
+	case a == "" && b == "":
+		return "a0", nil
+	case a == "":
+		ib := integerPart(b)
+		if ib == smallestInteger {
+			return ib + midpoint("", b[len(ib):]), nil
+		}
+		if ib < b {
+			return ib, nil
+		}
+		if i, ok := decrementInteger(ib); ok {
+			return i, nil
+		}
+		return "", fmt.Errorf("%w: no position left before %q", ErrInvalidPosition, b)
+	case b == "":
+		ia := integerPart(a)
+		if i, ok := incrementInteger(ia); ok {
+			return i, nil
+		}
+		return ia + midpoint(a[len(ia):], ""), nil
+	}
+
+	ia, ib := integerPart(a), integerPart(b)
+	if ia == ib {
+		return ia + midpoint(a[len(ia):], b[len(ib):]), nil
+	}
+	i, ok := incrementInteger(ia)
+	if !ok {
+		return "", fmt.Errorf("%w: no position left after %q", ErrInvalidPosition, a)
+	}
+	if i < b {
+		return i, nil
+	}
+	return ia + midpoint(a[len(ia):], ""), nil
+}
+
+// validatePosition checks that a string is a well-formed position
+func validatePosition(key string) error {
+	if key == smallestInteger {
+		return fmt.Errorf("%w: %q", ErrInvalidPosition, key)
+	}
+	n, ok := integerLength(key[0])
+	if !ok || n > len(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidPosition, key)
+	}
+	for i := 1; i < len(key); i++ {
+		if strings.IndexByte(positionDigits, key[i]) < 0 {
+			return fmt.Errorf("%w: %q", ErrInvalidPosition, key)
+		}
+	}
+	if len(key) > n && key[len(key)-1] == positionDigits[0] {
+		return fmt.Errorf("%w: %q", ErrInvalidPosition, key)
+	}
+	return nil
+}
+
+// integerLength returns the length of the integer part starting with head: a-z for lengths
+// 2-27 of positive integers, Z-A for lengths 2-27 of negative ones
+func integerLength(head byte) (int, bool) {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2, true
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2, true
+	}
+	return 0, false
+}
+
+func integerPart(key string) string {
+	n, _ := integerLength(key[0])
+	return key[:n]
+}
+
+// incrementInteger returns the next integer part, or false past the largest one
+func incrementInteger(x string) (string, bool) {
+	head, digits := x[0], []byte(x[1:])
+	carry := true
+	for i := len(digits) - 1; carry && i >= 0; i-- {
+		d := strings.IndexByte(positionDigits, digits[i]) + 1
+		if d == len(positionDigits) {
+			digits[i] = positionDigits[0]
+		} else {
+			digits[i] = positionDigits[d]
+			carry = false
+		}
+	}
+	if !carry {
+		return string(head) + string(digits), true
+	}
+	switch head {
+	case 'Z':
+		return "a" + positionDigits[:1], true
+	case 'z':
+		return "", false
+	}
+	head++
+	if head > 'a' {
+		digits = append(digits, positionDigits[0])
+	} else {
+		digits = digits[:len(digits)-1]
+	}
+	return string(head) + string(digits), true
+}
+
+// decrementInteger returns the previous integer part, or false before the smallest one
+func decrementInteger(x string) (string, bool) {
+	last := positionDigits[len(positionDigits)-1]
+	head, digits := x[0], []byte(x[1:])
+	borrow := true
+	for i := len(digits) - 1; borrow && i >= 0; i-- {
+		d := strings.IndexByte(positionDigits, digits[i]) - 1
+		if d < 0 {
+			digits[i] = last
+		} else {
+			digits[i] = positionDigits[d]
+			borrow = false
+		}
+	}
+	if !borrow {
+		return string(head) + string(digits), true
+	}
+	switch head {
+	case 'a':
+		return "Z" + string(last), true
+	case 'A':
+		return "", false
+	}
+	head--
+	if head < 'Z' {
+		digits = append(digits, last)
+	} else {
+		digits = digits[:len(digits)-1]
+	}
+	return string(head) + string(digits), true
+}
+
+// midpoint returns a fraction between fractions a and b, where an empty b stands for 1
+func midpoint(a, b string) string {
+	if b != "" {
+		// Strip the common prefix, padding a with zeros
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(sliceFrom(a, n), b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(positionDigits, a[0])
+	}
+	digitB := len(positionDigits)
+	if b != "" {
+		digitB = strings.IndexByte(positionDigits, b[0])
+	}
+	if digitB-digitA > 1 {
+		return string(positionDigits[(digitA+digitB+1)/2])
+	}
+	// The first digits are consecutive
+	if len(b) > 1 {
+		return b[:1]
+	}
+	return string(positionDigits[digitA]) + midpoint(sliceFrom(a, 1), "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return positionDigits[0]
+}
+
+func sliceFrom(s string, i int) string {
+	if i < len(s) {
+		return s[i:]
+	}
+	return ""
+}
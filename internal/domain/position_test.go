@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPositionBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", "a0"},
+		{"a0", "", "a1"},
+		{"", "a0", "Zz"},
+		{"a0", "a1", "a0V"},
+		{"a1", "a2", "a1V"},
+		{"a0V", "a1", "a0l"},
+		{"Zz", "a0", "ZzV"},
+		{"a0", "a0V", "a0G"},
+		{"a0", "a0G", "a08"},
+		{"b125", "b129", "b127"},
+		{"a0", "a4", "a1"},
+		{"az", "", "b00"},
+		{"Zz", "", "a0"},
+		{"", "b00", "az"},
+		{"a0", "a01", "a00V"},
+	}
+	for _, tt := range tests {
+		got, err := PositionBetween(tt.a, tt.b)
+		require.NoError(t, err, "between %q and %q", tt.a, tt.b)
+		assert.Equal(t, tt.want, got, "between %q and %q", tt.a, tt.b)
+	}
+}
+
+func TestPositionBetween_KeepsOrder(t *testing.T) {
+	// Repeated appends, prepends and inserts at the same spot all keep the keys ordered
+	keys := []string{}
+	last := ""
+	for i := 0; i < 1000; i++ {
+		key, err := PositionBetween(last, "")
+		require.NoError(t, err)
+		keys = append(keys, key)
+		last = key
+	}
+	first := keys[0]
+	for i := 0; i < 1000; i++ {
+		key, err := PositionBetween("", first)
+		require.NoError(t, err)
+		keys = append([]string{key}, keys...)
+		first = key
+	}
+	lo, hi := keys[0], keys[1]
+	for i := 0; i < 100; i++ {
+		key, err := PositionBetween(lo, hi)
+		require.NoError(t, err)
+		require.Less(t, lo, key)
+		require.Less(t, key, hi)
+		hi = key
+	}
+	for i := 1; i < len(keys); i++ {
+		require.Less(t, keys[i-1], keys[i])
+	}
+}
+
+func TestPositionBetween_Invalid(t *testing.T) {
+	for _, tc := range [][2]string{
+		{"a1", "a0"},          // out of order
+		{"a0", "a0"},          // equal
+		{"a10", ""},           // trailing zero in the fraction
+		{"b1", ""},            // integer part too short
+		{"a!", ""},            // not a digit
+		{"0a", ""},            // bad integer head
+		{"", smallestInteger}, // no room below it
+	} {
+		_, err := PositionBetween(tc[0], tc[1])
+		assert.ErrorIs(t, err, ErrInvalidPosition, "between %q and %q", tc[0], tc[1])
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RecommendationHalfLife is the age at which a favourite counts half as much towards the
+	// recommendations derived from it
+	RecommendationHalfLife = 90 * 24 * time.Hour
+	// CrossTypeWeight scales how much a favourite recommends assets of another type, e.g. an
+	// insight for a favourited chart
+	CrossTypeWeight = 0.5
+	// MaxRecommendationReasons bounds the favourited assets listed in Recommendation.BasedOn
+	MaxRecommendationReasons = 3
+)
+
+// Recommendation is an asset suggested to a user, with its score and the user's favourited
+// assets it was derived from, most influential first
+type Recommendation struct {
+	Asset   *Asset      `json:"asset"`
+	Score   float64     `json:"score" example:"0.42"`
+	BasedOn []uuid.UUID `json:"based_on"`
+}
+
+// CoFavouriteSimilarity returns the cosine similarity of two assets favourited by countA and
+// countB users, together by the given number of users. It is 1 for assets always favourited
+// together, and lower for assets whose popularity alone makes them co-occur.
+func CoFavouriteSimilarity(together, countA, countB int) float64 {
+	if together <= 0 || countA <= 0 || countB <= 0 {
+		return 0
+	}
+	return float64(together) / math.Sqrt(float64(countA)*float64(countB))
+}
+
+// RecencyWeight returns how much a favourite counts at a given time: 1 when just made, halving
+// with every RecommendationHalfLife of age
+func RecencyWeight(favouritedAt, at time.Time) float64 {
+	age := at.Sub(favouritedAt)
+	if age <= 0 {
+		return 1
+	}
+	return math.Exp2(-float64(age) / float64(RecommendationHalfLife))
+}
+
+// TypeWeight returns how much a favourite of one asset type recommends an asset of another type
+func TypeWeight(favourited, candidate AssetType) float64 {
+	if favourited == candidate {
+		return 1
+	}
+	return CrossTypeWeight
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoFavouriteSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, CoFavouriteSimilarity(3, 3, 3))
+	assert.InDelta(t, 0.5, CoFavouriteSimilarity(2, 4, 4), 1e-9)
+	assert.Less(t, CoFavouriteSimilarity(2, 100, 4), CoFavouriteSimilarity(2, 4, 4), "popular assets co-occur by chance")
+	assert.Zero(t, CoFavouriteSimilarity(0, 4, 4))
+	assert.Zero(t, CoFavouriteSimilarity(1, 0, 4))
+}
+
+func TestRecencyWeight(t *testing.T) {
+	at := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 1.0, RecencyWeight(at, at))
+	assert.Equal(t, 1.0, RecencyWeight(at.Add(time.Hour), at))
+	assert.InDelta(t, 0.5, RecencyWeight(at.Add(-RecommendationHalfLife), at), 1e-9)
+	assert.InDelta(t, 0.25, RecencyWeight(at.Add(-2*RecommendationHalfLife), at), 1e-9)
+}
+
+func TestTypeWeight(t *testing.T) {
+	assert.Equal(t, 1.0, TypeWeight(AssetTypeChart, AssetTypeChart))
+	assert.Equal(t, CrossTypeWeight, TypeWeight(AssetTypeChart, AssetTypeInsight))
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SharePermission is the access a share grants to the shared favourites
+type SharePermission string
+
+const (
+	// SharePermissionRead lets recipients list the shared favourites and copy them into their own
+	SharePermissionRead SharePermission = "read"
+	// SharePermissionEdit also lets recipients add and remove shared favourites on the owner's behalf
+	SharePermissionEdit SharePermission = "edit"
+)
+
+const shareTokenBytes = 24
+
+// Share grants other users access to a user's favourites, or to one of the user's collections
+// when CollectionID is set. Access is granted to the listed users, and to anyone redeeming the
+// share's link token if it has one. Deleting the share revokes all access, the link included.
+type Share struct {
+	ID           uuid.UUID       `json:"id"`
+	OwnerID      uuid.UUID       `json:"owner_id"`
+	CollectionID *uuid.UUID      `json:"collection_id,omitempty"`
+	Permission   SharePermission `json:"permission" example:"read"`
+	UserIDs      []uuid.UUID     `json:"user_ids,omitempty"`
+	Token        string          `json:"token,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+// NewShare creates a share of the owner's favourites, or of one of the owner's collections, with
+// the given users and, with link set, under a new link token. A share needs at least one user or
+// a link; the permission defaults to read.
+func NewShare(ownerID uuid.UUID, collectionID *uuid.UUID, userIDs []uuid.UUID, link bool, permission SharePermission) (*Share, error) {
+	switch permission {
+	case "":
+		permission = SharePermissionRead
+	case SharePermissionRead, SharePermissionEdit:
+	default:
+		return nil, InvalidField(ErrInvalidShare, "permission", "unknown permission %q", permission)
+	}
+
+	var grantees []uuid.UUID
+	for _, userID := range userIDs {
+		if userID == ownerID {
+			return nil, InvalidField(ErrInvalidShare, "user_ids", "cannot share with the owner")
+		}
+		if !slices.Contains(grantees, userID) {
+			grantees = append(grantees, userID)
+		}
+	}
+	if len(grantees) == 0 && !link {
+		return nil, fmt.Errorf("%w: share with at least one user or create a link", ErrInvalidShare)
+	}
+
+	share := &Share{
+		ID:           uuid.New(),
+		OwnerID:      ownerID,
+		CollectionID: collectionID,
+		Permission:   permission,
+		UserIDs:      grantees,
+		CreatedAt:    time.Now(),
+	}
+	if link {
+		token, err := newShareToken()
+		if err != nil {
+			return nil, err
+		}
+		share.Token = token
+	}
+	return share, nil
+}
+
+// GrantedTo tells whether the share gives a user access
+func (s *Share) GrantedTo(userID uuid.UUID) bool {
+	return slices.Contains(s.UserIDs, userID)
+}
+
+// CanEdit tells whether recipients may change the shared favourites
+func (s *Share) CanEdit() bool {
+	return s.Permission == SharePermissionEdit
+}
+
+// RecipientView returns a copy of the share as shown to its recipients, without the other
+// recipients and the link token, which only the owner gets to see
+func (s *Share) RecipientView() *Share {
+	view := *s
+	view.UserIDs = nil
+	view.Token = ""
+	return &view
+}
+
+// newShareToken returns a random, URL-safe link token
+func newShareToken() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generating share token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShare(t *testing.T) {
+	ownerID, userID := uuid.New(), uuid.New()
+
+	share, err := NewShare(ownerID, nil, []uuid.UUID{userID, userID}, false, "")
+	require.NoError(t, err)
+	assert.Equal(t, SharePermissionRead, share.Permission)
+	assert.Equal(t, []uuid.UUID{userID}, share.UserIDs)
+	assert.Empty(t, share.Token)
+	assert.True(t, share.GrantedTo(userID))
+	assert.False(t, share.GrantedTo(ownerID))
+	assert.False(t, share.CanEdit())
+
+	link, err := NewShare(ownerID, nil, nil, true, SharePermissionEdit)
+	require.NoError(t, err)
+	assert.True(t, link.CanEdit())
+	assert.Len(t, link.Token, 32)
+	other, err := NewShare(ownerID, nil, nil, true, SharePermissionEdit)
+	require.NoError(t, err)
+	assert.NotEqual(t, link.Token, other.Token)
+
+	view := link.RecipientView()
+	assert.Empty(t, view.Token)
+	assert.NotEmpty(t, link.Token, "the share itself is left untouched")
+
+	for name, tc := range map[string]struct {
+		userIDs    []uuid.UUID
+		link       bool
+		permission SharePermission
+	}{
+		"nobody":     {},
+		"owner":      {userIDs: []uuid.UUID{ownerID}},
+		"permission": {link: true, permission: "admin"},
+	} {
+		_, err := NewShare(ownerID, nil, tc.userIDs, tc.link, tc.permission)
+		assert.ErrorIs(t, err, ErrInvalidShare, name)
+	}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	maxTagLength    = 50
+	maxTagsPerAsset = 32
+)
+
+// TagMatch selects how a tag filter combines several tags
+type TagMatch string
+
+const (
+	TagMatchAll TagMatch = "all" // assets carrying every tag (AND)
+	TagMatchAny TagMatch = "any" // assets carrying at least one tag (OR)
+)
+
+// TagCount is the number of assets carrying a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// NormalizeTag lowercases a tag, trims it and joins inner whitespace with dashes. Tags may
+// contain letters, digits and the characters - _ : . only.
+func NormalizeTag(tag string) (string, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+	if normalized == "" {
+		return "", fmt.Errorf("%w: tag cannot be empty", ErrInvalidTag)
+	}
+	if len(normalized) > maxTagLength {
+		return "", fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTag, tag, maxTagLength)
+	}
+	for _, r := range normalized {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_', r == ':', r == '.':
+		default:
+			return "", fmt.Errorf("%w: %q contains invalid character %q", ErrInvalidTag, tag, r)
+		}
+	}
+	return normalized, nil
+}
+
+// NormalizeTags normalizes a list of tags, dropping duplicates. The result is sorted.
+func NormalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		n, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[n] {
+			seen[n] = true
+			normalized = append(normalized, n)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// ParseTagMatch parses a tag match mode, defaulting to TagMatchAll
+func ParseTagMatch(s string) (TagMatch, error) {
+	switch TagMatch(strings.ToLower(s)) {
+	case "", TagMatchAll:
+		return TagMatchAll, nil
+	case TagMatchAny:
+		return TagMatchAny, nil
+	default:
+		return "", fmt.Errorf("%w: unknown tag match mode %q", ErrInvalidTag, s)
+	}
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    string
+		wantErr bool
+	}{
+		{tag: "gen-z", want: "gen-z"},
+		{tag: "  Social   Media ", want: "social-media"},
+		{tag: "Q4:2025", want: "q4:2025"},
+		{tag: "", wantErr: true},
+		{tag: "   ", wantErr: true},
+		{tag: "#hashtag", wantErr: true},
+		{tag: "ünïcode", wantErr: true},
+		{tag: "a-very-long-tag-that-goes-on-and-on-and-on-for-ever-and-ever", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := NormalizeTag(tt.tag)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTag)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{"Sports", "gen-z", "sports", "GEN-Z"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gen-z", "sports"}, tags)
+
+	tags, err = NormalizeTags(nil)
+	require.NoError(t, err)
+	assert.Nil(t, tags)
+
+	_, err = NormalizeTags([]string{"ok", "not ok!"})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
+
+func TestParseTagMatch(t *testing.T) {
+	for input, want := range map[string]TagMatch{"": TagMatchAll, "all": TagMatchAll, "ANY": TagMatchAny} {
+		got, err := ParseTagMatch(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseTagMatch("some")
+	assert.ErrorIs(t, err, ErrInvalidTag)
+}
+
+func TestAsset_SetTags(t *testing.T) {
+	asset := &Asset{}
+	require.NoError(t, asset.SetTags([]string{"B", "a", "b"}))
+	assert.Equal(t, []string{"a", "b"}, asset.Tags)
+
+	tooMany := make([]string, maxTagsPerAsset+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+	assert.ErrorIs(t, asset.SetTags(tooMany), ErrInvalidTag)
+	assert.Equal(t, []string{"a", "b"}, asset.Tags, "tags are unchanged on error")
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/config"
+	"github.com/google/uuid"
+)
+
+const (
+	// MinWebhookSecretLength is the shortest secret accepted for signing webhook deliveries
+	MinWebhookSecretLength = 16
+	// MaxWebhookHistory bounds the completed deliveries kept per webhook; pending deliveries and
+	// dead letters are kept regardless
+	MaxWebhookHistory = 100
+)
+
+// WebhookEventTypes are the event types webhooks can subscribe to
+var WebhookEventTypes = []EventType{
+	EventAssetCreated, EventAssetUpdated, EventAssetDeleted, EventFavouriteAdded, EventFavouriteRemoved,
+}
+
+// Webhook subscribes a URL to events of the given types. Deliveries are signed with the secret,
+// which is never shown again once the webhook is created.
+type Webhook struct {
+	ID         uuid.UUID   `json:"id"`
+	URL        string      `json:"url" example:"https://analytics.example.com/hooks/gwi"`
+	EventTypes []EventType `json:"event_types" example:"asset_created,favourite_added"`
+	Secret     string      `json:"-"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// NewWebhook creates a webhook delivering events of the given types, at least one, to an
+// absolute http(s) URL. Unless configured otherwise, the URL may not name a loopback, private or
+// link-local address; host names are checked again as deliveries are dialled.
+func NewWebhook(rawURL string, eventTypes []EventType, secret string) (*Webhook, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, InvalidField(ErrInvalidWebhook, "url", "url must be an absolute http or https URL")
+	}
+	if !config.Get().WebhookAllowPrivate && !publicHost(parsed.Hostname()) {
+		return nil, InvalidField(ErrInvalidWebhook, "url", "url must not target a loopback, private or link-local address")
+	}
+	if len(secret) < MinWebhookSecretLength {
+		return nil, InvalidField(ErrInvalidWebhook, "secret", "secret must be at least %d characters", MinWebhookSecretLength)
+	}
+
+	var types []EventType
+	for _, eventType := range eventTypes {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			return nil, InvalidField(ErrInvalidWebhook, "event_types", "unknown event type %q", eventType)
+		}
+		if !slices.Contains(types, eventType) {
+			types = append(types, eventType)
+		}
+	}
+	if len(types) == 0 {
+		return nil, InvalidField(ErrInvalidWebhook, "event_types", "subscribe to at least one event type")
+	}
+
+	return &Webhook{
+		ID:         uuid.New(),
+		URL:        rawURL,
+		EventTypes: types,
+		Secret:     secret,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// PublicAddress tells whether webhooks may be delivered to an address: not a loopback, private,
+// link-local, multicast or unspecified one
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// publicHost tells whether a URL's host may be a webhook's, as far as can be told before
+// resolving it: localhost names and non-public addresses are not
+func publicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return PublicAddress(addr)
+	}
+	return true
+}
+
+// Subscribes tells whether the webhook receives events of a type
+func (w *Webhook) Subscribes(eventType EventType) bool {
+	return slices.Contains(w.EventTypes, eventType)
+}
+
+// DeliveryStatus is the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	// DeliveryPending deliveries are yet to be attempted, or to be retried
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliverySucceeded deliveries were acknowledged with a 2xx response
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	// DeliveryDead deliveries failed every attempt; they stay in the dead-letter list until redelivered
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+// DeliveryAttempt is one attempt at delivering an event, with the response status or the error
+type DeliveryAttempt struct {
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty" example:"503"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// WebhookDelivery is the delivery of an event to a webhook, with all its attempts. Redeliveries
+// counts how often a dead delivery was given a new round of attempts, and RoundAttempts the
+// attempts made in the current round.
+type WebhookDelivery struct {
+	ID            uuid.UUID         `json:"id"`
+	WebhookID     uuid.UUID         `json:"webhook_id"`
+	Event         Event             `json:"event"`
+	Status        DeliveryStatus    `json:"status" example:"pending"`
+	Attempts      []DeliveryAttempt `json:"attempts"`
+	Redeliveries  int               `json:"redeliveries"`
+	RoundAttempts int               `json:"round_attempts"`
+	NextAttemptAt *time.Time        `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// NewWebhookDelivery creates a pending delivery of an event to a webhook. Its ID is derived from
+// the webhook and the event's ID, so that an event handed again maps to the same delivery.
+func NewWebhookDelivery(webhookID uuid.UUID, event Event) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:        uuid.NewSHA1(webhookID, binary.BigEndian.AppendUint64(nil, event.ID)),
+		WebhookID: webhookID,
+		Event:     event,
+		Status:    DeliveryPending,
+		Attempts:  []DeliveryAttempt{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// WithAttempt returns a copy of the delivery with an attempt recorded and its resulting status;
+// nextAttemptAt is when a pending delivery is retried
+func (d *WebhookDelivery) WithAttempt(attempt DeliveryAttempt, status DeliveryStatus, nextAttemptAt *time.Time) *WebhookDelivery {
+	updated := *d
+	updated.Attempts = append(slices.Clip(d.Attempts), attempt)
+	updated.RoundAttempts++
+	updated.Status = status
+	updated.NextAttemptAt = nextAttemptAt
+	updated.UpdatedAt = attempt.At
+	return &updated
+}
+
+// Redelivered returns a copy of a dead delivery, pending again for a new round of attempts
+func (d *WebhookDelivery) Redelivered() (*WebhookDelivery, error) {
+	if d.Status != DeliveryDead {
+		return nil, fmt.Errorf("%w: only dead deliveries can be redelivered", ErrInvalidWebhook)
+	}
+	updated := *d
+	updated.Status = DeliveryPending
+	updated.Redeliveries++
+	updated.RoundAttempts = 0
+	updated.NextAttemptAt = nil
+	updated.UpdatedAt = time.Now()
+	return &updated, nil
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookSecret = "a-webhook-secret-for-tests"
+
+func TestNewWebhook(t *testing.T) {
+	hook, err := NewWebhook("https://example.com/hooks", []EventType{EventAssetCreated, EventFavouriteAdded, EventAssetCreated}, testWebhookSecret)
+	require.NoError(t, err)
+	assert.Equal(t, []EventType{EventAssetCreated, EventFavouriteAdded}, hook.EventTypes)
+	assert.True(t, hook.Subscribes(EventFavouriteAdded))
+	assert.False(t, hook.Subscribes(EventAssetDeleted))
+
+	for name, tc := range map[string]struct {
+		url        string
+		eventTypes []EventType
+		secret     string
+	}{
+		"relative url":   {url: "/hooks", eventTypes: []EventType{EventAssetCreated}, secret: testWebhookSecret},
+		"scheme":         {url: "ftp://example.com", eventTypes: []EventType{EventAssetCreated}, secret: testWebhookSecret},
+		"no event types": {url: "http://example.com", secret: testWebhookSecret},
+		"unknown event":  {url: "http://example.com", eventTypes: []EventType{EventReset}, secret: testWebhookSecret},
+		"short secret":   {url: "http://example.com", eventTypes: []EventType{EventAssetCreated}, secret: "secret"},
+		"loopback":       {url: "http://127.0.0.1:8080/hooks", eventTypes: []EventType{EventAssetCreated}, secret: testWebhookSecret},
+		"localhost":      {url: "http://LocalHost./hooks", eventTypes: []EventType{EventAssetCreated}, secret: testWebhookSecret},
+		"private":        {url: "https://10.1.2.3/hooks", eventTypes: []EventType{EventAssetCreated}, secret: testWebhookSecret},
+		"link-local":     {url: "http://169.254.169.254/latest/meta-data", eventTypes: []EventType{EventAssetCreated}, secret: testWebhookSecret},
+		"mapped ipv6":    {url: "http://[::ffff:127.0.0.1]/hooks", eventTypes: []EventType{EventAssetCreated}, secret: testWebhookSecret},
+		"unique local":   {url: "http://[fd00::1]/hooks", eventTypes: []EventType{EventAssetCreated}, secret: testWebhookSecret},
+	} {
+		_, err := NewWebhook(tc.url, tc.eventTypes, tc.secret)
+		assert.ErrorIs(t, err, ErrInvalidWebhook, name)
+	}
+}
+
+func TestWebhookDelivery_Attempts(t *testing.T) {
+	webhookID := uuid.New()
+	delivery := NewWebhookDelivery(webhookID, Event{ID: 1, Type: EventAssetCreated})
+	assert.Equal(t, DeliveryPending, delivery.Status)
+	assert.Equal(t, delivery.ID, NewWebhookDelivery(webhookID, Event{ID: 1, Type: EventAssetCreated}).ID, "one delivery per event")
+	assert.NotEqual(t, delivery.ID, NewWebhookDelivery(webhookID, Event{ID: 2, Type: EventAssetCreated}).ID)
+	assert.NotEqual(t, delivery.ID, NewWebhookDelivery(uuid.New(), Event{ID: 1, Type: EventAssetCreated}).ID)
+
+	_, err := delivery.Redelivered()
+	assert.ErrorIs(t, err, ErrInvalidWebhook)
+
+	retryAt := time.Now().Add(time.Second)
+	failed := delivery.WithAttempt(DeliveryAttempt{At: time.Now(), StatusCode: 500}, DeliveryPending, &retryAt)
+	dead := failed.WithAttempt(DeliveryAttempt{At: time.Now(), Error: "connection refused"}, DeliveryDead, nil)
+	assert.Empty(t, delivery.Attempts, "the delivery itself is left untouched")
+	assert.Len(t, failed.Attempts, 1)
+	assert.Equal(t, 2, dead.RoundAttempts)
+	assert.Nil(t, dead.NextAttemptAt)
+
+	redelivered, err := dead.Redelivered()
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryPending, redelivered.Status)
+	assert.Equal(t, 1, redelivered.Redeliveries)
+	assert.Equal(t, 0, redelivered.RoundAttempts)
+	assert.Len(t, redelivered.Attempts, 2, "the history of attempts is kept")
+	assert.Equal(t, DeliveryDead, dead.Status)
+}
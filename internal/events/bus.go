@@ -0,0 +1,170 @@
+// Package events implements an in-process bus of domain events. Events are numbered in order,
+// usually by the outbox they were recorded in, and the most recent ones are kept, so that
+// subscribers reconnecting after a disconnect can resume from the last event they received.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+)
+
+// subscriptionBuffer bounds the events queued for a subscriber; a subscriber falling further
+// behind is closed, and may resume from the replay buffer
+const subscriptionBuffer = 64
+
+// Bus is a thread-safe publish/subscribe hub of events with a bounded replay buffer
+type Bus struct {
+	mu          sync.Mutex
+	lastID      uint64
+	replay      []domain.Event // ring buffer of the latest events
+	next        int            // ring index the next event is stored at
+	buffered    int            // number of events in the ring buffer
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// Subscription receives the matching events published after it was made. Missed holds those
+// published since the event it resumed from; when they are no longer buffered Lost is set, and
+// LastID is the ID of the latest event to resume from after reloading.
+type Subscription struct {
+	Missed []domain.Event
+	Lost   bool
+	LastID uint64
+
+	bus     *Bus
+	match   func(domain.Event) bool
+	events  chan domain.Event
+	dropped bool
+}
+
+// NewBus creates a bus replaying up to replaySize events; a non-positive size disables replay
+func NewBus(replaySize int) *Bus {
+	return &Bus{
+		replay:      make([]domain.Event, max(replaySize, 0)),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish buffers an event for replay and delivers it to the matching subscribers. An event
+// without an ID is numbered after the last one; an event with an ID no later than the last one
+// was published already and is discarded, so that events handed more than once are delivered
+// once. Publish never blocks: subscribers whose queue is full are closed.
+func (b *Bus) Publish(event domain.Event) domain.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case event.ID == 0:
+		event.ID = b.lastID + 1
+	case event.ID <= b.lastID:
+		return event
+	}
+	b.lastID = event.ID
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	if len(b.replay) > 0 {
+		b.replay[b.next] = event
+		b.next = (b.next + 1) % len(b.replay)
+		b.buffered = min(b.buffered+1, len(b.replay))
+	}
+
+	for sub := range b.subscribers {
+		if !sub.match(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			sub.dropped = true
+			b.unsubscribe(sub)
+		}
+	}
+	return event
+}
+
+// Subscribe subscribes to the events for which match holds, resuming after the event with ID
+// lastEventID when non-zero. An ID not issued by this bus, e.g. before a restart, counts as lost.
+func (b *Bus) Subscribe(match func(domain.Event) bool, lastEventID uint64) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &Subscription{
+		LastID: b.lastID,
+		bus:    b,
+		match:  match,
+		events: make(chan domain.Event, subscriptionBuffer),
+	}
+	if lastEventID > 0 {
+		sub.Missed, sub.Lost = b.since(lastEventID, match)
+	}
+	if b.closed {
+		close(sub.events)
+	} else {
+		b.subscribers[sub] = struct{}{}
+	}
+	return sub
+}
+
+// since returns the buffered matching events after the given ID, oldest first, and whether some
+// of the events after it are not buffered
+func (b *Bus) since(lastEventID uint64, match func(domain.Event) bool) ([]domain.Event, bool) {
+	if lastEventID > b.lastID {
+		return nil, true
+	}
+
+	oldest := b.lastID + 1 // ID of the oldest buffered event
+	var missed []domain.Event
+	for i := b.buffered; i > 0; i-- {
+		event := b.replay[(b.next-i+len(b.replay))%len(b.replay)]
+		if i == b.buffered {
+			oldest = event.ID
+		}
+		if event.ID > lastEventID && match(event) {
+			missed = append(missed, event)
+		}
+	}
+	if oldest > lastEventID+1 {
+		return nil, true
+	}
+	return missed, false
+}
+
+// Close closes all subscriptions, and those made afterwards, e.g. when shutting down
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.unsubscribe(sub)
+	}
+}
+
+func (b *Bus) unsubscribe(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Events returns the channel of events, closed when the subscription ends
+func (s *Subscription) Events() <-chan domain.Event {
+	return s.events
+}
+
+// Dropped tells whether the subscription was closed for falling behind, rather than by Close
+func (s *Subscription) Dropped() bool {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.dropped
+}
+
+// Close ends the subscription; it is safe to call more than once
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.unsubscribe(s)
+}
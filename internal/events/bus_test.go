@@ -0,0 +1,150 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func favouriteEvent(userID uuid.UUID) domain.Event {
+	return domain.NewFavouriteEvent(domain.EventFavouriteAdded, domain.NewFavourite(userID, uuid.New()))
+}
+
+// anyAsset follows every asset
+func anyAsset(uuid.UUID) bool { return true }
+
+func ids(events []domain.Event) []uint64 {
+	out := make([]uint64, len(events))
+	for i, event := range events {
+		out[i] = event.ID
+	}
+	return out
+}
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus(10)
+	alice, bob := uuid.New(), uuid.New()
+	sub := bus.Subscribe(func(e domain.Event) bool { return e.VisibleTo(alice, anyAsset) }, 0)
+	defer sub.Close()
+	assert.Empty(t, sub.Missed)
+	assert.False(t, sub.Lost)
+
+	bus.Publish(favouriteEvent(bob))
+	published := bus.Publish(favouriteEvent(alice))
+	bus.Publish(domain.NewAssetEvent(domain.EventAssetDeleted, uuid.New(), nil))
+
+	assert.Equal(t, uint64(2), published.ID)
+	assert.False(t, published.OccurredAt.IsZero())
+	received := []domain.Event{<-sub.Events(), <-sub.Events()}
+	assert.Equal(t, []uint64{2, 3}, ids(received))
+	assert.Equal(t, domain.EventAssetDeleted, received[1].Type)
+
+	sub.Close()
+	sub.Close()
+	_, open := <-sub.Events()
+	assert.False(t, open)
+	bus.Publish(favouriteEvent(alice))
+}
+
+func TestBus_Resume(t *testing.T) {
+	bus := NewBus(3)
+	alice := uuid.New()
+	aliceOnly := func(e domain.Event) bool { return e.VisibleTo(alice, anyAsset) }
+	for i := 0; i < 4; i++ {
+		bus.Publish(favouriteEvent(alice))
+	}
+	bus.Publish(favouriteEvent(uuid.New()))
+
+	tests := []struct {
+		name        string
+		lastEventID uint64
+		missed      []uint64
+		lost        bool
+	}{
+		{"up to date", 5, nil, false},
+		{"replayed", 3, []uint64{4}, false},
+		{"oldest buffered", 2, []uint64{3, 4}, false},
+		{"evicted", 1, nil, true},
+		{"unknown", 6, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := bus.Subscribe(aliceOnly, tt.lastEventID)
+			defer sub.Close()
+			assert.Equal(t, tt.lost, sub.Lost)
+			assert.Equal(t, len(tt.missed), len(sub.Missed))
+			if tt.missed != nil {
+				assert.Equal(t, tt.missed, ids(sub.Missed))
+			}
+			assert.Equal(t, uint64(5), sub.LastID)
+		})
+	}
+
+	// Events published after subscribing follow the replayed ones
+	sub := bus.Subscribe(aliceOnly, 4)
+	defer sub.Close()
+	bus.Publish(favouriteEvent(alice))
+	assert.Empty(t, sub.Missed)
+	assert.Equal(t, uint64(6), (<-sub.Events()).ID)
+}
+
+func TestBus_NumberedEvents(t *testing.T) {
+	bus := NewBus(10)
+	sub := bus.Subscribe(func(domain.Event) bool { return true }, 0)
+	defer sub.Close()
+
+	// Events recorded with an ID keep it, and those handed again are discarded
+	for _, id := range []uint64{7, 8, 7, 8, 9} {
+		event := favouriteEvent(uuid.New())
+		event.ID = id
+		bus.Publish(event)
+	}
+	assert.Equal(t, uint64(10), bus.Publish(favouriteEvent(uuid.New())).ID)
+	received := []domain.Event{<-sub.Events(), <-sub.Events(), <-sub.Events(), <-sub.Events()}
+	assert.Equal(t, []uint64{7, 8, 9, 10}, ids(received))
+	assert.Empty(t, sub.Events())
+
+	resumed := bus.Subscribe(func(domain.Event) bool { return true }, 8)
+	defer resumed.Close()
+	assert.False(t, resumed.Lost)
+	assert.Equal(t, []uint64{9, 10}, ids(resumed.Missed))
+	before := bus.Subscribe(func(domain.Event) bool { return true }, 5)
+	defer before.Close()
+	assert.True(t, before.Lost, "events before the first one published were never buffered")
+}
+
+func TestBus_SlowSubscriber(t *testing.T) {
+	bus := NewBus(0)
+	sub := bus.Subscribe(func(domain.Event) bool { return true }, 0)
+	for i := 0; i < subscriptionBuffer+1; i++ {
+		bus.Publish(favouriteEvent(uuid.New()))
+	}
+
+	var received int
+	for range sub.Events() {
+		received++
+	}
+	assert.Equal(t, subscriptionBuffer, received, "the subscription is closed once its queue overflows")
+	assert.True(t, sub.Dropped())
+
+	resumed := bus.Subscribe(func(domain.Event) bool { return true }, uint64(received))
+	defer resumed.Close()
+	assert.True(t, resumed.Lost, "nothing is replayed without a buffer")
+}
+
+func TestBus_Close(t *testing.T) {
+	bus := NewBus(10)
+	sub := bus.Subscribe(func(domain.Event) bool { return true }, 0)
+	bus.Close()
+	_, open := <-sub.Events()
+	assert.False(t, open)
+	assert.False(t, sub.Dropped())
+
+	late := bus.Subscribe(func(domain.Event) bool { return true }, 0)
+	_, open = <-late.Events()
+	require.False(t, open)
+	late.Close()
+}
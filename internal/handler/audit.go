@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/middleware"
+	"github.com/google/uuid"
+)
+
+// ListAuditResponse represents a page of the audit trail
+type ListAuditResponse struct {
+	Entries []*domain.AuditEntry `json:"entries"`
+	Total   int                  `json:"total" example:"120"`         // entries matching the filters
+	Next    uint64               `json:"next,omitempty" example:"50"` // after value of the next page, if any
+}
+
+// authorizeAdmin checks that an authenticated user is an administrator
+func authorizeAdmin(r *http.Request) error {
+	if _, ok := middleware.GetUserIDFromContext(r.Context()); ok && !middleware.IsAdmin(r.Context()) {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// parseAuditQuery parses the filters and pagination of an audit trail listing
+func parseAuditQuery(r *http.Request) (*domain.AuditQuery, error) {
+	params := r.URL.Query()
+	limit, err := parseOptionalInt(params.Get("limit"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidAuditQuery, err)
+	}
+	var after uint64
+	if value := params.Get("after"); value != "" {
+		if after, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: invalid after %q", domain.ErrInvalidAuditQuery, value)
+		}
+	}
+	query, err := domain.NewAuditQuery(after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.ActorID, err = parseOptionalUUID(params.Get("actor_id")); err != nil {
+		return nil, fmt.Errorf("%w: actor_id: %v", domain.ErrInvalidAuditQuery, err)
+	}
+	if query.TargetID, err = parseOptionalUUID(params.Get("target_id")); err != nil {
+		return nil, fmt.Errorf("%w: target_id: %v", domain.ErrInvalidAuditQuery, err)
+	}
+	if query.Since, err = parseOptionalTime(params.Get("since")); err != nil {
+		return nil, fmt.Errorf("%w: since: %v", domain.ErrInvalidAuditQuery, err)
+	}
+	if query.Until, err = parseOptionalTime(params.Get("until")); err != nil {
+		return nil, fmt.Errorf("%w: until: %v", domain.ErrInvalidAuditQuery, err)
+	}
+	query.Action = domain.AuditAction(params.Get("action"))
+	query.RequestID = params.Get("request_id")
+	return query, nil
+}
+
+// parseOptionalUUID parses an optional UUID query parameter
+func parseOptionalUUID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid UUID %q", value)
+	}
+	return &id, nil
+}
+
+// parseOptionalTime parses an optional RFC 3339 time query parameter
+func parseOptionalTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC 3339", value)
+	}
+	return at, nil
+}
+
+// ListAudit handles GET /audit
+//
+//		@Summary		List audit trail
+//		@Description	Get the audit trail of changes, oldest first: who made each change (the authenticated user), in which
+//		@Description	request (X-Request-ID), to what, and the values before and after it. Pages follow one another by the
+//		@Description	after parameter, set to the next value of the previous page. Administrators only: tokens must carry
+//		@Description	an "admin": true claim.
+//		@Tags			audit
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			actor_id	query		string	false	"Only changes made by this user (UUID)"
+//		@Param			action		query		string	false	"Only this action, e.g. favourite.add, or all actions on a resource type, e.g. favourite"
+//		@Param			target_id	query		string	false	"Only changes to this resource (UUID)"
+//		@Param			request_id	query		string	false	"Only changes made by this request"
+//		@Param			since		query		string	false	"Only changes made at or after this time (RFC 3339)"
+//		@Param			until		query		string	false	"Only changes made before this time (RFC 3339)"
+//		@Param			after		query		int		false	"Only entries after this sequence number"
+//		@Param			limit		query		int		false	"Entries per page (default 50, max 1000)"
+//		@Success		200			{object}	Response{data=ListAuditResponse}
+//		@Failure		400			{object}	BadRequestError
+//		@Failure		403			{object}	ForbiddenError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/audit [get]
+func (h *Handler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	query, err := parseAuditQuery(r)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	entries, total, err := h.service.ListAudit(r.Context(), query)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	response := ListAuditResponse{Entries: entries, Total: total}
+	if len(entries) == query.Limit {
+		response.Next = entries[len(entries)-1].Seq
+	}
+	respondSuccess(w, http.StatusOK, response, "")
+}
+
+// VerifyAudit handles GET /audit/verify
+//
+//		@Summary		Verify audit trail
+//		@Description	Check the whole audit trail against its hash chain. Each entry's hash covers its content and the hash
+//		@Description	of the entry before, so that altered, removed or reordered entries are reported; keeping the head hash
+//		@Description	elsewhere also tells, on a later check, whether entries were dropped from the end. Administrators only.
+//		@Tags			audit
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Success		200	{object}	Response{data=domain.AuditVerification}
+//		@Failure		403	{object}	ForbiddenError
+//		@Failure		500	{object}	InternalServerError
+//		@Router			/audit/verify [get]
+func (h *Handler) VerifyAudit(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	verification, err := h.service.VerifyAudit(r.Context())
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, verification, "")
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// BatchFavouritesRequest represents a batch of assets to favourite or unfavourite
+type BatchFavouritesRequest struct {
+	AssetIDs []uuid.UUID `json:"asset_ids"`
+}
+
+// BatchItemResult reports the outcome for one asset of a batch, with the HTTP status code the
+// equivalent single request would have returned
+type BatchItemResult struct {
+	domain.BatchResult
+	Code int `json:"code" example:"201"`
+}
+
+// BatchFavouritesResponse represents the per-asset results of a batch, in request order, with
+// the number of results per status
+type BatchFavouritesResponse struct {
+	Results []BatchItemResult          `json:"results"`
+	Summary map[domain.BatchStatus]int `json:"summary"`
+}
+
+// batchStatusCodes maps batch item statuses to HTTP status codes
+var batchStatusCodes = map[domain.BatchStatus]int{
+	domain.BatchCreated:       http.StatusCreated,
+	domain.BatchAlreadyExists: http.StatusConflict,
+	domain.BatchAssetNotFound: http.StatusNotFound,
+	domain.BatchRemoved:       http.StatusOK,
+	domain.BatchNotFound:      http.StatusNotFound,
+}
+
+func newBatchFavouritesResponse(results []domain.BatchResult) BatchFavouritesResponse {
+	resp := BatchFavouritesResponse{
+		Results: make([]BatchItemResult, len(results)),
+		Summary: make(map[domain.BatchStatus]int),
+	}
+	for i, result := range results {
+		resp.Results[i] = BatchItemResult{BatchResult: result, Code: batchStatusCodes[result.Status]}
+		resp.Summary[result.Status]++
+	}
+	return resp
+}
+
+// decodeBatch parses the userId path parameter and the batch request body
+func decodeBatch(r *http.Request) (uuid.UUID, []uuid.UUID, error) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		return uuid.Nil, nil, err
+	}
+	var req BatchFavouritesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return uuid.Nil, nil, err
+	}
+	return userID, req.AssetIDs, nil
+}
+
+// AddFavourites handles POST /users/{userId}/favourites:batch
+//
+//		@Summary		Add favourites in bulk
+//		@Description	Favourite a batch of assets at once (at most MAX_BATCH_ITEMS, 1000 by default). The batch is
+//		@Description	applied as a single transaction and answered with 207 Multi-Status: each asset gets a result
+//		@Description	in request order, with status created, already_exists or asset_not_found and the matching
+//		@Description	HTTP code.
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string					true	"User ID (UUID)"
+//		@Param			request	body		BatchFavouritesRequest	true	"Asset IDs to favourite"
+//		@Success		207		{object}	Response{data=BatchFavouritesResponse}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/favourites:batch [post]
+func (h *Handler) AddFavourites(w http.ResponseWriter, r *http.Request) {
+	userID, assetIDs, err := decodeBatch(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	results, err := h.service.AddFavourites(r.Context(), userID, assetIDs)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusMultiStatus, newBatchFavouritesResponse(results), "")
+}
+
+// RemoveFavourites handles DELETE /users/{userId}/favourites:batch
+//
+//		@Summary		Remove favourites in bulk
+//		@Description	Remove the user's favourites of a batch of assets at once (at most MAX_BATCH_ITEMS). The batch
+//		@Description	is applied as a single transaction and answered with 207 Multi-Status: each asset gets a
+//		@Description	result in request order, with status removed or not_found and the matching HTTP code.
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string					true	"User ID (UUID)"
+//		@Param			request	body		BatchFavouritesRequest	true	"Asset IDs to unfavourite"
+//		@Success		207		{object}	Response{data=BatchFavouritesResponse}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/favourites:batch [delete]
+func (h *Handler) RemoveFavourites(w http.ResponseWriter, r *http.Request) {
+	userID, assetIDs, err := decodeBatch(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	results, err := h.service.RemoveFavourites(r.Context(), userID, assetIDs)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusMultiStatus, newBatchFavouritesResponse(results), "")
+}
+
+// FavouriteStatusResponse represents whether each of the requested assets is favourited, in request order
+type FavouriteStatusResponse struct {
+	Statuses []domain.FavouriteStatus `json:"statuses"`
+}
+
+// FavouriteStatuses handles GET /users/{userId}/favourites/status
+//
+//		@Summary		Look up favourite status
+//		@Description	Tell for each of a batch of assets (at most MAX_BATCH_ITEMS) whether the user has favourited it,
+//		@Description	and under which favourite ID, e.g. to mark the favourites among a page of assets.
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string		true	"User ID (UUID)"
+//		@Param			assetId	query		[]string	true	"Asset IDs to look up"	collectionFormat(multi)
+//		@Success		200		{object}	Response{data=FavouriteStatusResponse}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/favourites/status [get]
+func (h *Handler) FavouriteStatuses(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	assetIDs, err := parseUUIDs(r.URL.Query()["assetId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	statuses, err := h.service.FavouriteStatuses(r.Context(), userID, assetIDs)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, FavouriteStatusResponse{Statuses: statuses}, "")
+}
+
+// parseUUIDs parses a list of UUIDs, also accepting comma separated values
+func parseUUIDs(values []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(s))
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
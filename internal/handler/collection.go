@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CollectionRequest represents the request to create or rename a collection
+type CollectionRequest struct {
+	Name string `json:"name" example:"Q4 review"`
+}
+
+// ListCollectionsResponse represents a user's collections
+type ListCollectionsResponse struct {
+	Collections []*domain.Collection `json:"collections"`
+}
+
+// parseUserCollection parses the userId and collectionId path parameters
+func parseUserCollection(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(r)
+	userID, err := uuid.Parse(vars["userId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	collectionID, err := uuid.Parse(vars["collectionId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return userID, collectionID, nil
+}
+
+// CreateCollection handles POST /users/{userId}/collections
+//
+//		@Summary		Create collection
+//		@Description	Create an empty collection to group some of the user's favourites. Names are unique per user, ignoring case.
+//		@Tags			collections
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string				true	"User ID (UUID)"
+//		@Param			request	body		CollectionRequest	true	"Collection name"
+//		@Success		201		{object}	Response{data=domain.Collection}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		409		{object}	ConflictError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/collections [post]
+func (h *Handler) CreateCollection(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	collection, err := h.service.CreateCollection(r.Context(), userID, req.Name)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusCreated, collection, "Collection created successfully")
+}
+
+// ListCollections handles GET /users/{userId}/collections
+//
+//		@Summary		List collections
+//		@Description	Get all of a user's collections ordered by name, with the number of favourites in each
+//		@Tags			collections
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string	true	"User ID (UUID)"
+//		@Success		200		{object}	Response{data=ListCollectionsResponse}
+//		@Failure		400		{object}	InvalidUUIDError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/collections [get]
+func (h *Handler) ListCollections(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	collections, err := h.service.ListCollections(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, ListCollectionsResponse{Collections: collections}, "")
+}
+
+// RenameCollection handles PATCH /users/{userId}/collections/{collectionId}
+//
+//		@Summary		Rename collection
+//		@Description	Give a collection a new name
+//		@Tags			collections
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId			path		string				true	"User ID (UUID)"
+//		@Param			collectionId	path		string				true	"Collection ID (UUID)"
+//		@Param			request			body		CollectionRequest	true	"New name"
+//		@Success		200				{object}	Response{data=domain.Collection}
+//		@Failure		400				{object}	BadRequestError
+//		@Failure		404				{object}	NotFoundError
+//		@Failure		409				{object}	ConflictError
+//		@Failure		500				{object}	InternalServerError
+//		@Router			/users/{userId}/collections/{collectionId} [patch]
+func (h *Handler) RenameCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, err := parseUserCollection(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req CollectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	collection, err := h.service.RenameCollection(r.Context(), userID, collectionID, req.Name)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, collection, "Collection renamed successfully")
+}
+
+// DeleteCollection handles DELETE /users/{userId}/collections/{collectionId}
+//
+//		@Summary		Delete collection
+//		@Description	Delete a collection. The favourites it contained are kept.
+//		@Tags			collections
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId			path		string	true	"User ID (UUID)"
+//		@Param			collectionId	path		string	true	"Collection ID (UUID)"
+//		@Success		200				{object}	SuccessResponse
+//		@Failure		400				{object}	InvalidUUIDError
+//		@Failure		404				{object}	NotFoundError
+//		@Failure		500				{object}	InternalServerError
+//		@Router			/users/{userId}/collections/{collectionId} [delete]
+func (h *Handler) DeleteCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, err := parseUserCollection(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.DeleteCollection(r.Context(), userID, collectionID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, nil, "Collection deleted successfully")
+}
+
+// ListCollectionFavourites handles GET /users/{userId}/collections/{collectionId}/favourites
+//
+//		@Summary		List collection favourites
+//		@Description	Get a page of the favourites in a collection. Pagination, sorting, filtering and field
+//		@Description	selection work as for GET /users/{userId}/favourites.
+//		@Tags			collections
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId			path		string		true	"User ID (UUID)"
+//		@Param			collectionId	path		string		true	"Collection ID (UUID)"
+//		@Param			limit			query		int			false	"Number of items per page"	default(20)
+//		@Param			offset			query		int			false	"Number of items to skip"	default(0)
+//		@Param			sortBy			query		string		false	"Sort field"				Enums(created_at, updated_at, type, description, position)
+//		@Param			order			query		string		false	"Sort order"				Enums(asc, desc)
+//		@Param			cursor			query		string		false	"Cursor from a previous page (overrides offset, sortBy and order)"
+//		@Param			tag				query		[]string	false	"Only favourites of assets with these tags"	collectionFormat(multi)
+//		@Param			tagMatch		query		string		false	"Require all tags (AND) or any tag (OR)"	Enums(all, any)	default(all)
+//		@Param			filter			query		[]string	false	"Filter expression"	collectionFormat(multi)
+//		@Param			include			query		string		false	"Embed related resources"	Enums(asset)
+//		@Param			fields			query		string		false	"Comma separated favourite fields to return"
+//		@Success		200				{object}	Response{data=ListFavouritesResponse}
+//		@Failure		400				{object}	InvalidUUIDError
+//		@Failure		404				{object}	NotFoundError
+//		@Failure		500				{object}	InternalServerError
+//		@Router			/users/{userId}/collections/{collectionId}/favourites [get]
+func (h *Handler) ListCollectionFavourites(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, err := parseUserCollection(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	query, fields, err := parseFavouriteListQuery(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	favourites, page, err := h.service.ListCollectionFavourites(r.Context(), userID, collectionID, query)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, newListFavouritesResponse(favourites, page, query, fields), "")
+}
+
+// AddToCollection handles PUT /users/{userId}/collections/{collectionId}/favourites/{favouriteId}
+//
+//		@Summary		Add favourite to collection
+//		@Description	Put one of the user's favourites into a collection. Adding a favourite twice has no effect.
+//		@Tags			collections
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId			path		string	true	"User ID (UUID)"
+//		@Param			collectionId	path		string	true	"Collection ID (UUID)"
+//		@Param			favouriteId		path		string	true	"Favourite ID (UUID)"
+//		@Success		200				{object}	SuccessResponse
+//		@Failure		400				{object}	InvalidUUIDError
+//		@Failure		404				{object}	NotFoundError
+//		@Failure		500				{object}	InternalServerError
+//		@Router			/users/{userId}/collections/{collectionId}/favourites/{favouriteId} [put]
+func (h *Handler) AddToCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, err := parseUserCollection(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	favouriteID, err := uuid.Parse(mux.Vars(r)["favouriteId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.AddToCollection(r.Context(), userID, collectionID, favouriteID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, nil, "Favourite added to collection successfully")
+}
+
+// RemoveFromCollection handles DELETE /users/{userId}/collections/{collectionId}/favourites/{favouriteId}
+//
+//		@Summary		Remove favourite from collection
+//		@Description	Take a favourite out of a collection. The favourite itself is kept.
+//		@Tags			collections
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId			path		string	true	"User ID (UUID)"
+//		@Param			collectionId	path		string	true	"Collection ID (UUID)"
+//		@Param			favouriteId		path		string	true	"Favourite ID (UUID)"
+//		@Success		200				{object}	SuccessResponse
+//		@Failure		400				{object}	InvalidUUIDError
+//		@Failure		404				{object}	NotFoundError
+//		@Failure		500				{object}	InternalServerError
+//		@Router			/users/{userId}/collections/{collectionId}/favourites/{favouriteId} [delete]
+func (h *Handler) RemoveFromCollection(w http.ResponseWriter, r *http.Request) {
+	userID, collectionID, err := parseUserCollection(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	favouriteID, err := uuid.Parse(mux.Vars(r)["favouriteId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.RemoveFromCollection(r.Context(), userID, collectionID, favouriteID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, nil, "Favourite removed from collection successfully")
+}
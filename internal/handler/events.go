@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// eventsKeepAlive is how often an idle event stream sends a comment, so that proxies keep it open
+const eventsKeepAlive = 15 * time.Second
+
+// FavouriteEvents handles GET /users/{userId}/favourites/events
+//
+//		@Summary		Stream favourite events
+//		@Description	Stream, as Server-Sent Events, changes to the user's favourites (favourite_added,
+//		@Description	favourite_removed) and to the assets the user has favourited (asset_updated). Assets deleted
+//		@Description	are reported by the removal of their favourites. Each event's id can be sent
+//		@Description	back as Last-Event-ID header (or lastEventId parameter) on reconnecting, to receive the
+//		@Description	events missed meanwhile. When those are no longer available a reset event is sent instead,
+//		@Description	after which the client should reload the favourites. When authenticated, users may only
+//		@Description	stream their own events.
+//		@Tags			favourites
+//		@Produce		text/event-stream
+//	 @Security BearerAuth
+//		@Param			userId			path		string	true	"User ID (UUID)"
+//		@Param			Last-Event-ID	header		int		false	"ID of the last event received"
+//		@Param			lastEventId		query		int		false	"ID of the last event received, for clients unable to set headers"
+//		@Success		200				{object}	domain.Event
+//		@Failure		400				{object}	BadRequestError
+//		@Failure		403				{object}	ForbiddenError
+//		@Failure		500				{object}	InternalServerError
+//		@Router			/users/{userId}/favourites/events [get]
+func (h *Handler) FavouriteEvents(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// Streams outlive the server's write timeout
+	rc := http.NewResponseController(w)
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	sub := h.service.SubscribeEvents(userID, lastEventID)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if sub.Lost {
+		writeEvent(w, domain.Event{ID: sub.LastID, Type: domain.EventReset, OccurredAt: time.Now()})
+	}
+	for _, event := range sub.Missed {
+		writeEvent(w, event)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// Closed on shutdown or for falling behind; the client resumes on reconnecting
+				return
+			}
+			writeEvent(w, event)
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// CloseStreams ends the open event streams and live connections when the server shuts down, and
+// waits until the live connections are closed
+func (h *Handler) CloseStreams(ctx context.Context) error {
+	h.service.CloseEvents()
+	return h.live.wait(ctx)
+}
+
+// parseLastEventID reads the ID of the last event a client received, zero when not resuming
+func parseLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("lastEventId")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Last-Event-ID: %w", err)
+	}
+	return id, nil
+}
+
+// writeEvent writes an event in the Server-Sent Events format
+func writeEvent(w io.Writer, event domain.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
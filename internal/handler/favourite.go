@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/middleware"
+	"github.com/gioannid/platform-go-challenge/internal/render"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
@@ -24,6 +29,31 @@ type ListFavouritesResponse struct {
 	Total      int                 `json:"total"`
 	Limit      int                 `json:"limit"`
 	Offset     int                 `json:"offset"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+	PrevCursor string              `json:"prev_cursor,omitempty"`
+
+	fields *favouriteFieldSet
+}
+
+// MarshalJSON encodes the response, reduced to the selected fields of each favourite
+func (r ListFavouritesResponse) MarshalJSON() ([]byte, error) {
+	type plain ListFavouritesResponse
+	if r.fields == nil || !r.fields.sparse() {
+		return json.Marshal(plain(r))
+	}
+
+	favourites := make([]json.RawMessage, len(r.Favourites))
+	for i, fav := range r.Favourites {
+		encoded, err := r.fields.encode(fav)
+		if err != nil {
+			return nil, err
+		}
+		favourites[i] = encoded
+	}
+	return json.Marshal(struct {
+		plain
+		Favourites []json.RawMessage `json:"favourites"`
+	}{plain(r), favourites})
 }
 
 // TODO: Replace userID extraction from mux.Vars with extraction from request context.
@@ -32,7 +62,21 @@ type ListFavouritesResponse struct {
 // / ListFavourites handles GET /users/{userId}/favourites
 //
 //		@Summary		List user favourites
-//		@Description	Get paginated list of all favourites for a specific user
+//		@Description	Get paginated list of all favourites for a specific user. Pages can be addressed by offset or,
+//		@Description	more efficiently and stable under concurrent changes, by passing back the next_cursor or prev_cursor
+//		@Description	of a previous response; a cursor keeps the sort field and order it was issued for.
+//		@Description	sortBy=position returns the user's manual order (ascending by default), pinned favourites first.
+//		@Description
+//		@Description	The filter parameter takes comma separated clauses that must all hold, e.g.
+//		@Description	`type:chart|insight,created_at>=2025-10-01,description~sales,favourited_at:2025-10-17`.
+//		@Description	Fields: type (:), created_at and updated_at of the asset, favourited_at (: > >= < <=, with
+//		@Description	dates or RFC 3339 timestamps) and description (~ contains, ignoring case). Values containing
+//		@Description	commas can be double-quoted. Unknown fields or operators are rejected with 400.
+//		@Description
+//		@Description	Favourites are returned with their assets embedded. The fields parameter narrows down the
+//		@Description	returned favourite fields, e.g. `fields=id,created_at,asset.description`; "asset" selects the
+//		@Description	whole asset and "asset.<field>" single asset fields, which leave the asset out unless selected
+//		@Description	or included with include=asset.
 //		@Tags			favourites
 //		@Accept			json
 //		@Produce		json
@@ -40,9 +84,18 @@ type ListFavouritesResponse struct {
 //		@Param			userId	path		string	true	"User ID (UUID)"
 //		@Param			limit	query		int		false	"Number of items per page"	default(20)
 //		@Param			offset	query		int		false	"Number of items to skip"	default(0)
-//		@Param			sortBy	query		string	false	"Sort field"				Enums(created_at, updated_at)
+//		@Param			sortBy	query		string	false	"Sort field"				Enums(created_at, updated_at, type, description, position)
 //		@Param			order	query		string	false	"Sort order"				Enums(asc, desc)
+//		@Param			cursor	query		string	false	"Cursor from a previous page (overrides offset, sortBy and order)"
+//		@Param			tag		query		[]string	false	"Only favourites of assets with these tags"	collectionFormat(multi)
+//		@Param			tagMatch	query	string	false	"Require all tags (AND) or any tag (OR)"	Enums(all, any)	default(all)
+//		@Param			filter	query		[]string	false	"Filter expression, see description"	collectionFormat(multi)
+//		@Param			include	query		string	false	"Embed related resources"	Enums(asset)
+//		@Param			fields	query		string	false	"Comma separated favourite fields to return, e.g. id,asset.description"
+//		@Param			assetId	query		string	false	"Only the favourite of this asset, if any"
+//		@Param			If-None-Match	header	string	false	"ETag of a previous response"
 //		@Success		200		{object}	Response{data=ListFavouritesResponse}
+//		@Success		304		"Not modified"
 //		@Failure		400		{object}	InvalidUUIDError
 //		@Failure		404		{object}	NotFoundError
 //		@Failure		500		{object}	InternalServerError
@@ -51,30 +104,101 @@ func (h *Handler) ListFavourites(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := uuid.Parse(vars["userId"])
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	// Parse query parameters
-	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
-	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
-	sortBy := r.URL.Query().Get("sortBy")
-	order := r.URL.Query().Get("order")
+	query, fields, err := parseFavouriteListQuery(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
 
-	query := domain.NewPageQuery(limit, offset, sortBy, order)
+	favourites, page, err := h.service.ListFavourites(r.Context(), userID, query)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondWithETag(w, r, newListFavouritesResponse(favourites, page, query, fields))
+}
+
+// GetFavourite handles GET /users/{userId}/favourites/{favouriteId}
+//
+//		@Summary		Get favourite
+//		@Description	Get one of the user's favourites with its asset embedded. Responses carry an ETag; sending it
+//		@Description	back in If-None-Match returns 304 Not Modified while the favourite is unchanged.
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId			path		string	true	"User ID (UUID)"
+//		@Param			favouriteId		path		string	true	"Favourite ID (UUID)"
+//		@Param			If-None-Match	header		string	false	"ETag of a previous response"
+//		@Success		200				{object}	Response{data=domain.Favourite}
+//		@Success		304				"Not modified"
+//		@Failure		400				{object}	InvalidUUIDError
+//		@Failure		404				{object}	NotFoundError
+//		@Failure		500				{object}	InternalServerError
+//		@Router			/users/{userId}/favourites/{favouriteId} [get]
+func (h *Handler) GetFavourite(w http.ResponseWriter, r *http.Request) {
+	userID, favouriteID, err := parseUserFavourite(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
 
-	favourites, total, err := h.service.ListFavourites(r.Context(), userID, query)
+	favourite, err := h.service.GetFavourite(r.Context(), userID, favouriteID)
 	if err != nil {
-		respondError(w, mapDomainError(err), err)
+		respondError(w, r, mapDomainError(err), err)
 		return
 	}
 
-	respondSuccess(w, http.StatusOK, ListFavouritesResponse{
+	respondWithETag(w, r, favourite)
+}
+
+// parseFavouriteListQuery parses the pagination, sorting, filtering and field selection
+// parameters of a favourites listing
+func parseFavouriteListQuery(r *http.Request) (*domain.PageQuery, *favouriteFieldSet, error) {
+	params := r.URL.Query()
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	offset, _ := strconv.Atoi(params.Get("offset"))
+
+	query := domain.NewPageQuery(limit, offset, params.Get("sortBy"), params.Get("order"))
+	if err := query.SetCursor(params.Get("cursor")); err != nil {
+		return nil, nil, err
+	}
+	if err := query.SetTagFilter(params["tag"], params.Get("tagMatch")); err != nil {
+		return nil, nil, err
+	}
+	if err := query.SetFilter(params["filter"], domain.FilterFavourites); err != nil {
+		return nil, nil, err
+	}
+	if value := params.Get("assetId"); value != "" {
+		assetID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid assetId: %w", err)
+		}
+		query.AssetID = &assetID
+	}
+	fields, err := parseFavouriteFieldSet(params["include"], params["fields"])
+	if err != nil {
+		return nil, nil, err
+	}
+	query.IncludeAsset = fields.withAsset
+	return query, fields, nil
+}
+
+func newListFavouritesResponse(favourites []*domain.Favourite, page domain.PageInfo, query *domain.PageQuery, fields *favouriteFieldSet) ListFavouritesResponse {
+	return ListFavouritesResponse{
 		Favourites: favourites,
-		Total:      total,
+		Total:      page.Total,
 		Limit:      query.Limit,
 		Offset:     query.Offset,
-	}, "")
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		fields:     fields,
+	}
 }
 
 // AddFavouriteRequest represents the request to add a favourite
@@ -91,30 +215,33 @@ type AddFavouriteRequest struct {
 //		@Produce		json
 //	 @Security BearerAuth
 //		@Param			userId	path		string				true	"User ID (UUID)"
-//		@Param			request	body		AddFavouriteRequest	true	"Favourite details"
-//		@Success		201		{object}	Response{data=domain.Favourite}
-//		@Failure		400		{object}	BadRequestError
-//		@Failure		404		{object}	NotFoundError
-//		@Failure		409		{object}	ConflictError
-//		@Failure		500		{object}	InternalServerError
+//		@Param			request			body		AddFavouriteRequest	true	"Favourite details"
+//		@Param			Idempotency-Key	header		string				false	"Key making retries of the request replay its first response"
+//		@Success		201				{object}	Response{data=domain.Favourite}
+//		@Failure		400				{object}	BadRequestError
+//		@Failure		404				{object}	NotFoundError
+//		@Failure		409				{object}	ConflictError
+//		@Failure		413				{string}	string	"Body over 1 MiB sent with an Idempotency-Key"
+//		@Failure		422				{string}	string	"Idempotency-Key reused with a different request"
+//		@Failure		500				{object}	InternalServerError
 //		@Router			/users/{userId}/favourites [post]
 func (h *Handler) AddFavourite(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	userID, err := uuid.Parse(vars["userId"])
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	var req AddFavouriteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	favourite, err := h.service.AddFavourite(r.Context(), userID, req.AssetID)
 	if err != nil {
-		respondError(w, mapDomainError(err), err)
+		respondError(w, r, mapDomainError(err), err)
 		return
 	}
 
@@ -141,24 +268,194 @@ func (h *Handler) RemoveFavourite(w http.ResponseWriter, r *http.Request) {
 
 	userID, err := uuid.Parse(vars["userId"])
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	favouriteID, err := uuid.Parse(vars["favouriteId"])
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.service.RemoveFavourite(r.Context(), userID, favouriteID); err != nil {
-		respondError(w, mapDomainError(err), err)
+		respondError(w, r, mapDomainError(err), err)
 		return
 	}
 
 	respondSuccess(w, http.StatusOK, nil, "Favourite removed successfully")
 }
 
+// UpdateFavouriteRequest represents the request to annotate a favourite. Omitted fields are
+// left unchanged; an empty string clears a field.
+type UpdateFavouriteRequest struct {
+	Title *string `json:"title,omitempty" example:"Board deck chart"`
+	Note  *string `json:"note,omitempty" example:"Check the Q4 numbers with finance"`
+}
+
+// UpdateFavourite handles PATCH /users/{userId}/favourites/{favouriteId}
+//
+//		@Summary		Annotate favourite
+//		@Description	Set the user's own title and note on a favourite, without changing the asset for other users.
+//		@Description	Titles are at most 200 and notes at most 2000 characters long. Both are returned in favourite
+//		@Description	listings and are matched by favourite search.
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId		path		string					true	"User ID (UUID)"
+//		@Param			favouriteId	path		string					true	"Favourite ID (UUID)"
+//		@Param			request		body		UpdateFavouriteRequest	true	"Fields to change"
+//		@Success		200			{object}	Response{data=domain.Favourite}
+//		@Failure		400			{object}	BadRequestError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/users/{userId}/favourites/{favouriteId} [patch]
+func (h *Handler) UpdateFavourite(w http.ResponseWriter, r *http.Request) {
+	userID, favouriteID, err := parseUserFavourite(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req UpdateFavouriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	favourite, err := h.service.UpdateFavourite(r.Context(), userID, favouriteID, domain.FavouriteUpdate{Title: req.Title, Note: req.Note})
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, favourite, "Favourite updated successfully")
+}
+
+// MoveFavouriteRequest represents the request to move a favourite next to another one.
+// Exactly one of the IDs must be given.
+type MoveFavouriteRequest struct {
+	AfterID  *uuid.UUID `json:"after_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	BeforeID *uuid.UUID `json:"before_id,omitempty"`
+}
+
+// parseUserFavourite parses the userId and favouriteId path parameters
+func parseUserFavourite(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(r)
+	userID, err := uuid.Parse(vars["userId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	favouriteID, err := uuid.Parse(vars["favouriteId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return userID, favouriteID, nil
+}
+
+// MoveFavourite handles PUT /users/{userId}/favourites/{favouriteId}/position
+//
+//		@Summary		Move favourite
+//		@Description	Place a favourite right after or right before another of the user's favourites in the manual
+//		@Description	order (sortBy=position). Only the moved favourite changes; both must be pinned or both unpinned.
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId		path		string					true	"User ID (UUID)"
+//		@Param			favouriteId	path		string					true	"Favourite ID (UUID)"
+//		@Param			request		body		MoveFavouriteRequest	true	"Neighbour to move next to"
+//		@Success		200			{object}	Response{data=domain.Favourite}
+//		@Failure		400			{object}	BadRequestError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/users/{userId}/favourites/{favouriteId}/position [put]
+func (h *Handler) MoveFavourite(w http.ResponseWriter, r *http.Request) {
+	userID, favouriteID, err := parseUserFavourite(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req MoveFavouriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	favourite, err := h.service.MoveFavourite(r.Context(), userID, favouriteID, req.AfterID, req.BeforeID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, favourite, "Favourite moved successfully")
+}
+
+// PinFavourite handles PUT /users/{userId}/favourites/{favouriteId}/pin
+//
+//		@Summary		Pin favourite
+//		@Description	Pin a favourite so it is listed before the unpinned ones in the manual order, after the
+//		@Description	favourites pinned earlier. Pinning a pinned favourite has no effect.
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId		path		string	true	"User ID (UUID)"
+//		@Param			favouriteId	path		string	true	"Favourite ID (UUID)"
+//		@Success		200			{object}	Response{data=domain.Favourite}
+//		@Failure		400			{object}	InvalidUUIDError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/users/{userId}/favourites/{favouriteId}/pin [put]
+func (h *Handler) PinFavourite(w http.ResponseWriter, r *http.Request) {
+	userID, favouriteID, err := parseUserFavourite(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	favourite, err := h.service.PinFavourite(r.Context(), userID, favouriteID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, favourite, "Favourite pinned successfully")
+}
+
+// UnpinFavourite handles DELETE /users/{userId}/favourites/{favouriteId}/pin
+//
+//		@Summary		Unpin favourite
+//		@Description	Unpin a favourite, placing it first among the unpinned ones in the manual order
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId		path		string	true	"User ID (UUID)"
+//		@Param			favouriteId	path		string	true	"Favourite ID (UUID)"
+//		@Success		200			{object}	Response{data=domain.Favourite}
+//		@Failure		400			{object}	InvalidUUIDError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/users/{userId}/favourites/{favouriteId}/pin [delete]
+func (h *Handler) UnpinFavourite(w http.ResponseWriter, r *http.Request) {
+	userID, favouriteID, err := parseUserFavourite(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	favourite, err := h.service.UnpinFavourite(r.Context(), userID, favouriteID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, favourite, "Favourite unpinned successfully")
+}
+
 // UpdateAssetDescriptionRequest represents the request to update description
 type UpdateAssetDescriptionRequest struct {
 	Description string `json:"description"`
@@ -183,18 +480,18 @@ func (h *Handler) UpdateAssetDescription(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	assetID, err := uuid.Parse(vars["assetId"])
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	var req UpdateAssetDescriptionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
 	if err := h.service.UpdateAssetDescription(r.Context(), assetID, req.Description); err != nil {
-		respondError(w, mapDomainError(err), err)
+		respondError(w, r, mapDomainError(err), err)
 		return
 	}
 
@@ -206,12 +503,14 @@ type CreateAssetRequest struct {
 	Type        domain.AssetType `json:"type"`
 	Description string           `json:"description"`
 	Data        json.RawMessage  `json:"data" swaggertype:"object"`
+	Tags        []string         `json:"tags,omitempty"`
 }
 
 // CreateAsset handles POST /assets
 //
 //		@Summary		Create asset
 //		@Description	Create a new asset of type chart, insight, or audience.
+//		@Description	Retries sent with the same Idempotency-Key header get the first response back instead of a duplicate asset.
 //		@Description
 //		@Description	**Chart Example:**
 //		@Description	```
@@ -220,20 +519,37 @@ type CreateAssetRequest struct {
 //		@Description	  "description": "Monthly sales data",
 //		@Description	  "data": {
 //		@Description	    "title": "Q4 2025 Sales",
+//		@Description	    "kind": "bar",
 //		@Description	    "axis_x_title": "Month",
-//		@Description	    "axis_y_title": "Revenue (USD)",
-//		@Description	    "data": [[100, 200], [300, 400]]
+//		@Description	    "axis_y_title": "Revenue",
+//		@Description	    "axis_y_unit": "USD",
+//		@Description	    "categories": ["Oct", "Nov", "Dec"],
+//		@Description	    "series": [
+//		@Description	      {"name": "EU", "values": [1200, 1350, 1800]},
+//		@Description	      {"name": "US", "values": [2100, 2250, 2900]}
+//		@Description	    ],
+//		@Description	    "number_format": {"decimals": 0, "prefix": "$", "thousands_separator": true}
 //		@Description	  }
 //		@Description	}
 //		@Description	```
 //		@Description
+//		@Description	Any asset may carry "tags": ["social-media", "gen-z"]; tags are lowercased and inner spaces become dashes.
+//		@Description
+//		@Description	Chart kinds are line (default), bar, pie and scatter. Without categories, series carry
+//		@Description	x/y "points" instead of "values"; the legacy "data": [[x, y], ...] rows are still accepted.
+//		@Description
 //		@Description	**Insight Example:**
 //		@Description	```
 //		@Description	{
 //		@Description	  "type": "insight",
 //		@Description	  "description": "Social media usage",
 //		@Description	  "data": {
-//		@Description	    "text": "40% of millennials spend 3+ hours daily on social media"
+//		@Description	    "text": "40% of millennials spend 3+ hours daily on social media",
+//		@Description	    "value": 40,
+//		@Description	    "unit": "%",
+//		@Description	    "source": "GWI Core",
+//		@Description	    "period": {"start": "2025-10-01T00:00:00Z", "end": "2025-12-31T23:59:59Z"},
+//		@Description	    "audience_id": "3fa85f64-5717-4562-b3fc-2c963f66afa6"
 //		@Description	  }
 //		@Description	}
 //		@Description	```
@@ -245,10 +561,13 @@ type CreateAssetRequest struct {
 //		@Description	  "description": "Target demographic",
 //		@Description	  "data": {
 //		@Description	    "gender": "Male",
-//		@Description	    "birth_country": "USA",
-//		@Description	    "age_groups": ["24-35"],
-//		@Description	    "hours_social_daily": 3.5,
-//		@Description	    "purchases_last_month": 5
+//		@Description	    "birth_country": "US",
+//		@Description	    "age_groups": ["25-34"],
+//		@Description	    "hours_social_daily": {"gt": 3},
+//		@Description	    "any": [
+//		@Description	      {"purchases_last_month": {"gte": 5}},
+//		@Description	      {"birth_country": "GB"}
+//		@Description	    ]
 //		@Description	  }
 //		@Description	}
 //		@Description	```
@@ -256,15 +575,18 @@ type CreateAssetRequest struct {
 //		@Accept			json
 //		@Produce		json
 //	 @Security BearerAuth
-//		@Param			request	body		CreateAssetRequest		true	"Asset creation request"
-//		@Success		201		{object}	Response{data=domain.Asset}
-//		@Failure		400		{object}	BadRequestError
-//		@Failure		500		{object}	InternalServerError
+//		@Param			request			body		CreateAssetRequest	true	"Asset creation request"
+//		@Param			Idempotency-Key	header		string				false	"Key making retries of the request replay its first response"
+//		@Success		201				{object}	Response{data=domain.Asset}
+//		@Failure		400				{object}	BadRequestError
+//		@Failure		413				{string}	string	"Body over 1 MiB sent with an Idempotency-Key"
+//		@Failure		422				{string}	string	"Idempotency-Key reused with a different request"
+//		@Failure		500				{object}	InternalServerError
 //		@Router			/assets [post]
 func (h *Handler) CreateAsset(w http.ResponseWriter, r *http.Request) {
 	var req CreateAssetRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
@@ -274,88 +596,211 @@ func (h *Handler) CreateAsset(w http.ResponseWriter, r *http.Request) {
 	case domain.AssetTypeChart:
 		var chartData domain.ChartData
 		if err := json.Unmarshal(req.Data, &chartData); err != nil {
-			respondError(w, http.StatusBadRequest, err)
+			respondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 		data = chartData
 	case domain.AssetTypeInsight:
 		var insightData domain.InsightData
 		if err := json.Unmarshal(req.Data, &insightData); err != nil {
-			respondError(w, http.StatusBadRequest, err)
+			respondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 		data = insightData
 	case domain.AssetTypeAudience:
 		var audienceData domain.AudienceData
 		if err := json.Unmarshal(req.Data, &audienceData); err != nil {
-			respondError(w, http.StatusBadRequest, err)
+			respondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 		data = audienceData
 	default:
-		respondError(w, http.StatusBadRequest, domain.ErrInvalidAssetType)
+		respondError(w, r, http.StatusBadRequest, domain.ErrInvalidAssetType)
 		return
 	}
 
-	asset, err := h.service.CreateAsset(r.Context(), req.Type, req.Description, data)
+	asset, err := h.service.CreateAsset(r.Context(), req.Type, req.Description, data, req.Tags)
 	if err != nil {
-		respondError(w, mapDomainError(err), err)
+		respondError(w, r, mapDomainError(err), err)
 		return
 	}
 
 	respondSuccess(w, http.StatusCreated, asset, "Asset created successfully")
 }
 
+// GetAsset handles GET /assets/{assetId}
+//
+//		@Summary		Get asset
+//		@Description	Get a single asset. Responses carry an ETag; sending it back in If-None-Match returns
+//		@Description	304 Not Modified while the asset is unchanged.
+//		@Tags			assets
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			assetId			path		string	true	"Asset ID (UUID)"
+//		@Param			If-None-Match	header		string	false	"ETag of a previous response"
+//		@Success		200				{object}	Response{data=domain.Asset}
+//		@Success		304				"Not modified"
+//		@Failure		400				{object}	InvalidUUIDError
+//		@Failure		404				{object}	NotFoundError
+//		@Failure		500				{object}	InternalServerError
+//		@Router			/assets/{assetId} [get]
+func (h *Handler) GetAsset(w http.ResponseWriter, r *http.Request) {
+	assetID, err := uuid.Parse(mux.Vars(r)["assetId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	asset, err := h.service.GetAsset(r.Context(), assetID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondWithETag(w, r, asset)
+}
+
+// DeleteAssetResponse lists the assets removed by a delete, including cascaded ones
+type DeleteAssetResponse struct {
+	DeletedAssetIDs []uuid.UUID `json:"deleted_asset_ids"`
+}
+
 // DeleteAsset handles DELETE /assets/{assetId}
 //
 //		@Summary		Delete asset
-//		@Description	Delete an existing asset
+//		@Description	Delete an existing asset together with any favourites of it. An audience referenced by
+//		@Description	insights cannot be deleted (409) unless cascade=true, which deletes the referencing insights too.
 //		@Tags			assets
 //		@Accept			json
 //		@Produce		json
 //	 @Security BearerAuth
 //		@Param			assetId	path		string	true	"Asset ID (UUID)"
-//		@Success		200		{object}	SuccessResponse
+//		@Param			cascade	query		bool	false	"Also delete assets referencing this asset"	default(false)
+//		@Success		200		{object}	Response{data=DeleteAssetResponse}
 //		@Failure		400		{object}	InvalidUUIDError
 //		@Failure		404		{object}	NotFoundError
+//		@Failure		409		{object}	ConflictError
 //		@Failure		500		{object}	InternalServerError
 //		@Router			/assets/{assetId} [delete]
 func (h *Handler) DeleteAsset(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	assetID, err := uuid.Parse(vars["assetId"])
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err)
+		respondError(w, r, http.StatusBadRequest, err)
 		return
 	}
 
-	if err := h.service.DeleteAsset(r.Context(), assetID); err != nil {
-		respondError(w, mapDomainError(err), err)
+	policy := domain.DeleteRestrict
+	if value := r.URL.Query().Get("cascade"); value != "" {
+		cascade, err := strconv.ParseBool(value)
+		if err != nil {
+			respondError(w, r, http.StatusBadRequest, fmt.Errorf("invalid cascade parameter: %w", err))
+			return
+		}
+		if cascade {
+			policy = domain.DeleteCascade
+		}
+	}
+
+	deleted, err := h.service.DeleteAsset(r.Context(), assetID, policy)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
 		return
 	}
 
-	respondSuccess(w, http.StatusOK, nil, "Asset deleted successfully")
+	respondSuccess(w, http.StatusOK, DeleteAssetResponse{DeletedAssetIDs: deleted}, "Asset deleted successfully")
 }
 
 // ListAssetsResponse represents paginated assets response
 type ListAssetsResponse struct {
-	Assets []*domain.Asset `json:"assets"`
-	Total  int             `json:"total"`
-	Limit  int             `json:"limit"`
-	Offset int             `json:"offset"`
+	Assets     []*AssetView `json:"assets"`
+	Total      int          `json:"total"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	PrevCursor string       `json:"prev_cursor,omitempty"`
+}
+
+// AssetView is an asset in a listing, annotated with its favourite status when the listing
+// asks for a user's favourites (favouritedBy)
+type AssetView struct {
+	*domain.Asset
+	Favourited  *bool      `json:"favourited,omitempty"`
+	FavouriteID *uuid.UUID `json:"favourite_id,omitempty"`
+}
+
+// parseFavouritedBy resolves the favouritedBy parameter: "me" stands for the authenticated user
+func parseFavouritedBy(r *http.Request) (*uuid.UUID, error) {
+	value := r.URL.Query().Get("favouritedBy")
+	switch value {
+	case "":
+		return nil, nil
+	case "me":
+		userID, ok := middleware.GetUserIDFromContext(r.Context())
+		if !ok {
+			return nil, fmt.Errorf("favouritedBy=me requires an authenticated user")
+		}
+		return &userID, nil
+	}
+	userID, err := uuid.Parse(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid favouritedBy: %w", err)
+	}
+	return &userID, nil
+}
+
+// newAssetViews wraps a page of assets, annotating them with favouritedBy's favourites if given
+func (h *Handler) newAssetViews(ctx context.Context, assets []*domain.Asset, favouritedBy *uuid.UUID) ([]*AssetView, error) {
+	views := make([]*AssetView, len(assets))
+	for i, asset := range assets {
+		views[i] = &AssetView{Asset: asset}
+	}
+	if favouritedBy == nil || len(assets) == 0 {
+		return views, nil
+	}
+
+	assetIDs := make([]uuid.UUID, len(assets))
+	for i, asset := range assets {
+		assetIDs[i] = asset.ID
+	}
+	favourited, err := h.service.FavouritedAssets(ctx, *favouritedBy, assetIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, view := range views {
+		favID, ok := favourited[view.ID]
+		view.Favourited = &ok
+		if ok {
+			view.FavouriteID = &favID
+		}
+	}
+	return views, nil
 }
 
 // ListAssets handles GET /assets
 //
 //		@Summary		List all assets
-//		@Description	Get paginated list of all assets in the system
+//		@Description	Get paginated list of all assets in the system. Pages can be addressed by offset or by passing
+//		@Description	back the next_cursor or prev_cursor of a previous response.
+//		@Description
+//		@Description	The filter parameter takes comma separated clauses that must all hold, e.g.
+//		@Description	`type:chart,created_at>=2025-10-01,description~"q4, sales"`. Fields: type (:), created_at and
+//		@Description	updated_at (: > >= < <=, with dates or RFC 3339 timestamps) and description (~ contains,
+//		@Description	ignoring case). Unknown fields or operators are rejected with 400.
 //		@Tags			assets
 //		@Accept			json
 //		@Produce		json
 //	 @Security BearerAuth
 //		@Param			limit	query		int		false	"Number of items per page"	default(20)
 //		@Param			offset	query		int		false	"Number of items to skip"	default(0)
-//		@Param			sortBy	query		string	false	"Sort field"				Enums(created_at, updated_at, type, description)
+//		@Param			sortBy	query		string	false	"Sort field; popularity is the number of favourites"	Enums(created_at, updated_at, type, description, popularity)
 //		@Param			order	query		string	false	"Sort order"				Enums(asc, desc)
+//		@Param			cursor	query		string	false	"Cursor from a previous page (overrides offset, sortBy and order)"
+//		@Param			tag		query		[]string	false	"Only assets with these tags"	collectionFormat(multi)
+//		@Param			tagMatch	query	string	false	"Require all tags (AND) or any tag (OR)"	Enums(all, any)	default(all)
+//		@Param			filter	query		[]string	false	"Filter expression, see description"	collectionFormat(multi)
+//		@Param			favouritedBy	query	string	false	"Mark the assets favourited by this user: a user ID, or me for the authenticated user"
 //		@Success		200		{object}	Response{data=ListAssetsResponse}
 //		@Failure		400		{object}	BadRequestError
 //		@Failure		500		{object}	InternalServerError
@@ -368,17 +813,399 @@ func (h *Handler) ListAssets(w http.ResponseWriter, r *http.Request) {
 	order := r.URL.Query().Get("order")
 
 	query := domain.NewPageQuery(limit, offset, sortBy, order)
+	if err := query.SetCursor(r.URL.Query().Get("cursor")); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := query.SetTagFilter(r.URL.Query()["tag"], r.URL.Query().Get("tagMatch")); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := query.SetFilter(r.URL.Query()["filter"], domain.FilterAssets); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	favouritedBy, err := parseFavouritedBy(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
 
-	assets, total, err := h.service.ListAssets(r.Context(), query)
+	assets, page, err := h.service.ListAssets(r.Context(), query)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	views, err := h.newAssetViews(r.Context(), assets, favouritedBy)
 	if err != nil {
-		respondError(w, mapDomainError(err), err)
+		respondError(w, r, mapDomainError(err), err)
 		return
 	}
 
 	respondSuccess(w, http.StatusOK, ListAssetsResponse{
+		Assets:     views,
+		Total:      page.Total,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+	}, "")
+}
+
+// TrendingAssetsResponse represents the assets that gained the most favourites within a window
+type TrendingAssetsResponse struct {
+	Window string                 `json:"window" example:"7d"`
+	Since  time.Time              `json:"since"`
+	Assets []domain.TrendingAsset `json:"assets"`
+}
+
+// TrendingAssets handles GET /assets/trending
+//
+//		@Summary		List trending assets
+//		@Description	Rank the assets by the net number of favourites they gained (added minus removed) within a
+//		@Description	window up to now, e.g. the most starred charts of the week. Activity is counted per hour,
+//		@Description	so the window starts at the beginning of the hour it falls into. Only assets that gained
+//		@Description	favourites are listed.
+//		@Tags			assets
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			window	query		string	false	"Window in days (7d) or hours (24h), at most 30d"	default(7d)
+//		@Param			limit	query		int		false	"Number of assets"	default(100)
+//		@Success		200		{object}	Response{data=TrendingAssetsResponse}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/assets/trending [get]
+func (h *Handler) TrendingAssets(w http.ResponseWriter, r *http.Request) {
+	window, err := domain.ParseTrendingWindow(r.URL.Query().Get("window"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := parseOptionalInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	assets, since, err := h.service.TrendingAssets(r.Context(), window, limit)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, TrendingAssetsResponse{
+		Window: formatWindow(window),
+		Since:  since,
 		Assets: assets,
+	}, "")
+}
+
+// formatWindow renders a trending window in days, or in hours if it is not a whole number of days
+func formatWindow(window time.Duration) string {
+	if day := 24 * time.Hour; window%day == 0 {
+		return strconv.Itoa(int(window/day)) + "d"
+	}
+	return strconv.Itoa(int(window/time.Hour)) + "h"
+}
+
+// SearchAssets handles GET /assets/search
+//
+//		@Summary		Search assets
+//		@Description	Full-text search over asset descriptions, tags, chart and axis titles, insight text and audience
+//		@Description	countries. Every word must match, either fully or as the prefix of a word; results are ranked by relevance.
+//		@Tags			assets
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			q		query		string		true	"Search text"
+//		@Param			limit	query		int			false	"Number of items per page"	default(20)
+//		@Param			offset	query		int			false	"Number of items to skip"	default(0)
+//		@Param			tag		query		[]string	false	"Only assets with these tags"	collectionFormat(multi)
+//		@Param			tagMatch	query	string		false	"Require all tags (AND) or any tag (OR)"	Enums(all, any)	default(all)
+//		@Param			favouritedBy	query	string	false	"Mark the assets favourited by this user: a user ID, or me for the authenticated user"
+//		@Success		200		{object}	Response{data=ListAssetsResponse}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/assets/search [get]
+func (h *Handler) SearchAssets(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	query := domain.NewPageQuery(limit, offset, "", "")
+	if err := query.SetTagFilter(r.URL.Query()["tag"], r.URL.Query().Get("tagMatch")); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	favouritedBy, err := parseFavouritedBy(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	assets, total, err := h.service.SearchAssets(r.Context(), r.URL.Query().Get("q"), query)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	views, err := h.newAssetViews(r.Context(), assets, favouritedBy)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, ListAssetsResponse{
+		Assets: views,
 		Total:  total,
 		Limit:  query.Limit,
 		Offset: query.Offset,
 	}, "")
 }
+
+// SearchFavourites handles GET /users/{userId}/favourites/search
+//
+//		@Summary		Search user favourites
+//		@Description	Full-text search over the assets a user has favourited and the user's own titles and notes on them, ranked by relevance
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string		true	"User ID (UUID)"
+//		@Param			q		query		string		true	"Search text"
+//		@Param			limit	query		int			false	"Number of items per page"	default(20)
+//		@Param			offset	query		int			false	"Number of items to skip"	default(0)
+//		@Param			tag		query		[]string	false	"Only favourites of assets with these tags"	collectionFormat(multi)
+//		@Param			tagMatch	query	string		false	"Require all tags (AND) or any tag (OR)"	Enums(all, any)	default(all)
+//		@Success		200		{object}	Response{data=ListFavouritesResponse}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/favourites/search [get]
+func (h *Handler) SearchFavourites(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := uuid.Parse(vars["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+	query := domain.NewPageQuery(limit, offset, "", "")
+	if err := query.SetTagFilter(r.URL.Query()["tag"], r.URL.Query().Get("tagMatch")); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	favourites, total, err := h.service.SearchFavourites(r.Context(), userID, r.URL.Query().Get("q"), query)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, ListFavouritesResponse{
+		Favourites: favourites,
+		Total:      total,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+	}, "")
+}
+
+// AssetTagsRequest represents the tags to add to an asset
+type AssetTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// AddAssetTags handles POST /assets/{assetId}/tags
+//
+//		@Summary		Tag asset
+//		@Description	Add tags to an asset. Tags are normalized (lowercased, inner spaces become dashes) and
+//		@Description	tags the asset already has are ignored.
+//		@Tags			assets
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			assetId	path		string				true	"Asset ID (UUID)"
+//		@Param			request	body		AssetTagsRequest	true	"Tags to add"
+//		@Success		200		{object}	Response{data=domain.Asset}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/assets/{assetId}/tags [post]
+func (h *Handler) AddAssetTags(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	assetID, err := uuid.Parse(vars["assetId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var req AssetTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	asset, err := h.service.AddAssetTags(r.Context(), assetID, req.Tags)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, asset, "Asset tags updated successfully")
+}
+
+// RemoveAssetTag handles DELETE /assets/{assetId}/tags/{tag}
+//
+//		@Summary		Untag asset
+//		@Description	Remove a tag from an asset. Removing a tag the asset does not have is a no-op.
+//		@Tags			assets
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			assetId	path		string	true	"Asset ID (UUID)"
+//		@Param			tag		path		string	true	"Tag to remove"
+//		@Success		200		{object}	Response{data=domain.Asset}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/assets/{assetId}/tags/{tag} [delete]
+func (h *Handler) RemoveAssetTag(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	assetID, err := uuid.Parse(vars["assetId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	asset, err := h.service.RemoveAssetTag(r.Context(), assetID, vars["tag"])
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, asset, "Asset tags updated successfully")
+}
+
+// ListTags handles GET /tags
+//
+//		@Summary		List tags
+//		@Description	Get all tags in use with the number of assets carrying each, most used first
+//		@Tags			assets
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Success		200		{object}	Response{data=[]domain.TagCount}
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/tags [get]
+func (h *Handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.service.ListTags(r.Context())
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, tags, "")
+}
+
+// MatchAudienceResponse represents the result of evaluating a respondent against an audience
+type MatchAudienceResponse struct {
+	AssetID uuid.UUID `json:"asset_id"`
+	Matched bool      `json:"matched"`
+}
+
+// MatchAudience handles POST /assets/{assetId}/match
+//
+//		@Summary		Match respondent to audience
+//		@Description	Evaluate whether a respondent profile satisfies the criteria of an audience asset
+//		@Tags			assets
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			assetId	path		string						true	"Asset ID (UUID)"
+//		@Param			request	body		domain.RespondentProfile	true	"Respondent profile"
+//		@Success		200		{object}	Response{data=MatchAudienceResponse}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/assets/{assetId}/match [post]
+func (h *Handler) MatchAudience(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	assetID, err := uuid.Parse(vars["assetId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var profile domain.RespondentProfile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	matched, err := h.service.MatchAudience(r.Context(), assetID, &profile)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, MatchAudienceResponse{
+		AssetID: assetID,
+		Matched: matched,
+	}, "")
+}
+
+// RenderAsset handles GET /assets/{assetId}/render
+//
+//		@Summary		Render chart
+//		@Description	Render a chart asset as an SVG or PNG image with its title, axis labels and legend
+//		@Tags			assets
+//		@Produce		image/svg+xml
+//		@Produce		image/png
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			assetId	path		string	true	"Asset ID (UUID)"
+//		@Param			format	query		string	false	"Image format"				Enums(svg, png)	default(svg)
+//		@Param			width	query		int		false	"Image width in pixels"		minimum(200)	maximum(2000)	default(800)
+//		@Param			height	query		int		false	"Image height in pixels"	minimum(200)	maximum(2000)	default(450)
+//		@Success		200		{file}		binary
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/assets/{assetId}/render [get]
+func (h *Handler) RenderAsset(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	assetID, err := uuid.Parse(vars["assetId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	width, err := parseOptionalInt(r.URL.Query().Get("width"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	height, err := parseOptionalInt(r.URL.Query().Get("height"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	opts, err := render.NewOptions(r.URL.Query().Get("format"), width, height)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	image, err := h.service.RenderAsset(r.Context(), assetID, opts)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	w.Header().Set("Content-Type", opts.Format.ContentType())
+	w.Header().Set("Content-Length", strconv.Itoa(len(image)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(image)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+)
+
+// Field names clients can select, taken from the JSON encoding of the domain types
+var (
+	favouriteFields = jsonFields(reflect.TypeOf(domain.Favourite{}))
+	assetFields     = jsonFields(reflect.TypeOf(domain.Asset{}))
+)
+
+// jsonFields returns the JSON names of the exported fields of a struct type
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
+
+// favouriteFieldSet selects the fields of favourites returned by a listing. Without a
+// selection, favourites carry all their fields, the favourited asset embedded among them; a
+// selection narrows them down, embedding the asset only when selected or included.
+type favouriteFieldSet struct {
+	favourite map[string]bool // selected favourite fields; nil selects all
+	asset     map[string]bool // selected asset fields; nil selects all
+	withAsset bool            // whether the asset is embedded
+}
+
+// parseFavouriteFieldSet parses the include and fields query parameters. fields is a comma
+// separated list of favourite fields, where "asset" selects the whole asset and "asset.<field>"
+// single asset fields (embedding it either way); include=asset embeds the whole asset along
+// with the fields selected.
+func parseFavouriteFieldSet(include []string, fields []string) (*favouriteFieldSet, error) {
+	set := &favouriteFieldSet{}
+	for _, value := range include {
+		for _, name := range strings.Split(value, ",") {
+			switch strings.TrimSpace(name) {
+			case "":
+			case "asset":
+				set.withAsset = true
+			default:
+				return nil, fmt.Errorf("%w: cannot include %q", domain.ErrInvalidFields, name)
+			}
+		}
+	}
+
+	for _, value := range fields {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if set.favourite == nil {
+				set.favourite = make(map[string]bool)
+			}
+			if assetField, ok := strings.CutPrefix(name, "asset."); ok {
+				if !assetFields[assetField] {
+					return nil, fmt.Errorf("%w: unknown asset field %q", domain.ErrInvalidFields, assetField)
+				}
+				if set.asset == nil {
+					set.asset = make(map[string]bool)
+				}
+				set.asset[assetField] = true
+				set.withAsset = true
+				continue
+			}
+			if !favouriteFields[name] {
+				return nil, fmt.Errorf("%w: unknown favourite field %q", domain.ErrInvalidFields, name)
+			}
+			set.favourite[name] = true
+			if name == "asset" {
+				set.withAsset = true
+			}
+		}
+	}
+	if set.favourite == nil {
+		set.withAsset = true // as favourites have always been listed
+	} else if set.withAsset {
+		set.favourite["asset"] = true
+	}
+	return set, nil
+}
+
+// sparse reports whether encoding needs to drop any fields
+func (s *favouriteFieldSet) sparse() bool {
+	return s.favourite != nil || s.asset != nil || !s.withAsset
+}
+
+// encode renders a favourite with the selected fields only
+func (s *favouriteFieldSet) encode(fav *domain.Favourite) (json.RawMessage, error) {
+	obj, err := selectFields(fav, func(name string) bool {
+		if name == "asset" {
+			return s.withAsset
+		}
+		return s.favourite == nil || s.favourite[name]
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.withAsset && s.asset != nil && fav.Asset != nil {
+		asset, err := selectFields(fav.Asset, func(name string) bool { return s.asset[name] })
+		if err != nil {
+			return nil, err
+		}
+		if obj["asset"], err = json.Marshal(asset); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// selectFields encodes v as a JSON object and keeps the members accepted by keep
+func selectFields(v any, keep func(string) bool) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	for name := range obj {
+		if !keep(name) {
+			delete(obj, name)
+		}
+	}
+	return obj, nil
+}
@@ -4,15 +4,21 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/middleware"
+	"github.com/gioannid/platform-go-challenge/internal/problem"
 	"github.com/gioannid/platform-go-challenge/internal/service"
+	"github.com/google/uuid"
 )
 
 // Handler holds all HTTP handlers
 type Handler struct {
 	service *service.FavouriteService
+	live    liveConnections
 }
 
 // NewHandler creates a new handler instance
@@ -34,36 +40,50 @@ type Response struct {
 type ErrorResponse struct {
 	Success bool   `json:"success" example:"false"`
 	Error   string `json:"error" example:"error message"`
+	Code    string `json:"code" example:"internal_server_error"`
 }
 
 // BadRequestError represents a 400 error
 type BadRequestError struct {
-	Success bool   `json:"success" example:"false"`
-	Error   string `json:"error" example:"unexpected end of JSON input"`
+	Success bool                    `json:"success" example:"false"`
+	Error   string                  `json:"error" example:"unexpected end of JSON input"`
+	Code    string                  `json:"code" example:"invalid_json"`
+	Details []domain.FieldViolation `json:"details,omitempty"`
 }
 
 // InvalidUUIDError represents invalid UUID format error
 type InvalidUUIDError struct {
 	Success bool   `json:"success" example:"false"`
 	Error   string `json:"error" example:"invalid UUID length: 10"`
+	Code    string `json:"code" example:"bad_request"`
 }
 
 // NotFoundError represents a 404 error
 type NotFoundError struct {
 	Success bool   `json:"success" example:"false"`
 	Error   string `json:"error" example:"resource not found"`
+	Code    string `json:"code" example:"not_found"`
+}
+
+// ForbiddenError represents a 403 error
+type ForbiddenError struct {
+	Success bool   `json:"success" example:"false"`
+	Error   string `json:"error" example:"forbidden"`
+	Code    string `json:"code" example:"forbidden"`
 }
 
 // ConflictError represents a 409 error
 type ConflictError struct {
 	Success bool   `json:"success" example:"false"`
 	Error   string `json:"error" example:"resource already exists"`
+	Code    string `json:"code" example:"already_exists"`
 }
 
 // InternalServerError represents a 500 error
 type InternalServerError struct {
 	Success bool   `json:"success" example:"false"`
 	Error   string `json:"error" example:"internal server error"`
+	Code    string `json:"code" example:"internal_server_error"`
 }
 
 // SuccessResponse represents a success response with message
@@ -85,12 +105,10 @@ func respondJSON(w http.ResponseWriter, statusCode int, payload interface{}) {
 	json.NewEncoder(w).Encode(payload)
 }
 
-// respondError sends an error response
-func respondError(w http.ResponseWriter, statusCode int, err error) {
-	respondJSON(w, statusCode, Response{
-		Success: false,
-		Error:   err.Error(),
-	})
+// respondError sends an error response, as problem details or in the legacy envelope depending on
+// what the client accepts
+func respondError(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	problem.Write(w, r, statusCode, err)
 }
 
 // respondSuccess sends a success response
@@ -102,12 +120,33 @@ func respondSuccess(w http.ResponseWriter, statusCode int, data interface{}, mes
 	})
 }
 
+// parseOptionalInt parses an optional integer query parameter, returning 0 when absent
+func parseOptionalInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer %q", value)
+	}
+	return n, nil
+}
+
+// authorizeUser checks that an authenticated user only acts as themselves
+func authorizeUser(r *http.Request, userID uuid.UUID) error {
+	if authenticated, ok := middleware.GetUserIDFromContext(r.Context()); ok && authenticated != userID {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
 // mapDomainError maps domain errors to HTTP status codes
 func mapDomainError(err error) int {
 	switch {
 	case errors.Is(err, domain.ErrNotFound):
 		return http.StatusNotFound
-	case errors.Is(err, domain.ErrAlreadyExists):
+	case errors.Is(err, domain.ErrAlreadyExists),
+		errors.Is(err, domain.ErrAssetReferenced):
 		return http.StatusConflict
 	case errors.Is(err, domain.ErrUnauthorized):
 		return http.StatusUnauthorized
@@ -117,7 +156,24 @@ func mapDomainError(err error) int {
 		errors.Is(err, domain.ErrMissingAssetData),
 		errors.Is(err, domain.ErrInvalidChartData),
 		errors.Is(err, domain.ErrInvalidInsightData),
-		errors.Is(err, domain.ErrInvalidAudienceData):
+		errors.Is(err, domain.ErrInvalidAudienceData),
+		errors.Is(err, domain.ErrAssetTypeMismatch),
+		errors.Is(err, domain.ErrInvalidRespondentProfile),
+		errors.Is(err, domain.ErrInvalidRenderOptions),
+		errors.Is(err, domain.ErrInvalidReference),
+		errors.Is(err, domain.ErrInvalidTag),
+		errors.Is(err, domain.ErrInvalidSearchQuery),
+		errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrInvalidFilter),
+		errors.Is(err, domain.ErrInvalidFields),
+		errors.Is(err, domain.ErrInvalidCollection),
+		errors.Is(err, domain.ErrInvalidPosition),
+		errors.Is(err, domain.ErrInvalidAnnotation),
+		errors.Is(err, domain.ErrInvalidBatch),
+		errors.Is(err, domain.ErrInvalidShare),
+		errors.Is(err, domain.ErrInvalidWindow),
+		errors.Is(err, domain.ErrInvalidWebhook),
+		errors.Is(err, domain.ErrInvalidAuditQuery):
 		return http.StatusBadRequest
 	default:
 		return http.StatusInternalServerError
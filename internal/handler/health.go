@@ -28,7 +28,7 @@ func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 //	@Router			/../../readyz [get]
 func (h *Handler) ReadinessCheck(w http.ResponseWriter, r *http.Request) {
 	if err := h.service.HealthCheck(r.Context()); err != nil {
-		respondError(w, http.StatusServiceUnavailable, err)
+		respondError(w, r, http.StatusServiceUnavailable, err)
 		return
 	}
 
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// RecommendationsResponse represents the assets suggested to a user, best first
+type RecommendationsResponse struct {
+	Recommendations []domain.Recommendation `json:"recommendations"`
+}
+
+// Recommendations handles GET /users/{userId}/recommendations
+//
+//		@Summary		Recommend assets
+//		@Description	Suggest assets the user has not favourited yet, ranked by how often other users favourited them
+//		@Description	together with the user's favourites. Recent favourites weigh more (their weight halves every 90
+//		@Description	days), and so do assets of the same type as the favourite. Each suggestion lists the user's
+//		@Description	favourited assets it is based on, most influential first. Users without favourites get none.
+//		@Tags			favourites
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string	true	"User ID (UUID)"
+//		@Param			limit	query		int		false	"Number of suggestions"	default(100)
+//		@Success		200		{object}	Response{data=RecommendationsResponse}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/recommendations [get]
+func (h *Handler) Recommendations(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := parseOptionalInt(r.URL.Query().Get("limit"))
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	recommendations, err := h.service.Recommendations(r.Context(), userID, limit)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, RecommendationsResponse{Recommendations: recommendations}, "")
+}
@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// ShareRequest represents the request to share a user's favourites, or one of the user's collections
+type ShareRequest struct {
+	CollectionID *uuid.UUID             `json:"collection_id,omitempty"`
+	UserIDs      []uuid.UUID            `json:"user_ids,omitempty"`
+	Link         bool                   `json:"link,omitempty"`
+	Permission   domain.SharePermission `json:"permission,omitempty" example:"read"`
+}
+
+// RedeemShareRequest represents the request to redeem a share link token
+type RedeemShareRequest struct {
+	Token string `json:"token"`
+}
+
+// ListSharesResponse represents a list of shares
+type ListSharesResponse struct {
+	Shares []*domain.Share `json:"shares"`
+}
+
+// parseUserShare parses the userId and shareId path parameters
+func parseUserShare(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(r)
+	userID, err := uuid.Parse(vars["userId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	shareID, err := uuid.Parse(vars["shareId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return userID, shareID, nil
+}
+
+// CreateShare handles POST /users/{userId}/shares
+//
+//		@Summary		Share favourites
+//		@Description	Share the user's favourites, or one of the user's collections, read only or editable. Access is
+//		@Description	granted to the given user IDs and, with link set, to anyone redeeming the returned link token.
+//		@Description	When authenticated, users may only manage their own shares and use those shared with them;
+//		@Description	link tokens are only shown to the share's owner.
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string			true	"User ID (UUID)"
+//		@Param			request	body		ShareRequest	true	"Share details"
+//		@Success		201		{object}	Response{data=domain.Share}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		403		{object}	ForbiddenError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/shares [post]
+func (h *Handler) CreateShare(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	var req ShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	share, err := h.service.ShareFavourites(r.Context(), userID, req.CollectionID, req.UserIDs, req.Link, req.Permission)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusCreated, share, "Favourites shared successfully")
+}
+
+// ListShares handles GET /users/{userId}/shares
+//
+//		@Summary		List shares
+//		@Description	Get the shares the user created, oldest first, with their recipients and link tokens
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string	true	"User ID (UUID)"
+//		@Success		200		{object}	Response{data=ListSharesResponse}
+//		@Failure		400		{object}	InvalidUUIDError
+//		@Failure		403		{object}	ForbiddenError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/shares [get]
+func (h *Handler) ListShares(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	shares, err := h.service.ListShares(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, ListSharesResponse{Shares: shares}, "")
+}
+
+// RevokeShare handles DELETE /users/{userId}/shares/{shareId}
+//
+//		@Summary		Revoke share
+//		@Description	Delete one of the user's shares. Its recipients lose access and its link token stops working.
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string	true	"User ID (UUID)"
+//		@Param			shareId	path		string	true	"Share ID (UUID)"
+//		@Success		200		{object}	SuccessResponse
+//		@Failure		400		{object}	InvalidUUIDError
+//		@Failure		403		{object}	ForbiddenError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/shares/{shareId} [delete]
+func (h *Handler) RevokeShare(w http.ResponseWriter, r *http.Request) {
+	userID, shareID, err := parseUserShare(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	if err := h.service.RevokeShare(r.Context(), userID, shareID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, nil, "Share revoked successfully")
+}
+
+// SharedWithMe handles GET /users/{userId}/shared
+//
+//		@Summary		List shares with me
+//		@Description	Get the shares other users granted to the user, oldest first
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string	true	"User ID (UUID)"
+//		@Success		200		{object}	Response{data=ListSharesResponse}
+//		@Failure		400		{object}	InvalidUUIDError
+//		@Failure		403		{object}	ForbiddenError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/shared [get]
+func (h *Handler) SharedWithMe(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	shares, err := h.service.SharedWithMe(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, ListSharesResponse{Shares: shares}, "")
+}
+
+// RedeemShareLink handles POST /users/{userId}/shared
+//
+//		@Summary		Redeem share link
+//		@Description	Gain access to the share behind a link token; the share then shows among those shared with the user
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string				true	"User ID (UUID)"
+//		@Param			request	body		RedeemShareRequest	true	"Link token"
+//		@Success		200		{object}	Response{data=domain.Share}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		403		{object}	ForbiddenError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/shared [post]
+func (h *Handler) RedeemShareLink(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	var req RedeemShareRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	share, err := h.service.RedeemShareLink(r.Context(), userID, req.Token)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, share, "Share link redeemed successfully")
+}
+
+// ListSharedFavourites handles GET /users/{userId}/shared/{shareId}/favourites
+//
+//		@Summary		List shared favourites
+//		@Description	Get a page of the favourites shared with the user. Pagination, sorting, filtering and field
+//		@Description	selection work as for GET /users/{userId}/favourites.
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId		path		string		true	"User ID (UUID)"
+//		@Param			shareId		path		string		true	"Share ID (UUID)"
+//		@Param			limit		query		int			false	"Number of items per page"	default(20)
+//		@Param			offset		query		int			false	"Number of items to skip"	default(0)
+//		@Param			sortBy		query		string		false	"Sort field"				Enums(created_at, updated_at, type, description, position)
+//		@Param			order		query		string		false	"Sort order"				Enums(asc, desc)
+//		@Param			cursor		query		string		false	"Cursor from a previous page (overrides offset, sortBy and order)"
+//		@Param			tag			query		[]string	false	"Only favourites of assets with these tags"	collectionFormat(multi)
+//		@Param			tagMatch	query		string		false	"Require all tags (AND) or any tag (OR)"	Enums(all, any)	default(all)
+//		@Param			filter		query		[]string	false	"Filter expression"	collectionFormat(multi)
+//		@Param			include		query		string		false	"Embed related resources"	Enums(asset)
+//		@Param			fields		query		string		false	"Comma separated favourite fields to return"
+//		@Success		200			{object}	Response{data=ListFavouritesResponse}
+//		@Failure		400			{object}	InvalidUUIDError
+//		@Failure		403			{object}	ForbiddenError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/users/{userId}/shared/{shareId}/favourites [get]
+func (h *Handler) ListSharedFavourites(w http.ResponseWriter, r *http.Request) {
+	userID, shareID, err := parseUserShare(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	query, fields, err := parseFavouriteListQuery(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	favourites, page, err := h.service.ListSharedFavourites(r.Context(), userID, shareID, query)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, newListFavouritesResponse(favourites, page, query, fields), "")
+}
+
+// AddSharedFavourite handles POST /users/{userId}/shared/{shareId}/favourites
+//
+//		@Summary		Add shared favourite
+//		@Description	Favourite an asset on behalf of the owner of an editable share. With a collection share the
+//		@Description	favourite is also put into the collection.
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string				true	"User ID (UUID)"
+//		@Param			shareId	path		string				true	"Share ID (UUID)"
+//		@Param			request	body		AddFavouriteRequest	true	"Favourite details"
+//		@Success		201		{object}	Response{data=domain.Favourite}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		403		{object}	ForbiddenError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		409		{object}	ConflictError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/shared/{shareId}/favourites [post]
+func (h *Handler) AddSharedFavourite(w http.ResponseWriter, r *http.Request) {
+	userID, shareID, err := parseUserShare(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	var req AddFavouriteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	favourite, err := h.service.AddSharedFavourite(r.Context(), userID, shareID, req.AssetID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusCreated, favourite, "Favourite added successfully")
+}
+
+// RemoveSharedFavourite handles DELETE /users/{userId}/shared/{shareId}/favourites/{favouriteId}
+//
+//		@Summary		Remove shared favourite
+//		@Description	Remove a favourite on behalf of the owner of an editable share. With a collection share the
+//		@Description	favourite is only taken out of the collection.
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId		path		string	true	"User ID (UUID)"
+//		@Param			shareId		path		string	true	"Share ID (UUID)"
+//		@Param			favouriteId	path		string	true	"Favourite ID (UUID)"
+//		@Success		200			{object}	SuccessResponse
+//		@Failure		400			{object}	InvalidUUIDError
+//		@Failure		403			{object}	ForbiddenError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/users/{userId}/shared/{shareId}/favourites/{favouriteId} [delete]
+func (h *Handler) RemoveSharedFavourite(w http.ResponseWriter, r *http.Request) {
+	userID, shareID, err := parseUserShare(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	favouriteID, err := uuid.Parse(mux.Vars(r)["favouriteId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.RemoveSharedFavourite(r.Context(), userID, shareID, favouriteID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, nil, "Favourite removed successfully")
+}
+
+// CopySharedFavourites handles POST /users/{userId}/shared/{shareId}/copy
+//
+//		@Summary		Copy shared favourites
+//		@Description	Favourite all the assets shared with the user. Answered with 207 Multi-Status like a batch
+//		@Description	of favourites: assets the user has favourited already are reported as already_exists. Shares
+//		@Description	larger than a batch (MAX_BATCH_ITEMS) are copied batch by batch, with all the results merged.
+//		@Tags			shares
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			userId	path		string	true	"User ID (UUID)"
+//		@Param			shareId	path		string	true	"Share ID (UUID)"
+//		@Success		207		{object}	Response{data=BatchFavouritesResponse}
+//		@Failure		400		{object}	InvalidUUIDError
+//		@Failure		403		{object}	ForbiddenError
+//		@Failure		404		{object}	NotFoundError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/users/{userId}/shared/{shareId}/copy [post]
+func (h *Handler) CopySharedFavourites(w http.ResponseWriter, r *http.Request) {
+	userID, shareID, err := parseUserShare(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	results, err := h.service.CopySharedFavourites(r.Context(), userID, shareID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusMultiStatus, newBatchFavouritesResponse(results), "")
+}
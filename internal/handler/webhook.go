@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// CreateWebhookRequest represents the request to subscribe a URL to events
+type CreateWebhookRequest struct {
+	URL        string             `json:"url" example:"https://analytics.example.com/hooks"`
+	EventTypes []domain.EventType `json:"event_types" example:"asset_created,favourite_added"`
+	Secret     string             `json:"secret" example:"a-secret-of-16-characters-or-more"`
+}
+
+// ListWebhooksResponse represents a list of webhooks
+type ListWebhooksResponse struct {
+	Webhooks []*domain.Webhook `json:"webhooks"`
+}
+
+// ListDeliveriesResponse represents the delivery history of a webhook
+type ListDeliveriesResponse struct {
+	Deliveries []*domain.WebhookDelivery `json:"deliveries"`
+}
+
+// parseWebhookDelivery parses the webhookId and deliveryId path parameters
+func parseWebhookDelivery(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	vars := mux.Vars(r)
+	webhookID, err := uuid.Parse(vars["webhookId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	deliveryID, err := uuid.Parse(vars["deliveryId"])
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return webhookID, deliveryID, nil
+}
+
+// CreateWebhook handles POST /webhooks
+//
+//		@Summary		Create webhook
+//		@Description	Subscribe a URL to asset and favourite events. Each delivery is a POST of the event as JSON, with
+//		@Description	the X-Webhook-Signature header set to "sha256=" and the hex HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>"
+//		@Description	keyed with the secret. Failed deliveries are retried with exponential backoff, then left dead.
+//		@Description	URLs may not target loopback, private or link-local addresses. Webhooks receive every user's
+//		@Description	events, so all webhook routes are for administrators only: tokens must carry an "admin": true claim.
+//		@Tags			webhooks
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			request	body		CreateWebhookRequest	true	"Webhook URL, event types and secret"
+//		@Success		201		{object}	Response{data=domain.Webhook}
+//		@Failure		400		{object}	BadRequestError
+//		@Failure		403		{object}	ForbiddenError
+//		@Failure		500		{object}	InternalServerError
+//		@Router			/webhooks [post]
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(r.Context(), req.URL, req.EventTypes, req.Secret)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusCreated, webhook, "Webhook created successfully")
+}
+
+// ListWebhooks handles GET /webhooks
+//
+//		@Summary		List webhooks
+//		@Description	Get all webhooks, oldest first. Secrets are never returned.
+//		@Tags			webhooks
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Success		200	{object}	Response{data=ListWebhooksResponse}
+//		@Failure		403	{object}	ForbiddenError
+//		@Failure		500	{object}	InternalServerError
+//		@Router			/webhooks [get]
+func (h *Handler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	webhooks, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, ListWebhooksResponse{Webhooks: webhooks}, "")
+}
+
+// GetWebhook handles GET /webhooks/{webhookId}
+//
+//		@Summary		Get webhook
+//		@Description	Get a webhook by ID
+//		@Tags			webhooks
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			webhookId	path		string	true	"Webhook ID (UUID)"
+//		@Success		200			{object}	Response{data=domain.Webhook}
+//		@Failure		400			{object}	InvalidUUIDError
+//		@Failure		403			{object}	ForbiddenError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/webhooks/{webhookId} [get]
+func (h *Handler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	webhookID, err := uuid.Parse(mux.Vars(r)["webhookId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	webhook, err := h.service.GetWebhook(r.Context(), webhookID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, webhook, "")
+}
+
+// DeleteWebhook handles DELETE /webhooks/{webhookId}
+//
+//		@Summary		Delete webhook
+//		@Description	Delete a webhook together with its delivery history; pending deliveries are dropped
+//		@Tags			webhooks
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			webhookId	path		string	true	"Webhook ID (UUID)"
+//		@Success		200			{object}	SuccessResponse
+//		@Failure		400			{object}	InvalidUUIDError
+//		@Failure		403			{object}	ForbiddenError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/webhooks/{webhookId} [delete]
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	webhookID, err := uuid.Parse(mux.Vars(r)["webhookId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if err := h.service.DeleteWebhook(r.Context(), webhookID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, nil, "Webhook deleted successfully")
+}
+
+// ListWebhookDeliveries handles GET /webhooks/{webhookId}/deliveries
+//
+//		@Summary		List webhook deliveries
+//		@Description	Get the delivery history of a webhook, newest first, with every attempt made. Pending deliveries
+//		@Description	and dead letters are kept until delivered; only the latest succeeded deliveries are.
+//		@Tags			webhooks
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			webhookId	path		string	true	"Webhook ID (UUID)"
+//		@Param			status		query		string	false	"Only deliveries with this status"	Enums(pending, succeeded, dead)
+//		@Success		200			{object}	Response{data=ListDeliveriesResponse}
+//		@Failure		400			{object}	BadRequestError
+//		@Failure		403			{object}	ForbiddenError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/webhooks/{webhookId}/deliveries [get]
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	h.listDeliveries(w, r, domain.DeliveryStatus(r.URL.Query().Get("status")))
+}
+
+// ListDeadLetters handles GET /webhooks/{webhookId}/dead-letters
+//
+//		@Summary		List webhook dead letters
+//		@Description	Get the deliveries of a webhook that failed every attempt, newest first
+//		@Tags			webhooks
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			webhookId	path		string	true	"Webhook ID (UUID)"
+//		@Success		200			{object}	Response{data=ListDeliveriesResponse}
+//		@Failure		400			{object}	InvalidUUIDError
+//		@Failure		403			{object}	ForbiddenError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/webhooks/{webhookId}/dead-letters [get]
+func (h *Handler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	h.listDeliveries(w, r, domain.DeliveryDead)
+}
+
+func (h *Handler) listDeliveries(w http.ResponseWriter, r *http.Request, status domain.DeliveryStatus) {
+	webhookID, err := uuid.Parse(mux.Vars(r)["webhookId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	deliveries, err := h.service.ListWebhookDeliveries(r.Context(), webhookID, status)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusOK, ListDeliveriesResponse{Deliveries: deliveries}, "")
+}
+
+// RedeliverWebhook handles POST /webhooks/{webhookId}/deliveries/{deliveryId}/redeliver
+//
+//		@Summary		Redeliver dead letter
+//		@Description	Give a dead delivery a new round of attempts. Only dead letters can be redelivered.
+//		@Tags			webhooks
+//		@Accept			json
+//		@Produce		json
+//	 @Security BearerAuth
+//		@Param			webhookId	path		string	true	"Webhook ID (UUID)"
+//		@Param			deliveryId	path		string	true	"Delivery ID (UUID)"
+//		@Success		202			{object}	Response{data=domain.WebhookDelivery}
+//		@Failure		400			{object}	BadRequestError
+//		@Failure		403			{object}	ForbiddenError
+//		@Failure		404			{object}	NotFoundError
+//		@Failure		500			{object}	InternalServerError
+//		@Router			/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver [post]
+func (h *Handler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := authorizeAdmin(r); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	webhookID, deliveryID, err := parseWebhookDelivery(r)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	delivery, err := h.service.RedeliverWebhook(r.Context(), webhookID, deliveryID)
+	if err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+
+	respondSuccess(w, http.StatusAccepted, delivery, "Delivery scheduled")
+}
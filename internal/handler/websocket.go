@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/middleware"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+)
+
+// Live connection settings
+const (
+	liveWriteWait      = 10 * time.Second      // Time allowed to write a message to the client
+	livePongWait       = 60 * time.Second      // Time allowed to read the next pong from the client
+	livePingPeriod     = livePongWait * 9 / 10 // Heartbeat period, shorter than livePongWait
+	liveMaxMessageSize = 64 * 1024             // Largest message accepted from the client
+	liveCloseWait      = time.Second           // Time allowed to the client to acknowledge closing
+)
+
+// Messages of live connections
+const (
+	LiveSubscribe   = "subscribe"
+	LiveUnsubscribe = "unsubscribe"
+	LiveSubscribed  = "subscribed"
+	LiveEvent       = "event"
+	LiveError       = "error"
+)
+
+// liveUpgrader upgrades live connections; clients authenticate with a bearer token rather than
+// cookies, so that connections from any origin are accepted. Clients passing the token as
+// subprotocol get middleware.WebSocketProtocol selected, as browsers require.
+var liveUpgrader = websocket.Upgrader{
+	CheckOrigin:  func(*http.Request) bool { return true },
+	Subprotocols: []string{middleware.WebSocketProtocol},
+}
+
+// LiveRequest is a message from the client, subscribing to or unsubscribing from asset events
+type LiveRequest struct {
+	Type     string      `json:"type" enums:"subscribe,unsubscribe" example:"subscribe"`
+	AssetIDs []uuid.UUID `json:"asset_ids"`
+}
+
+// LiveMessage is a message to the client: an event, the assets subscribed to after a request, or
+// the error a request failed with
+type LiveMessage struct {
+	Type     string        `json:"type" enums:"event,subscribed,error" example:"event"`
+	Event    *domain.Event `json:"event,omitempty"`
+	AssetIDs []uuid.UUID   `json:"asset_ids,omitempty"`
+	NotFound []uuid.UUID   `json:"not_found,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// liveConnections tracks the open live connections, which http.Server no longer does once hijacked
+type liveConnections struct {
+	mu      sync.Mutex
+	closing bool
+	open    sync.WaitGroup
+}
+
+// add registers a new connection, unless closing
+func (c *liveConnections) add() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closing {
+		return false
+	}
+	c.open.Add(1)
+	return true
+}
+
+// wait refuses new connections and waits until the open ones are closed
+func (c *liveConnections) wait(ctx context.Context) error {
+	c.mu.Lock()
+	c.closing = true
+	c.mu.Unlock()
+
+	closed := make(chan struct{})
+	go func() {
+		c.open.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// watchedAssets is the set of assets a live connection subscribed to
+type watchedAssets struct {
+	mu     sync.RWMutex
+	assets map[uuid.UUID]struct{}
+}
+
+func (w *watchedAssets) contains(assetID uuid.UUID) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	_, ok := w.assets[assetID]
+	return ok
+}
+
+// update adds or removes assets, returning the resulting set
+func (w *watchedAssets) update(assetIDs []uuid.UUID, watch bool) []uuid.UUID {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, assetID := range assetIDs {
+		if watch {
+			w.assets[assetID] = struct{}{}
+		} else {
+			delete(w.assets, assetID)
+		}
+	}
+	watched := make([]uuid.UUID, 0, len(w.assets))
+	for assetID := range w.assets {
+		watched = append(watched, assetID)
+	}
+	return watched
+}
+
+// Live handles GET /users/{userId}/live
+//
+//		@Summary		Live updates over WebSocket
+//		@Description	Upgrade to a WebSocket connection streaming the changes to the user's favourites, and to the
+//		@Description	assets subscribed to, as {"type":"event","event":{...}} messages. Send
+//		@Description	{"type":"subscribe","asset_ids":[...]} or {"type":"unsubscribe","asset_ids":[...]} to change
+//		@Description	the subscribed assets; each request is answered with the subscribed assets, and unknown ones
+//		@Description	as not_found. The server pings every 54 seconds and closes connections not answering within
+//		@Description	60 seconds, or falling too far behind (close code 1013, to reconnect and reload). Browsers
+//		@Description	may pass the JWT as subprotocol after "bearer", as in new WebSocket(url, ["bearer", token]),
+//		@Description	or as access_token query parameter. When authenticated, users may only watch their own
+//		@Description	favourites.
+//		@Tags			favourites
+//	 @Security BearerAuth
+//		@Param			userId			path		string	true	"User ID (UUID)"
+//		@Param			access_token	query		string	false	"JWT, for clients unable to set headers"
+//		@Success		101				{object}	LiveMessage
+//		@Failure		400				{object}	BadRequestError
+//		@Failure		403				{object}	ForbiddenError
+//		@Failure		503				{object}	ErrorResponse
+//		@Router			/users/{userId}/live [get]
+func (h *Handler) Live(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(mux.Vars(r)["userId"])
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	if err := authorizeUser(r, userID); err != nil {
+		respondError(w, r, mapDomainError(err), err)
+		return
+	}
+	if !h.live.add() {
+		respondError(w, r, http.StatusServiceUnavailable, errors.New("server is shutting down"))
+		return
+	}
+	defer h.live.open.Done()
+
+	conn, err := liveUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // the upgrader replied already
+	}
+
+	watched := &watchedAssets{assets: make(map[uuid.UUID]struct{})}
+	sub := h.service.WatchEvents(userID, watched.contains)
+	defer sub.Close()
+
+	replies := make(chan LiveMessage, 1)
+	readerDone := make(chan struct{})
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		h.readLive(r.Context(), conn, watched, replies, writerDone)
+	}()
+	// The connection only counts as closed once the reader is done with it
+	defer func() {
+		close(writerDone)
+		conn.Close()
+		<-readerDone
+	}()
+
+	heartbeat := time.NewTicker(livePingPeriod)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-readerDone:
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				closeLive(conn, sub.Dropped(), readerDone)
+				return
+			}
+			err = writeLive(conn, LiveMessage{Type: LiveEvent, Event: &event})
+		case reply := <-replies:
+			err = writeLive(conn, reply)
+		case <-heartbeat.C:
+			err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(liveWriteWait))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// readLive handles the client's requests until the connection fails or is closed, replying
+// through the writer so that a single goroutine writes to the connection
+func (h *Handler) readLive(ctx context.Context, conn *websocket.Conn, watched *watchedAssets, replies chan<- LiveMessage, writerDone <-chan struct{}) {
+	conn.SetReadLimit(liveMaxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(livePongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(livePongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		reply := h.handleLiveRequest(ctx, data, watched)
+		select {
+		case replies <- reply:
+		case <-writerDone:
+			return
+		}
+	}
+}
+
+// handleLiveRequest applies a subscribe or unsubscribe request
+func (h *Handler) handleLiveRequest(ctx context.Context, data []byte, watched *watchedAssets) LiveMessage {
+	var req LiveRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return LiveMessage{Type: LiveError, Error: err.Error()}
+	}
+
+	switch req.Type {
+	case LiveSubscribe:
+		found, notFound, err := h.service.ExistingAssets(ctx, req.AssetIDs)
+		if err != nil {
+			return LiveMessage{Type: LiveError, Error: err.Error()}
+		}
+		return LiveMessage{Type: LiveSubscribed, AssetIDs: watched.update(found, true), NotFound: notFound}
+	case LiveUnsubscribe:
+		return LiveMessage{Type: LiveSubscribed, AssetIDs: watched.update(req.AssetIDs, false)}
+	default:
+		return LiveMessage{Type: LiveError, Error: "unknown message type " + req.Type}
+	}
+}
+
+// writeLive writes a message, failing for clients not reading it in time
+func writeLive(conn *websocket.Conn, message LiveMessage) error {
+	conn.SetWriteDeadline(time.Now().Add(liveWriteWait))
+	return conn.WriteJSON(message)
+}
+
+// closeLive closes a connection whose events ended, because the server shuts down or because the
+// client fell behind, and waits for the client to acknowledge
+func closeLive(conn *websocket.Conn, dropped bool, readerDone <-chan struct{}) {
+	message := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	if dropped {
+		message = websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow")
+	}
+	if err := conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(liveWriteWait)); err != nil {
+		return
+	}
+	select {
+	case <-readerDone:
+	case <-time.After(liveCloseWait):
+	}
+}
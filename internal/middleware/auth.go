@@ -2,74 +2,157 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strings"
 
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/problem"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 type contextKey string
 
-const UserIDKey contextKey = "user_id"
+const (
+	UserIDKey contextKey = "user_id"
+	AdminKey  contextKey = "admin"
+)
+
+// WebSocketProtocol is the subprotocol WebSocket clients offer along with their token, as in
+// new WebSocket(url, ["bearer", token]), since browsers cannot set headers on upgrade requests
+const WebSocketProtocol = "bearer"
 
-// JWTAuth performs JWT authentication: it validates JWT tokens and extracts user ID
+// Authentication errors. They tell what is wrong with a token, but not the parser's reasons.
+var (
+	ErrMissingToken     = &domain.Error{Code: "missing_token", Message: "Missing authorization header"}
+	ErrMalformedToken   = &domain.Error{Code: "malformed_authorization", Message: "Invalid authorization header format"}
+	ErrInvalidToken     = &domain.Error{Code: "invalid_token", Message: "Invalid token"}
+	ErrExpiredToken     = &domain.Error{Code: "expired_token", Message: "Token has expired"}
+	ErrInvalidTokenUser = &domain.Error{Code: "invalid_token_user", Message: "Missing or invalid user_id in token"}
+)
+
+// JWTAuth performs JWT authentication: it validates JWT tokens and extracts user ID, along with
+// whether the user is an administrator (an "admin": true claim). Browsers
+// cannot set headers on WebSocket upgrade requests, which may pass the token as subprotocol after
+// WebSocketProtocol instead, or as access_token query parameter, which Logger redacts.
 func JWTAuth(secretKey string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Extract token from Authorization header
 			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" && isWebSocketUpgrade(r) {
+				if token := webSocketToken(r); token != "" {
+					authHeader = "Bearer " + token
+				}
+			}
 			if authHeader == "" {
-				http.Error(w, "Missing authorization header", http.StatusUnauthorized)
+				problem.Write(w, r, http.StatusUnauthorized, ErrMissingToken)
 				return
 			}
 
 			parts := strings.Split(authHeader, " ")
 			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
-				return
-			}
-
-			tokenString := parts[1]
-
-			// Parse and validate token
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				return []byte(secretKey), nil
-			})
-
-			if err != nil || !token.Valid {
-				http.Error(w, "Invalid token: "+err.Error(), http.StatusUnauthorized)
+				problem.Write(w, r, http.StatusUnauthorized, ErrMalformedToken)
 				return
 			}
 
-			// Extract user ID from claims
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-				return
-			}
-
-			userIDStr, ok := claims["user_id"].(string)
-			if !ok {
-				http.Error(w, "Missing user_id in token", http.StatusUnauthorized)
+			claims, err := parseClaims(secretKey, parts[1])
+			if err != nil {
+				problem.Write(w, r, http.StatusUnauthorized, err)
 				return
 			}
-
-			userID, err := uuid.Parse(userIDStr)
+			userID, err := claimedUserID(claims)
 			if err != nil {
-				http.Error(w, "Invalid user_id format", http.StatusUnauthorized)
+				problem.Write(w, r, http.StatusUnauthorized, err)
 				return
 			}
 
-			// Add user ID to context
+			// Add user ID to context, also as the actor of the changes the request makes
 			ctx := context.WithValue(r.Context(), UserIDKey, userID)
+			admin, _ := claims["admin"].(bool)
+			ctx = context.WithValue(ctx, AdminKey, admin)
+			actor := domain.ActorFromContext(ctx)
+			actor.UserID = &userID
+			ctx = domain.WithActor(ctx, actor)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// ParseToken validates a JWT token and returns the ID of the user it was issued to
+func ParseToken(secretKey, tokenString string) (uuid.UUID, error) {
+	claims, err := parseClaims(secretKey, tokenString)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return claimedUserID(claims)
+}
+
+// parseClaims validates a JWT token and returns its claims
+func parseClaims(secretKey, tokenString string) (jwt.MapClaims, error) {
+	// Parse and validate token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secretKey), nil
+	})
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrExpiredToken
+	}
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// claimedUserID extracts the user ID from a token's claims
+func claimedUserID(claims jwt.MapClaims) (uuid.UUID, error) {
+	userIDStr, ok := claims["user_id"].(string)
+	if !ok {
+		return uuid.Nil, ErrInvalidTokenUser
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return uuid.Nil, ErrInvalidTokenUser
+	}
+	return userID, nil
+}
+
+// isWebSocketUpgrade tells whether a request asks to upgrade to the WebSocket protocol
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// webSocketToken returns the token of a WebSocket upgrade request: the subprotocol offered after
+// WebSocketProtocol, or else the access_token query parameter
+func webSocketToken(r *http.Request) string {
+	var protocols []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			protocols = append(protocols, strings.TrimSpace(protocol))
+		}
+	}
+	for i := 0; i+1 < len(protocols); i++ {
+		if protocols[i] == WebSocketProtocol {
+			return protocols[i+1]
+		}
+	}
+	return r.URL.Query().Get("access_token")
+}
+
 // GetUserIDFromContext extracts user ID from context
 func GetUserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	userID, ok := ctx.Value(UserIDKey).(uuid.UUID)
 	return userID, ok
 }
+
+// IsAdmin tells whether the authenticated user is an administrator
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(AdminKey).(bool)
+	return admin
+}
@@ -0,0 +1,283 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/problem"
+	"github.com/google/uuid"
+)
+
+const (
+	// IdempotencyKeyHeader lets clients retry a POST without repeating its effect
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses replayed for a retried request
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBody bounds the body of requests with a key, read whole to be fingerprinted
+	maxIdempotentBody = 1 << 20
+)
+
+// Idempotency errors
+var (
+	ErrIdempotencyKeyTooLong = &domain.Error{Code: "idempotency_key_too_long", Message: "Idempotency-Key too long"}
+	ErrIdempotencyKeyReused  = &domain.Error{Code: "idempotency_key_reused", Message: "Idempotency-Key reused with a different request"}
+	ErrUnreadableBody        = &domain.Error{Code: "unreadable_body", Message: "Failed to read request body"}
+	ErrBodyTooLarge          = &domain.Error{Code: "body_too_large", Message: "Request body too large"}
+)
+
+// IdempotencyPolicy tells how long and how many responses are kept for replay
+type IdempotencyPolicy struct {
+	Window   time.Duration // Time a response is kept; zero disables the middleware
+	MaxKeys  int           // Responses kept at most, the oldest forgotten first; zero for no bound
+	MaxBytes int           // Total size of the responses kept at most; zero for no bound
+}
+
+// idempotencyScope identifies a key: keys are the client's own, so that each user has their own
+type idempotencyScope struct {
+	userID uuid.UUID // uuid.Nil when authentication is disabled
+	key    string
+}
+
+// idempotentRequest is the first request made with a key: its fingerprint and, once handled, its
+// response. done is closed when the response is recorded, or dropped.
+type idempotentRequest struct {
+	fingerprint [sha256.Size]byte
+	done        chan struct{}
+	response    *recordedResponse // nil until handled, and if dropped
+	expires     time.Time
+}
+
+// recordedResponse is a response kept for replay
+type recordedResponse struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// size approximates the memory a recorded response takes, by the length of its header and body
+func (rec *recordedResponse) size() int {
+	size := len(rec.body)
+	for name, values := range rec.header {
+		for _, value := range values {
+			size += len(name) + len(value)
+		}
+	}
+	return size
+}
+
+// idempotencyStore keeps the first request per key until the window after its response expires,
+// or until forgotten to keep within the policy's bounds
+type idempotencyStore struct {
+	mu       sync.Mutex
+	policy   IdempotencyPolicy
+	requests map[idempotencyScope]*idempotentRequest
+	expiry   []idempotencyScope // keys of the recorded requests, in the order they expire
+	bytes    int                // total size of the recorded responses
+}
+
+// Idempotency honours the Idempotency-Key header of POST requests: the first response with a key is
+// recorded, for the window, and replayed to retries of the request, with the Idempotent-Replayed
+// header set, without handling them again. Keys are scoped per authenticated user, so it must come
+// after JWTAuth. Retries whose method, path, query or body differ from the first request are
+// rejected with 422; retries arriving while the first request is handled wait for its response.
+// Server errors are not recorded, so that retries get handled again. Bodies of requests with a key
+// are limited to 1 MiB, and beyond the policy's bounds the oldest responses are forgotten early.
+func Idempotency(policy IdempotencyPolicy) func(http.Handler) http.Handler {
+	store := &idempotencyStore{policy: policy, requests: make(map[idempotencyScope]*idempotentRequest)}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if policy.Window <= 0 || r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				problem.Write(w, r, http.StatusBadRequest, ErrIdempotencyKeyTooLong)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBody))
+			r.Body.Close()
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				problem.Write(w, r, http.StatusRequestEntityTooLarge, ErrBodyTooLarge)
+				return
+			}
+			if err != nil {
+				problem.Write(w, r, http.StatusBadRequest, ErrUnreadableBody)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			scope := idempotencyScope{key: key}
+			if userID, ok := GetUserIDFromContext(r.Context()); ok {
+				scope.userID = userID
+			}
+			fingerprint := requestFingerprint(r, body)
+
+			for {
+				first, started := store.begin(scope, fingerprint)
+				if started {
+					recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+					defer func() {
+						if p := recover(); p != nil {
+							store.drop(scope, first)
+							panic(p)
+						}
+					}()
+					next.ServeHTTP(recorder, r)
+					store.finish(scope, first, recorder)
+					return
+				}
+				if first.fingerprint != fingerprint {
+					problem.Write(w, r, http.StatusUnprocessableEntity, ErrIdempotencyKeyReused)
+					return
+				}
+
+				select {
+				case <-first.done:
+				case <-r.Context().Done():
+					return
+				}
+				if first.response != nil {
+					first.response.replay(w)
+					return
+				}
+				// The first request was not recorded: handle this one in its place
+			}
+		})
+	}
+}
+
+// begin returns the first request made with a key, unexpired, or starts it with the given
+// fingerprint, telling which
+func (s *idempotencyStore) begin(scope idempotencyScope, fingerprint [sha256.Size]byte) (*idempotentRequest, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expire(time.Now())
+	if first, exists := s.requests[scope]; exists {
+		return first, false
+	}
+	first := &idempotentRequest{fingerprint: fingerprint, done: make(chan struct{})}
+	s.requests[scope] = first
+	return first, true
+}
+
+// finish records the response to the first request made with a key, unless a server error, and
+// releases the retries waiting for it. The oldest responses are forgotten as needed to keep within
+// the policy's bounds.
+func (s *idempotencyStore) finish(scope idempotencyScope, first *idempotentRequest, recorder *responseRecorder) {
+	if recorder.status >= http.StatusInternalServerError {
+		s.drop(scope, first)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	first.response = &recordedResponse{status: recorder.status, header: recorder.Header().Clone(), body: recorder.body.Bytes()}
+	first.expires = time.Now().Add(s.policy.Window)
+	s.expiry = append(s.expiry, scope)
+	s.bytes += first.response.size()
+	for len(s.expiry) > 0 && s.exceeded() {
+		s.forget(1)
+	}
+	close(first.done)
+}
+
+// exceeded tells whether the recorded responses exceed the policy's bounds; the caller holds the lock
+func (s *idempotencyStore) exceeded() bool {
+	return (s.policy.MaxKeys > 0 && len(s.expiry) > s.policy.MaxKeys) ||
+		(s.policy.MaxBytes > 0 && s.bytes > s.policy.MaxBytes)
+}
+
+// drop forgets the first request made with a key, unrecorded, so that a retry waiting for it is
+// handled in its place
+func (s *idempotencyStore) drop(scope idempotencyScope, first *idempotentRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.requests, scope)
+	close(first.done)
+}
+
+// expire drops the recorded requests whose window is over; the caller holds the lock. Requests
+// are recorded with the same window, so that they expire in the order they were recorded in.
+func (s *idempotencyStore) expire(now time.Time) {
+	expired := 0
+	for ; expired < len(s.expiry); expired++ {
+		if s.requests[s.expiry[expired]].expires.After(now) {
+			break
+		}
+	}
+	s.forget(expired)
+}
+
+// forget drops the n oldest recorded requests; the caller holds the lock
+func (s *idempotencyStore) forget(n int) {
+	for _, scope := range s.expiry[:n] {
+		s.bytes -= s.requests[scope].response.size()
+		delete(s.requests, scope)
+	}
+	if n == len(s.expiry) {
+		s.expiry = nil // release the drained array
+	} else {
+		s.expiry = s.expiry[n:]
+	}
+}
+
+// requestFingerprint hashes what a retry must repeat: the method, the path with its query and the
+// body
+func requestFingerprint(r *http.Request, body []byte) [sha256.Size]byte {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	var fingerprint [sha256.Size]byte
+	h.Sum(fingerprint[:0])
+	return fingerprint
+}
+
+// replay writes a recorded response, keeping the ID of the request replayed to
+func (rec *recordedResponse) replay(w http.ResponseWriter) {
+	for name, values := range rec.header {
+		if name != http.CanonicalHeaderKey(RequestIDHeader) {
+			w.Header()[name] = append([]string(nil), values...)
+		}
+	}
+	w.Header().Set(IdempotentReplayedHeader, "true")
+	w.WriteHeader(rec.status)
+	w.Write(rec.body)
+}
+
+// responseRecorder wraps http.ResponseWriter to keep a copy of the response
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.status, rec.wroteHeader = code, true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the wrapped ResponseWriter, e.g. for http.ResponseController
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -2,9 +2,14 @@
 package middleware
 
 import (
+	"bufio"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/problem"
 )
 
 // responseWriter wraps http.ResponseWriter to capture status code
@@ -18,7 +23,22 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
-// Logger logs each HTTP request
+// Unwrap exposes the wrapped ResponseWriter, e.g. for http.ResponseController to flush streams
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Hijack lets WebSocket upgrades take over the connection
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err == nil {
+		rw.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, brw, err
+}
+
+// Logger logs each HTTP request, with its ID when tagged by RequestID. Access tokens passed in the
+// query string are redacted.
 func Logger() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -32,13 +52,17 @@ func Logger() func(http.Handler) http.Handler {
 
 			next.ServeHTTP(wrapped, r)
 
-			log.Printf(
+			line := fmt.Sprintf(
 				"%s %s %d %s",
 				r.Method,
-				r.RequestURI,
+				problem.RequestURI(r.URL),
 				wrapped.statusCode,
 				time.Since(start),
 			)
+			if requestID, ok := GetRequestIDFromContext(r.Context()); ok {
+				line += " request=" + requestID
+			}
+			log.Print(line)
 		})
 	}
 }
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the ID of a request, from clients and back to them
+const RequestIDHeader = "X-Request-ID"
+
+const RequestIDKey contextKey = "request_id"
+
+// RequestID tags each request with an ID, taken from the X-Request-ID header when it is a
+// reasonable one and generated otherwise, and echoes it in the response. Changes made by the
+// request are audited with its ID.
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			ctx := context.WithValue(r.Context(), RequestIDKey, requestID)
+			actor := domain.ActorFromContext(ctx)
+			actor.RequestID = requestID
+			ctx = domain.WithActor(ctx, actor)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// validRequestID tells whether a client's request ID is printable ASCII of a bounded length
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > domain.MaxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if requestID[i] < 0x21 || requestID[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// GetRequestIDFromContext extracts request ID from context
+func GetRequestIDFromContext(ctx context.Context) (string, bool) {
+	requestID, ok := ctx.Value(RequestIDKey).(string)
+	return requestID, ok
+}
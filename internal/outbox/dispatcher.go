@@ -0,0 +1,103 @@
+// Package outbox relays the events recorded in the repository's outbox to sinks: the live event
+// bus, webhooks, a log or a file. Events are acknowledged once every sink has handled them, so
+// that a sink failing, or the process stopping, before then has them handed again: delivery is at
+// least once, and sinks discard the events they handled already by ID.
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+)
+
+// Store keeps the outbox of recorded events
+type Store interface {
+	PendingEvents(ctx context.Context, limit int) ([]domain.Event, error)
+	AckEvents(ctx context.Context, lastEventID uint64) error
+}
+
+// Sink handles batches of events, in order. A batch may be handed again after a failure, so
+// sinks are expected to discard the events they handled already, by ID.
+type Sink interface {
+	Handle(ctx context.Context, events []domain.Event) error
+}
+
+// Policy tells how the outbox is drained
+type Policy struct {
+	BatchSize    int           // Events read at once
+	PollInterval time.Duration // Wait before reading the outbox again when idle or after a failure
+}
+
+// Dispatcher drains an outbox to sinks. It does nothing until run.
+type Dispatcher struct {
+	store  Store
+	sinks  []Sink
+	policy Policy
+	wake   chan struct{} // signalled when events are recorded
+}
+
+// NewDispatcher creates a dispatcher of the events in a store to sinks
+func NewDispatcher(store Store, policy Policy, sinks ...Sink) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		sinks:  sinks,
+		policy: policy,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Notify tells the dispatcher that events were recorded, so that they are relayed right away
+// rather than on the next poll. It never blocks.
+func (d *Dispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run relays the recorded events to the sinks until the context is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		if d.drain(ctx) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+		case <-time.After(d.policy.PollInterval):
+		}
+	}
+}
+
+// drain relays a batch of pending events to every sink and acknowledges it, returning whether
+// more events may be pending
+func (d *Dispatcher) drain(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	events, err := d.store.PendingEvents(ctx, d.policy.BatchSize)
+	if err != nil {
+		log.Printf("outbox: reading pending events: %v", err)
+		return false
+	}
+	if len(events) == 0 {
+		return false
+	}
+
+	for _, sink := range d.sinks {
+		if err := sink.Handle(ctx, events); err != nil {
+			if ctx.Err() == nil {
+				log.Printf("outbox: handling events %d to %d: %v", events[0].ID, events[len(events)-1].ID, err)
+			}
+			return false
+		}
+	}
+	if err := d.store.AckEvents(ctx, events[len(events)-1].ID); err != nil {
+		log.Printf("outbox: acknowledging events up to %d: %v", events[len(events)-1].ID, err)
+		return false
+	}
+	return len(events) == d.policy.BatchSize
+}
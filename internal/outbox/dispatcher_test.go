@@ -0,0 +1,163 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/events"
+	"github.com/gioannid/platform-go-challenge/internal/repository/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a sink remembering the IDs of the events handed to it, failing as often as told first
+type recorder struct {
+	mu       sync.Mutex
+	failures int
+	handed   []uint64
+}
+
+func (r *recorder) Handle(ctx context.Context, events []domain.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("sink unavailable")
+	}
+	for _, event := range events {
+		r.handed = append(r.handed, event.ID)
+	}
+	return nil
+}
+
+func (r *recorder) ids() []uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]uint64(nil), r.handed...)
+}
+
+// run runs a dispatcher until the test ends
+func run(t *testing.T, dispatcher *Dispatcher) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatcher.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+// drained waits until every recorded event is acknowledged
+func drained(t *testing.T, repo *memory.MemoryRepository) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		pending, err := repo.PendingEvents(context.Background(), 0)
+		require.NoError(t, err)
+		return len(pending) == 0
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+func createAssets(t *testing.T, repo *memory.MemoryRepository, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, "insight", domain.InsightData{Text: "text"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(context.Background(), asset))
+	}
+}
+
+func TestDispatcher_Relay(t *testing.T) {
+	repo := memory.NewRepository()
+	bus := events.NewBus(16)
+	sub := bus.Subscribe(func(domain.Event) bool { return true }, 0)
+	defer sub.Close()
+	sink := &recorder{}
+	// A long poll interval: events are relayed as notified, in batches
+	dispatcher := NewDispatcher(repo, Policy{BatchSize: 2, PollInterval: time.Hour}, NewBusSink(bus), sink)
+
+	createAssets(t, repo, 3)
+	run(t, dispatcher)
+	drained(t, repo)
+	createAssets(t, repo, 2)
+	dispatcher.Notify()
+	drained(t, repo)
+
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, sink.ids())
+	for id := uint64(1); id <= 5; id++ {
+		event := <-sub.Events()
+		assert.Equal(t, id, event.ID, "the bus keeps the recorded IDs")
+		assert.Equal(t, domain.EventAssetCreated, event.Type)
+	}
+}
+
+func TestDispatcher_AtLeastOnce(t *testing.T) {
+	repo := memory.NewRepository()
+	bus := events.NewBus(16)
+	sub := bus.Subscribe(func(domain.Event) bool { return true }, 0)
+	defer sub.Close()
+	flaky := &recorder{failures: 2}
+	dispatcher := NewDispatcher(repo, Policy{BatchSize: 10, PollInterval: 5 * time.Millisecond}, NewBusSink(bus), flaky)
+
+	// Events stay pending while a sink fails, and sinks that handled them already get them again
+	createAssets(t, repo, 2)
+	run(t, dispatcher)
+	drained(t, repo)
+	assert.Equal(t, []uint64{1, 2}, flaky.ids())
+	assert.Equal(t, uint64(1), (<-sub.Events()).ID)
+	assert.Equal(t, uint64(2), (<-sub.Events()).ID)
+	assert.Empty(t, sub.Events(), "the bus discards the events handed again")
+}
+
+func TestSinks_Dedup(t *testing.T) {
+	ctx := context.Background()
+	userID, assetID := uuid.New(), uuid.New()
+	batch := []domain.Event{
+		{ID: 1, Type: domain.EventAssetCreated, AssetID: &assetID},
+		{ID: 2, Type: domain.EventFavouriteAdded, UserID: &userID, AssetID: &assetID},
+	}
+	next := []domain.Event{batch[1], {ID: 3, Type: domain.EventAssetDeleted, AssetID: &assetID}}
+
+	var logged bytes.Buffer
+	logSink := NewLogSink(log.New(&logged, "", 0))
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	fileSink := NewFileSink(path)
+	for _, sink := range []Sink{logSink, fileSink} {
+		require.NoError(t, sink.Handle(ctx, batch))
+		require.NoError(t, sink.Handle(ctx, batch))
+		require.NoError(t, sink.Handle(ctx, next))
+	}
+
+	assert.Equal(t, "event 1 asset_created asset="+assetID.String()+"\n"+
+		"event 2 favourite_added user="+userID.String()+" asset="+assetID.String()+"\n"+
+		"event 3 asset_deleted asset="+assetID.String()+"\n", logged.String())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var ids []uint64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event domain.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		ids = append(ids, event.ID)
+	}
+	require.NoError(t, scanner.Err())
+	assert.Equal(t, []uint64{1, 2, 3}, ids, "one line per event, appended once")
+}
@@ -0,0 +1,132 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/events"
+)
+
+// BusSink publishes events to the live event bus, which discards those it published already
+type BusSink struct {
+	bus *events.Bus
+}
+
+// NewBusSink creates a sink publishing to a bus
+func NewBusSink(bus *events.Bus) *BusSink {
+	return &BusSink{bus: bus}
+}
+
+// Handle publishes the events in order
+func (s *BusSink) Handle(ctx context.Context, events []domain.Event) error {
+	for _, event := range events {
+		s.bus.Publish(event)
+	}
+	return nil
+}
+
+// dedup remembers the ID of the last event a sink handled
+type dedup struct {
+	mu     sync.Mutex
+	lastID uint64
+}
+
+// fresh returns the events after the last one handled
+func (d *dedup) fresh(events []domain.Event) []domain.Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, event := range events {
+		if event.ID > d.lastID {
+			return events[i:]
+		}
+	}
+	return nil
+}
+
+func (d *dedup) handled(events []domain.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastID = max(d.lastID, events[len(events)-1].ID)
+}
+
+// LogSink writes a line per event to a logger
+type LogSink struct {
+	logger *log.Logger
+	dedup  dedup
+}
+
+// NewLogSink creates a sink writing to a logger
+func NewLogSink(logger *log.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+// Handle logs the events not logged yet
+func (s *LogSink) Handle(ctx context.Context, events []domain.Event) error {
+	events = s.dedup.fresh(events)
+	if len(events) == 0 {
+		return nil
+	}
+	for _, event := range events {
+		line := fmt.Sprintf("event %d %s", event.ID, event.Type)
+		if event.UserID != nil {
+			line += " user=" + event.UserID.String()
+		}
+		if event.AssetID != nil {
+			line += " asset=" + event.AssetID.String()
+		}
+		if event.FavouriteID != nil {
+			line += " favourite=" + event.FavouriteID.String()
+		}
+		s.logger.Print(line)
+	}
+	s.dedup.handled(events)
+	return nil
+}
+
+// FileSink appends events to a file as JSON lines, syncing the file before acknowledging them.
+// Events are discarded by ID within a run only, so that consumers of the file should do the same
+// across runs.
+type FileSink struct {
+	path  string
+	dedup dedup
+}
+
+// NewFileSink creates a sink appending to a file, created as needed
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Handle appends the events not appended yet
+func (s *FileSink) Handle(ctx context.Context, events []domain.Event) error {
+	events = s.dedup.fresh(events)
+	if len(events) == 0 {
+		return nil
+	}
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(f)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	s.dedup.handled(events)
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package problem writes error responses, shared by the handlers and the middleware so that every
+// error reads alike. Clients asking for application/problem+json get RFC 9457 (formerly RFC 7807)
+// problem details; others get the legacy envelope, {"success": false, "error": ...}, which now
+// carries the error's code and invalid fields as well.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+)
+
+// ContentType is the media type of problem details
+const ContentType = "application/problem+json"
+
+// TypePrefix is prepended to an error's code to make its problem type URI, relative to the API
+const TypePrefix = "/problems/"
+
+// InvalidJSONCode is the code of request bodies that are not the JSON expected
+const InvalidJSONCode = "invalid_json"
+
+// Details is a problem details document. Code repeats the last segment of Type, for clients that
+// would rather not parse URIs.
+type Details struct {
+	Type      string                  `json:"type" example:"/problems/invalid_webhook"`
+	Title     string                  `json:"title" example:"invalid webhook"`
+	Status    int                     `json:"status" example:"400"`
+	Detail    string                  `json:"detail,omitempty" example:"invalid webhook: secret must be at least 16 characters"`
+	Instance  string                  `json:"instance,omitempty" example:"/api/v1/webhooks"`
+	Code      string                  `json:"code" example:"invalid_webhook"`
+	Errors    []domain.FieldViolation `json:"errors,omitempty"`
+	RequestID string                  `json:"request_id,omitempty" example:"6f1c2d1e-8a9b-4c3d-9e0f-1a2b3c4d5e6f"`
+}
+
+// envelope is the legacy error response
+type envelope struct {
+	Success bool                    `json:"success"`
+	Error   string                  `json:"error"`
+	Code    string                  `json:"code"`
+	Details []domain.FieldViolation `json:"details,omitempty"`
+}
+
+// New describes an error answered with a status. Domain errors lend their code and title; other
+// errors are named after the status, but for request bodies that are not valid JSON. Server errors
+// are only detailed as such, leaving their cause to Write's log.
+func New(r *http.Request, status int, err error) *Details {
+	details := &Details{
+		Title:    strings.ToLower(http.StatusText(status)),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: RequestURI(r.URL),
+		Code:     statusCode(status),
+		Errors:   domain.FieldViolations(err),
+	}
+
+	var (
+		domainErr *domain.Error
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &domainErr):
+		details.Code, details.Title = domainErr.Code, domainErr.Message
+	case errors.As(err, &syntaxErr):
+		details.Code, details.Title = InvalidJSONCode, "invalid JSON"
+	case errors.As(err, &typeErr):
+		details.Code, details.Title = InvalidJSONCode, "invalid JSON"
+		if typeErr.Field != "" {
+			details.Errors = []domain.FieldViolation{{Field: typeErr.Field, Message: "must be " + typeErr.Type.String()}}
+		}
+	}
+	details.Type = TypePrefix + details.Code
+	if status >= http.StatusInternalServerError {
+		details.Detail, details.Errors = serverErrorDetail(status), nil
+	}
+	return details
+}
+
+// Write answers a request with an error, as problem details if the client accepts them and in the
+// legacy envelope otherwise. The causes of server errors are logged with the request's ID instead.
+func Write(w http.ResponseWriter, r *http.Request, status int, err error) {
+	details := New(r, status, err)
+	// The request ID middleware runs first, and sets its header on the response
+	requestID := w.Header().Get("X-Request-ID")
+	if status >= http.StatusInternalServerError {
+		log.Printf("%s %s %d: %v request=%s", r.Method, RequestURI(r.URL), status, err, requestID)
+	}
+	if !Accepted(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(envelope{Error: details.Detail, Code: details.Code, Details: details.Errors})
+		return
+	}
+
+	details.RequestID = requestID
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(details)
+}
+
+// Accepted tells whether a request's Accept header lists problem details, with a non-zero quality
+func Accepted(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaRange := range strings.Split(accept, ",") {
+			mediaType, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil || mediaType != ContentType {
+				continue
+			}
+			if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+				continue
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// RequestURI returns the URI of a request as reported in problems and logs, with the access_token
+// query parameter redacted
+func RequestURI(u *url.URL) string {
+	query := u.Query()
+	if !query.Has("access_token") {
+		return u.RequestURI()
+	}
+	query.Set("access_token", "REDACTED")
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+	return redacted.RequestURI()
+}
+
+// serverErrorDetail is the generic detail of server errors, e.g. "internal server error"
+func serverErrorDetail(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return strings.ToLower(text)
+	}
+	return "internal server error"
+}
+
+// statusCode names a status as an error code, e.g. "unprocessable_entity"
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
+}
@@ -0,0 +1,102 @@
+package problem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccepted(t *testing.T) {
+	for accept, want := range map[string]bool{
+		"":                         false,
+		"application/json":         false,
+		"application/problem+json": true,
+		"application/json, application/problem+json;q=0.5": true,
+		"application/problem+json;q=0":                     false,
+		"*/*":                                              false,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept", accept)
+		assert.Equal(t, want, Accepted(r), accept)
+	}
+}
+
+func TestWrite_Problem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks?dry=1", nil)
+	r.Header.Set("Accept", ContentType)
+	w := httptest.NewRecorder()
+	w.Header().Set("X-Request-ID", "req-1")
+	Write(w, r, http.StatusBadRequest, domain.InvalidField(domain.ErrInvalidWebhook, "secret", "secret must be at least 16 characters"))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	var details Details
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&details))
+	assert.Equal(t, Details{
+		Type:      "/problems/invalid_webhook",
+		Title:     "invalid webhook",
+		Status:    http.StatusBadRequest,
+		Detail:    "invalid webhook: secret must be at least 16 characters",
+		Instance:  "/api/v1/webhooks?dry=1",
+		Code:      "invalid_webhook",
+		Errors:    []domain.FieldViolation{{Field: "secret", Message: "secret must be at least 16 characters"}},
+		RequestID: "req-1",
+	}, details)
+}
+
+func TestWrite_Legacy(t *testing.T) {
+	w := httptest.NewRecorder()
+	Write(w, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusNotFound, fmt.Errorf("%w: asset", domain.ErrNotFound))
+
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"success":false,"error":"resource not found: asset","code":"not_found"}`, w.Body.String())
+}
+
+func TestNew_Codes(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	details := New(r, http.StatusInternalServerError, errors.New("disk on fire"))
+	assert.Equal(t, "internal_server_error", details.Code, "other errors are named after the status")
+	assert.Equal(t, "/problems/internal_server_error", details.Type)
+	assert.Equal(t, "internal server error", details.Detail, "server errors keep their cause to the log")
+
+	var payload struct {
+		Limit int `json:"limit"`
+	}
+	details = New(r, http.StatusBadRequest, json.Unmarshal([]byte(`{"limit":"ten"}`), &payload))
+	assert.Equal(t, InvalidJSONCode, details.Code)
+	assert.Equal(t, []domain.FieldViolation{{Field: "limit", Message: "must be int"}}, details.Errors)
+
+	details = New(r, http.StatusBadRequest, json.Unmarshal([]byte(`{`), &payload))
+	assert.Equal(t, InvalidJSONCode, details.Code)
+	assert.Empty(t, details.Errors)
+}
+
+func TestWrite_ServerError(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	w := httptest.NewRecorder()
+	w.Header().Set("X-Request-ID", "req-1")
+	Write(w, httptest.NewRequest(http.MethodGet, "/api/v1/assets", nil), http.StatusInternalServerError, errors.New("disk on fire at /var/lib/db"))
+
+	assert.JSONEq(t, `{"success":false,"error":"internal server error","code":"internal_server_error"}`, w.Body.String())
+	assert.Contains(t, logged.String(), "GET /api/v1/assets 500: disk on fire at /var/lib/db request=req-1")
+}
+
+func TestRequestURI(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/users/1/live?access_token=header.claims.signature&since=2", nil)
+	assert.Equal(t, "/api/v1/users/1/live?access_token=REDACTED&since=2", RequestURI(r.URL))
+	assert.Equal(t, "/api/v1/assets?dry=1", RequestURI(httptest.NewRequest(http.MethodGet, "/api/v1/assets?dry=1", nil).URL))
+}
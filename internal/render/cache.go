@@ -0,0 +1,81 @@
+package render
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cache is a thread-safe, size-bounded LRU cache of rendered images. Keys include the asset's
+// UpdatedAt timestamp, so an updated asset is rendered afresh while stale entries age out.
+type Cache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front is most recently used
+}
+
+type cacheEntry struct {
+	key  string
+	data []byte
+}
+
+// NewCache creates a cache holding up to capacity images; a non-positive capacity disables caching
+func NewCache(capacity int) *Cache {
+	return &Cache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Key builds the cache key for a rendering of an asset version
+func Key(assetID uuid.UUID, updatedAt time.Time, opts Options) string {
+	return fmt.Sprintf("%s|%d|%s|%dx%d", assetID, updatedAt.UnixNano(), opts.Format, opts.Width, opts.Height)
+}
+
+// Get returns the cached image for key, if any
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*cacheEntry).data, true
+}
+
+// Put stores an image, evicting the least recently used one when full
+func (c *Cache) Put(key string, data []byte) {
+	if c.capacity <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*cacheEntry).data = data
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, data: data})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of cached images
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
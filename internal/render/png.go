@@ -0,0 +1,182 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// pngCanvas rasterizes drawing primitives onto an RGBA image. Text uses the fixed-size
+// 7x13 bitmap face, so the requested text size is ignored.
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func newPNGCanvas(width, height int) *pngCanvas {
+	return &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+}
+
+// Bytes encodes the image as PNG
+func (c *pngCanvas) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *pngCanvas) Rect(x, y, w, h float64, fill color.RGBA) {
+	r := image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h)))
+	draw.Draw(c.img, r, image.NewUniform(fill), image.Point{}, draw.Src)
+}
+
+// Line stamps filled discs along the segment, which gives round caps and joins for free
+func (c *pngCanvas) Line(x1, y1, x2, y2 float64, stroke color.RGBA, width float64) {
+	if width <= 1 {
+		c.thinLine(x1, y1, x2, y2, stroke)
+		return
+	}
+	length := math.Hypot(x2-x1, y2-y1)
+	steps := int(math.Ceil(length*2)) + 1
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		c.Circle(x1+(x2-x1)*t, y1+(y2-y1)*t, width/2, stroke)
+	}
+}
+
+// thinLine draws a one pixel wide line with Bresenham's algorithm
+func (c *pngCanvas) thinLine(x1, y1, x2, y2 float64, stroke color.RGBA) {
+	x0, y0 := int(math.Round(x1)), int(math.Round(y1))
+	xe, ye := int(math.Round(x2)), int(math.Round(y2))
+	dx, dy := abs(xe-x0), -abs(ye-y0)
+	sx, sy := sign(xe-x0), sign(ye-y0)
+	e := dx + dy
+	for {
+		c.img.SetRGBA(x0, y0, stroke)
+		if x0 == xe && y0 == ye {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func (c *pngCanvas) Polyline(points []point, stroke color.RGBA, width float64) {
+	for i := 1; i < len(points); i++ {
+		c.Line(points[i-1].X, points[i-1].Y, points[i].X, points[i].Y, stroke, width)
+	}
+}
+
+func (c *pngCanvas) Circle(cx, cy, r float64, fill color.RGBA) {
+	c.fill(cx, cy, r, func(dx, dy float64) bool { return dx*dx+dy*dy <= r*r }, fill)
+}
+
+func (c *pngCanvas) Wedge(cx, cy, r, startAngle, endAngle float64, fill color.RGBA) {
+	full := endAngle-startAngle >= 2*math.Pi-1e-9
+	c.fill(cx, cy, r, func(dx, dy float64) bool {
+		if dx*dx+dy*dy > r*r {
+			return false
+		}
+		if full {
+			return true
+		}
+		// Normalize the pixel angle into [startAngle, startAngle+2π)
+		a := math.Atan2(dy, dx)
+		for a < startAngle {
+			a += 2 * math.Pi
+		}
+		for a >= startAngle+2*math.Pi {
+			a -= 2 * math.Pi
+		}
+		return a <= endAngle
+	}, fill)
+}
+
+// fill paints every pixel of the bounding square around (cx, cy) for which inside holds
+func (c *pngCanvas) fill(cx, cy, r float64, inside func(dx, dy float64) bool, col color.RGBA) {
+	bounds := c.img.Bounds()
+	for y := int(math.Floor(cy - r)); y <= int(math.Ceil(cy+r)); y++ {
+		for x := int(math.Floor(cx - r)); x <= int(math.Ceil(cx+r)); x++ {
+			if !(image.Point{X: x, Y: y}).In(bounds) {
+				continue
+			}
+			if inside(float64(x)+0.5-cx, float64(y)+0.5-cy) {
+				c.img.SetRGBA(x, y, col)
+			}
+		}
+	}
+}
+
+func (c *pngCanvas) Text(x, y float64, s string, size float64, a anchor, fill color.RGBA) {
+	w := c.TextWidth(s, size)
+	switch a {
+	case anchorMiddle:
+		x -= w / 2
+	case anchorEnd:
+		x -= w
+	}
+	d := &font.Drawer{
+		Dst:  c.img,
+		Src:  image.NewUniform(fill),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(int(math.Round(x)), int(math.Round(y))),
+	}
+	d.DrawString(s)
+}
+
+// VerticalText draws the text on a scratch image and copies it rotated 90° counter-clockwise
+func (c *pngCanvas) VerticalText(x, y float64, s string, size float64, fill color.RGBA) {
+	w := int(math.Ceil(c.TextWidth(s, size)))
+	h := basicfont.Face7x13.Height
+	scratch := image.NewRGBA(image.Rect(0, 0, w, h))
+	d := &font.Drawer{
+		Dst:  scratch,
+		Src:  image.NewUniform(fill),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(0, basicfont.Face7x13.Ascent),
+	}
+	d.DrawString(s)
+
+	left, top := int(math.Round(x))-h/2, int(math.Round(y))-w/2
+	for sy := 0; sy < h; sy++ {
+		for sx := 0; sx < w; sx++ {
+			if px := scratch.RGBAAt(sx, sy); px.A > 0 {
+				c.img.SetRGBA(left+sy, top+w-1-sx, px)
+			}
+		}
+	}
+}
+
+func (c *pngCanvas) TextWidth(s string, size float64) float64 {
+	return float64(font.MeasureString(basicfont.Face7x13, s).Ceil())
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	default:
+		return 0
+	}
+}
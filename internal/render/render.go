@@ -0,0 +1,486 @@
+// Package render draws chart assets as SVG or PNG images. Drawing is done in pure Go: the layout
+// (title, axes, ticks, legend, series) is computed once and replayed on a vector (SVG) or raster (PNG) canvas.
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+)
+
+// Format is an output image format
+type Format string
+
+const (
+	FormatSVG Format = "svg"
+	FormatPNG Format = "png"
+)
+
+// Size limits for rendered images, in pixels
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 450
+	MinSize       = 200
+	MaxSize       = 2000
+)
+
+// ParseFormat parses a format name, defaulting to SVG when empty
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case "", FormatSVG:
+		return FormatSVG, nil
+	case FormatPNG:
+		return FormatPNG, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported format %q", domain.ErrInvalidRenderOptions, s)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatPNG {
+		return "image/png"
+	}
+	return "image/svg+xml"
+}
+
+// Options controls the rendered image
+type Options struct {
+	Format Format
+	Width  int
+	Height int
+}
+
+// NewOptions validates the requested format and size; zero sizes fall back to the defaults
+func NewOptions(format string, width, height int) (Options, error) {
+	f, err := ParseFormat(format)
+	if err != nil {
+		return Options{}, err
+	}
+	if width == 0 {
+		width = DefaultWidth
+	}
+	if height == 0 {
+		height = DefaultHeight
+	}
+	if width < MinSize || width > MaxSize || height < MinSize || height > MaxSize {
+		return Options{}, fmt.Errorf("%w: width and height must be between %d and %d", domain.ErrInvalidRenderOptions, MinSize, MaxSize)
+	}
+	return Options{Format: f, Width: width, Height: height}, nil
+}
+
+// Chart renders chart data to an image in the requested format
+func Chart(chart *domain.ChartData, opts Options) ([]byte, error) {
+	switch opts.Format {
+	case FormatSVG:
+		c := newSVGCanvas(opts.Width, opts.Height)
+		paint(c, chart, float64(opts.Width), float64(opts.Height))
+		return c.Bytes(), nil
+	case FormatPNG:
+		c := newPNGCanvas(opts.Width, opts.Height)
+		paint(c, chart, float64(opts.Width), float64(opts.Height))
+		return c.Bytes()
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", domain.ErrInvalidRenderOptions, opts.Format)
+	}
+}
+
+// anchor is the horizontal alignment of text relative to its x coordinate
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+type point struct{ X, Y float64 }
+
+// canvas is the set of drawing primitives both output formats implement
+type canvas interface {
+	Rect(x, y, w, h float64, fill color.RGBA)
+	Line(x1, y1, x2, y2 float64, stroke color.RGBA, width float64)
+	Polyline(points []point, stroke color.RGBA, width float64)
+	Circle(cx, cy, r float64, fill color.RGBA)
+	Wedge(cx, cy, r, startAngle, endAngle float64, fill color.RGBA)
+	Text(x, y float64, s string, size float64, a anchor, fill color.RGBA)
+	VerticalText(x, y float64, s string, size float64, fill color.RGBA) // rotated 90° counter-clockwise, centred on (x, y)
+	TextWidth(s string, size float64) float64
+}
+
+var (
+	colorBackground = color.RGBA{255, 255, 255, 255}
+	colorText       = color.RGBA{33, 37, 41, 255}
+	colorMuted      = color.RGBA{108, 117, 125, 255}
+	colorAxis       = color.RGBA{73, 80, 87, 255}
+	colorGrid       = color.RGBA{222, 226, 230, 255}
+	palette         = []color.RGBA{
+		{31, 119, 180, 255}, {255, 127, 14, 255}, {44, 160, 44, 255}, {214, 39, 40, 255},
+		{148, 103, 189, 255}, {140, 86, 75, 255}, {227, 119, 194, 255}, {23, 190, 207, 255},
+	}
+)
+
+const (
+	titleSize    = 16
+	labelSize    = 12
+	tickSize     = 11
+	legendSwatch = 10
+)
+
+// plotArea is the rectangle inside the axes
+type plotArea struct {
+	left, top, right, bottom float64
+}
+
+// paint lays out and paints the whole chart on c
+func paint(c canvas, chart *domain.ChartData, width, height float64) {
+	c.Rect(0, 0, width, height, colorBackground)
+	c.Text(width/2, 28, chart.Title, titleSize, anchorMiddle, colorText)
+
+	legend := legendEntries(chart)
+	right := width - 20
+	if len(legend) > 1 || chart.Kind == domain.ChartKindPie {
+		right = width - 20 - legendWidth(c, legend)
+	}
+
+	if chart.Kind == domain.ChartKindPie {
+		drawPie(c, chart, plotArea{left: 20, top: 50, right: right - 10, bottom: height - 20})
+		drawLegend(c, legend, right+10, 60)
+		return
+	}
+
+	plot := plotArea{left: 75, top: 50, right: right, bottom: height - 60}
+	if len(chart.Series) == 0 {
+		drawAxes(c, chart, plot)
+		c.Text((plot.left+plot.right)/2, (plot.top+plot.bottom)/2, "No data", labelSize, anchorMiddle, colorMuted)
+		return
+	}
+
+	yLo, yHi := valueRange(chart)
+	yMin, yMax, yStep := niceTicks(yLo, yHi, 5)
+	yPos := func(v float64) float64 {
+		return plot.bottom - (v-yMin)/(yMax-yMin)*(plot.bottom-plot.top)
+	}
+
+	for _, v := range ticks(yMin, yMax, yStep) {
+		y := yPos(v)
+		c.Line(plot.left, y, plot.right, y, colorGrid, 1)
+		c.Text(plot.left-8, y+4, formatValue(chart.NumberFormat, v, yStep), tickSize, anchorEnd, colorMuted)
+	}
+
+	if len(chart.Categories) > 0 {
+		drawCategorical(c, chart, plot, yPos, yMin)
+	} else {
+		drawNumeric(c, chart, plot, yPos, yMin)
+	}
+
+	drawAxes(c, chart, plot)
+	if len(legend) > 1 {
+		drawLegend(c, legend, right+10, plot.top)
+	}
+}
+
+// drawAxes paints the axis lines and their titles (with units)
+func drawAxes(c canvas, chart *domain.ChartData, plot plotArea) {
+	c.Line(plot.left, plot.bottom, plot.right, plot.bottom, colorAxis, 1)
+	c.Line(plot.left, plot.top, plot.left, plot.bottom, colorAxis, 1)
+
+	if title := axisTitle(chart.AxisXTitle, chart.AxisXUnit); title != "" {
+		c.Text((plot.left+plot.right)/2, plot.bottom+45, title, labelSize, anchorMiddle, colorText)
+	}
+	if title := axisTitle(chart.AxisYTitle, chart.AxisYUnit); title != "" {
+		c.VerticalText(18, (plot.top+plot.bottom)/2, title, labelSize, colorText)
+	}
+}
+
+// drawCategorical paints line and bar series against category bands
+func drawCategorical(c canvas, chart *domain.ChartData, plot plotArea, yPos func(float64) float64, yMin float64) {
+	n := len(chart.Categories)
+	band := (plot.right - plot.left) / float64(n)
+	center := func(i int) float64 { return plot.left + band*(float64(i)+0.5) }
+
+	// Skip labels when they would overlap
+	every := int(math.Ceil((maxTextWidth(c, chart.Categories, tickSize) + 6) / band))
+	if every < 1 {
+		every = 1
+	}
+	for i, category := range chart.Categories {
+		if i%every == 0 {
+			c.Text(center(i), plot.bottom+18, category, tickSize, anchorMiddle, colorMuted)
+		}
+	}
+
+	if chart.Kind == domain.ChartKindBar {
+		groupWidth := band * 0.8
+		barWidth := groupWidth / float64(len(chart.Series))
+		base := yPos(math.Max(yMin, 0))
+		for s, series := range chart.Series {
+			for i, v := range series.Values {
+				x := center(i) - groupWidth/2 + barWidth*float64(s)
+				top, bottom := yPos(v), base
+				if top > bottom {
+					top, bottom = bottom, top
+				}
+				c.Rect(x, top, barWidth*0.9, bottom-top, palette[s%len(palette)])
+			}
+		}
+		return
+	}
+
+	for s, series := range chart.Series {
+		points := make([]point, len(series.Values))
+		for i, v := range series.Values {
+			points[i] = point{X: center(i), Y: yPos(v)}
+		}
+		drawSeriesLine(c, points, palette[s%len(palette)])
+	}
+}
+
+// drawNumeric paints point series against a numeric x axis
+func drawNumeric(c canvas, chart *domain.ChartData, plot plotArea, yPos func(float64) float64, yMin float64) {
+	xLo, xHi := pointRange(chart)
+	xMin, xMax, xStep := niceTicks(xLo, xHi, 6)
+	xPos := func(v float64) float64 {
+		return plot.left + (v-xMin)/(xMax-xMin)*(plot.right-plot.left)
+	}
+	for _, v := range ticks(xMin, xMax, xStep) {
+		x := xPos(v)
+		c.Line(x, plot.bottom, x, plot.bottom+4, colorAxis, 1)
+		c.Text(x, plot.bottom+18, formatValue(nil, v, xStep), tickSize, anchorMiddle, colorMuted)
+	}
+
+	for s, series := range chart.Series {
+		col := palette[s%len(palette)]
+		points := make([]point, len(series.Points))
+		for i, p := range series.Points {
+			points[i] = point{X: xPos(p.X), Y: yPos(p.Y)}
+		}
+
+		switch chart.Kind {
+		case domain.ChartKindScatter:
+			for _, p := range points {
+				c.Circle(p.X, p.Y, 3.5, col)
+			}
+		case domain.ChartKindBar:
+			width := math.Max(2, (plot.right-plot.left)/float64(4*len(points)*len(chart.Series)+1))
+			base := yPos(math.Max(yMin, 0))
+			for _, p := range points {
+				top, bottom := p.Y, base
+				if top > bottom {
+					top, bottom = bottom, top
+				}
+				c.Rect(p.X-width/2+width*float64(s), top, width, bottom-top, col)
+			}
+		default:
+			drawSeriesLine(c, points, col)
+		}
+	}
+}
+
+func drawSeriesLine(c canvas, points []point, col color.RGBA) {
+	c.Polyline(points, col, 2)
+	for _, p := range points {
+		c.Circle(p.X, p.Y, 2.5, col)
+	}
+}
+
+// drawPie paints the single pie series as wedges starting at 12 o'clock
+func drawPie(c canvas, chart *domain.ChartData, area plotArea) {
+	if len(chart.Series) == 0 {
+		c.Text((area.left+area.right)/2, (area.top+area.bottom)/2, "No data", labelSize, anchorMiddle, colorMuted)
+		return
+	}
+
+	cx, cy := (area.left+area.right)/2, (area.top+area.bottom)/2
+	r := math.Min(area.right-area.left, area.bottom-area.top) / 2
+
+	total := 0.0
+	for _, v := range chart.Series[0].Values {
+		total += v
+	}
+	angle := -math.Pi / 2
+	for i, v := range chart.Series[0].Values {
+		sweep := v / total * 2 * math.Pi
+		if sweep > 0 {
+			c.Wedge(cx, cy, r, angle, angle+sweep, palette[i%len(palette)])
+		}
+		angle += sweep
+	}
+}
+
+type legendEntry struct {
+	label string
+	color color.RGBA
+}
+
+// legendEntries lists series names, or for pie charts the categories with their share
+func legendEntries(chart *domain.ChartData) []legendEntry {
+	if chart.Kind == domain.ChartKindPie {
+		if len(chart.Series) == 0 {
+			return nil
+		}
+		total := 0.0
+		for _, v := range chart.Series[0].Values {
+			total += v
+		}
+		entries := make([]legendEntry, len(chart.Categories))
+		for i, category := range chart.Categories {
+			share := chart.Series[0].Values[i] / total * 100
+			entries[i] = legendEntry{
+				label: fmt.Sprintf("%s (%s%%)", category, strconv.FormatFloat(share, 'f', 1, 64)),
+				color: palette[i%len(palette)],
+			}
+		}
+		return entries
+	}
+
+	entries := make([]legendEntry, len(chart.Series))
+	for i, series := range chart.Series {
+		entries[i] = legendEntry{label: series.Name, color: palette[i%len(palette)]}
+	}
+	return entries
+}
+
+func legendWidth(c canvas, entries []legendEntry) float64 {
+	widest := 0.0
+	for _, e := range entries {
+		widest = math.Max(widest, c.TextWidth(e.label, labelSize))
+	}
+	return math.Min(widest+legendSwatch+16, 220)
+}
+
+func drawLegend(c canvas, entries []legendEntry, x, y float64) {
+	for i, e := range entries {
+		top := y + float64(i)*20
+		c.Rect(x, top, legendSwatch, legendSwatch, e.color)
+		c.Text(x+legendSwatch+6, top+legendSwatch, e.label, labelSize, anchorStart, colorText)
+	}
+}
+
+// valueRange returns the extent of the y values; bars always include the zero baseline
+func valueRange(chart *domain.ChartData) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, series := range chart.Series {
+		for _, v := range series.Values {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+		for _, p := range series.Points {
+			lo, hi = math.Min(lo, p.Y), math.Max(hi, p.Y)
+		}
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 1
+	}
+	if chart.Kind == domain.ChartKindBar {
+		lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+	}
+	return lo, hi
+}
+
+// pointRange returns the extent of the x coordinates of point series
+func pointRange(chart *domain.ChartData) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, series := range chart.Series {
+		for _, p := range series.Points {
+			lo, hi = math.Min(lo, p.X), math.Max(hi, p.X)
+		}
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 1
+	}
+	return lo, hi
+}
+
+const (
+	// maxTicks bounds the ticks drawn on an axis, whatever its range and step
+	maxTicks = 50
+	// minRelativeSpan is the narrowest range an axis spans, relative to the magnitude of its
+	// values: well above float64 precision (about 2.2e-16), so that ticks are distinct numbers
+	minRelativeSpan = 1e-9
+)
+
+// niceTicks expands [lo, hi] to round tick boundaries with a 1, 2 or 5 based step. Non-finite
+// bounds fall back to [0, 1]; ranges too narrow to tell ticks apart, single values among them,
+// are widened around their middle.
+func niceTicks(lo, hi float64, count int) (float64, float64, float64) {
+	if math.IsNaN(lo) || math.IsNaN(hi) || math.IsInf(lo, 0) || math.IsInf(hi, 0) {
+		lo, hi = 0, 1
+	}
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	if span := math.Max(math.Abs(lo), math.Abs(hi)) * minRelativeSpan; hi-lo < span {
+		mid, pad := lo/2+hi/2, math.Max(1, span)
+		lo, hi = mid-pad, mid+pad
+	}
+	raw := hi/float64(count) - lo/float64(count) // hi - lo may overflow
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	var step float64
+	switch norm := raw / magnitude; {
+	case norm < 1.5:
+		step = magnitude
+	case norm < 3:
+		step = 2 * magnitude
+	case norm < 7:
+		step = 5 * magnitude
+	default:
+		step = 10 * magnitude
+	}
+	min, max := math.Floor(lo/step)*step, math.Ceil(hi/step)*step
+	if math.IsInf(min, 0) || math.IsInf(max, 0) {
+		min, max = lo, hi
+	}
+	return min, max, step
+}
+
+// ticks lists the tick values from min to max by step, at most maxTicks of them. Values are
+// computed from their index rather than accumulated, so that rounding neither drifts nor stalls.
+func ticks(min, max, step float64) []float64 {
+	var values []float64
+	for i := 0; i < maxTicks; i++ {
+		v := min + float64(i)*step
+		if v > max+step/2 {
+			break
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// formatValue formats a tick value with the chart number format, or with as many decimals as the step needs
+func formatValue(format *domain.NumberFormat, v, step float64) string {
+	if math.Abs(v) < step*1e-9 {
+		v = 0
+	}
+	if format != nil {
+		return format.Format(v)
+	}
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+func axisTitle(title, unit string) string {
+	switch {
+	case unit == "":
+		return title
+	case title == "":
+		return unit
+	default:
+		return title + " (" + unit + ")"
+	}
+}
+
+func maxTextWidth(c canvas, labels []string, size float64) float64 {
+	widest := 0.0
+	for _, l := range labels {
+		widest = math.Max(widest, c.TextWidth(l, size))
+	}
+	return widest
+}
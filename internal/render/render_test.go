@@ -0,0 +1,230 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testCharts() map[string]*domain.ChartData {
+	return map[string]*domain.ChartData{
+		"line": {
+			Title: "Q4 Sales", Kind: domain.ChartKindLine, AxisXTitle: "Month", AxisYTitle: "Revenue", AxisYUnit: "USD",
+			Categories: []string{"Oct", "Nov", "Dec"},
+			Series: []domain.ChartSeries{
+				{Name: "EU", Values: []float64{1200, 1350, 1800}},
+				{Name: "US", Values: []float64{2100, 2250, 2900}},
+			},
+			NumberFormat: &domain.NumberFormat{Prefix: "$", ThousandsSeparator: true},
+		},
+		"bar": {
+			Title: "Purchases", Kind: domain.ChartKindBar, AxisXTitle: "Age", AxisYTitle: "Count",
+			Categories: []string{"18-24", "25-34"},
+			Series:     []domain.ChartSeries{{Name: "2025", Values: []float64{-3, 12}}},
+		},
+		"pie": {
+			Title: "Share", Kind: domain.ChartKindPie,
+			Categories: []string{"Male", "Female"},
+			Series:     []domain.ChartSeries{{Name: "share", Values: []float64{45, 55}}},
+		},
+		"scatter": {
+			Title: "Hours vs purchases", Kind: domain.ChartKindScatter, AxisXTitle: "Hours", AxisYTitle: "Purchases",
+			Series: []domain.ChartSeries{{Name: "respondents", Points: []domain.ChartPoint{{X: 0.5, Y: 1}, {X: 3.2, Y: 7}}}},
+		},
+		"empty": {Title: "Placeholder", Kind: domain.ChartKindLine},
+	}
+}
+
+func TestChart_SVG(t *testing.T) {
+	opts, err := NewOptions("svg", 0, 0)
+	require.NoError(t, err)
+
+	for name, chart := range testCharts() {
+		t.Run(name, func(t *testing.T) {
+			out, err := Chart(chart, opts)
+			require.NoError(t, err)
+
+			// The document must be well-formed XML
+			decoder := xml.NewDecoder(bytes.NewReader(out))
+			for {
+				_, err := decoder.Token()
+				if err != nil {
+					assert.Equal(t, "EOF", err.Error())
+					break
+				}
+			}
+
+			svg := string(out)
+			assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="800" height="450"`))
+			assert.Contains(t, svg, ">"+chart.Title+"</text>")
+		})
+	}
+
+	t.Run("axis titles, units, legend and formatted ticks", func(t *testing.T) {
+		out, err := Chart(testCharts()["line"], opts)
+		require.NoError(t, err)
+		svg := string(out)
+		assert.Contains(t, svg, ">Month</text>")
+		assert.Contains(t, svg, ">Revenue (USD)</text>")
+		assert.Contains(t, svg, "rotate(-90")
+		assert.Contains(t, svg, ">EU</text>")
+		assert.Contains(t, svg, ">US</text>")
+		assert.Contains(t, svg, ">$3,000</text>")
+		assert.Equal(t, 2, strings.Count(svg, "<polyline"))
+	})
+
+	t.Run("pie legend shows shares", func(t *testing.T) {
+		out, err := Chart(testCharts()["pie"], opts)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "Female (55.0%)")
+		assert.Equal(t, 2, strings.Count(string(out), "<path"))
+	})
+
+	t.Run("text is escaped", func(t *testing.T) {
+		out, err := Chart(&domain.ChartData{Title: "R&D <costs>", Kind: domain.ChartKindLine}, opts)
+		require.NoError(t, err)
+		assert.Contains(t, string(out), "R&amp;D &lt;costs&gt;")
+	})
+}
+
+func TestChart_PNG(t *testing.T) {
+	opts, err := NewOptions("png", 640, 360)
+	require.NoError(t, err)
+
+	for name, chart := range testCharts() {
+		t.Run(name, func(t *testing.T) {
+			out, err := Chart(chart, opts)
+			require.NoError(t, err)
+
+			img, err := png.Decode(bytes.NewReader(out))
+			require.NoError(t, err)
+			assert.Equal(t, 640, img.Bounds().Dx())
+			assert.Equal(t, 360, img.Bounds().Dy())
+
+			// Something other than the background must have been drawn
+			painted := false
+			for y := 0; y < 360 && !painted; y += 2 {
+				for x := 0; x < 640; x += 2 {
+					if r, g, b, _ := img.At(x, y).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+						painted = true
+						break
+					}
+				}
+			}
+			assert.True(t, painted)
+		})
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	opts, err := NewOptions("", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, Options{Format: FormatSVG, Width: DefaultWidth, Height: DefaultHeight}, opts)
+
+	opts, err = NewOptions("PNG", 300, 300)
+	require.NoError(t, err)
+	assert.Equal(t, FormatPNG, opts.Format)
+	assert.Equal(t, "image/png", opts.Format.ContentType())
+
+	_, err = NewOptions("gif", 0, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidRenderOptions)
+	_, err = NewOptions("svg", MaxSize+1, 300)
+	assert.ErrorIs(t, err, domain.ErrInvalidRenderOptions)
+	_, err = NewOptions("svg", 300, MinSize-1)
+	assert.ErrorIs(t, err, domain.ErrInvalidRenderOptions)
+}
+
+func TestNiceTicks(t *testing.T) {
+	lo, hi, step := niceTicks(1200, 2900, 5)
+	assert.Equal(t, 1000.0, lo)
+	assert.Equal(t, 3000.0, hi)
+	assert.Equal(t, 500.0, step)
+
+	lo, hi, step = niceTicks(0.5, 3.2, 6)
+	assert.Equal(t, 0.5, lo)
+	assert.InDelta(t, 3.5, hi, 1e-9)
+	assert.Equal(t, 0.5, step)
+
+	// Degenerate ranges are widened
+	lo, hi, _ = niceTicks(4, 4, 5)
+	assert.Less(t, lo, 4.0)
+	assert.Greater(t, hi, 4.0)
+
+	// Ranges narrower than float64 precision at their magnitude still make distinct ticks
+	for _, r := range [][2]float64{{1e16, 10000000000000004}, {1e300, 1e300}, {-1e308, 1e308}, {math.Inf(-1), math.NaN()}} {
+		lo, hi, step = niceTicks(r[0], r[1], 5)
+		values := ticks(lo, hi, step)
+		assert.LessOrEqual(t, len(values), 12, "%v", r)
+		assert.GreaterOrEqual(t, len(values), 2, "%v", r)
+		for i := 1; i < len(values); i++ {
+			assert.Greater(t, values[i], values[i-1], "%v", r)
+		}
+	}
+}
+
+func TestChart_SubULPRange(t *testing.T) {
+	charts := []*domain.ChartData{
+		{
+			Title: "Values", Kind: domain.ChartKindBar, Categories: []string{"a", "b"},
+			Series: []domain.ChartSeries{{Name: "values", Values: []float64{1e16, 10000000000000004}}},
+		},
+		{
+			Title: "Points", Kind: domain.ChartKindScatter,
+			Series: []domain.ChartSeries{{Name: "points", Points: []domain.ChartPoint{{X: 1e16, Y: 1e16}, {X: 10000000000000004, Y: 10000000000000004}}}},
+		},
+	}
+	for _, chart := range charts {
+		for _, format := range []Format{FormatSVG, FormatPNG} {
+			done := make(chan error, 1)
+			go func() {
+				_, err := Chart(chart, Options{Format: format, Width: 400, Height: 300})
+				done <- err
+			}()
+			select {
+			case err := <-done:
+				assert.NoError(t, err)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("rendering %s as %s did not finish", chart.Title, format)
+			}
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	cache := NewCache(2)
+	assetID := uuid.New()
+	opts := Options{Format: FormatSVG, Width: 800, Height: 450}
+	v1 := time.Now()
+	v2 := v1.Add(time.Second)
+
+	k1 := Key(assetID, v1, opts)
+	k2 := Key(assetID, v2, opts)
+	k3 := Key(assetID, v2, Options{Format: FormatPNG, Width: 800, Height: 450})
+	assert.NotEqual(t, k1, k2, "a new asset version must not hit the old rendering")
+
+	cache.Put(k1, []byte("one"))
+	cache.Put(k2, []byte("two"))
+	got, ok := cache.Get(k1) // k1 becomes most recently used
+	require.True(t, ok)
+	assert.Equal(t, []byte("one"), got)
+
+	cache.Put(k3, []byte("three")) // evicts k2
+	_, ok = cache.Get(k2)
+	assert.False(t, ok)
+	_, ok = cache.Get(k1)
+	assert.True(t, ok)
+	assert.Equal(t, 2, cache.Len())
+
+	disabled := NewCache(0)
+	disabled.Put(k1, []byte("one"))
+	assert.Equal(t, 0, disabled.Len())
+}
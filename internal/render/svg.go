@@ -0,0 +1,108 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+)
+
+const svgFontFamily = "Helvetica, Arial, sans-serif"
+
+// svgCanvas writes drawing primitives as SVG elements
+type svgCanvas struct {
+	buf bytes.Buffer
+}
+
+func newSVGCanvas(width, height int) *svgCanvas {
+	c := &svgCanvas{}
+	fmt.Fprintf(&c.buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="%s">`,
+		width, height, width, height, svgFontFamily)
+	c.buf.WriteByte('\n')
+	return c
+}
+
+// Bytes closes the document and returns it
+func (c *svgCanvas) Bytes() []byte {
+	c.buf.WriteString("</svg>\n")
+	return c.buf.Bytes()
+}
+
+func (c *svgCanvas) Rect(x, y, w, h float64, fill color.RGBA) {
+	fmt.Fprintf(&c.buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n", num(x), num(y), num(w), num(h), hex(fill))
+}
+
+func (c *svgCanvas) Line(x1, y1, x2, y2 float64, stroke color.RGBA, width float64) {
+	fmt.Fprintf(&c.buf, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s" stroke-width="%s"/>`+"\n",
+		num(x1), num(y1), num(x2), num(y2), hex(stroke), num(width))
+}
+
+func (c *svgCanvas) Polyline(points []point, stroke color.RGBA, width float64) {
+	coords := make([]string, len(points))
+	for i, p := range points {
+		coords[i] = num(p.X) + "," + num(p.Y)
+	}
+	fmt.Fprintf(&c.buf, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%s" stroke-linejoin="round"/>`+"\n",
+		strings.Join(coords, " "), hex(stroke), num(width))
+}
+
+func (c *svgCanvas) Circle(cx, cy, r float64, fill color.RGBA) {
+	fmt.Fprintf(&c.buf, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n", num(cx), num(cy), num(r), hex(fill))
+}
+
+func (c *svgCanvas) Wedge(cx, cy, r, startAngle, endAngle float64, fill color.RGBA) {
+	if endAngle-startAngle >= 2*math.Pi-1e-9 {
+		c.Circle(cx, cy, r, fill)
+		return
+	}
+	x1, y1 := cx+r*math.Cos(startAngle), cy+r*math.Sin(startAngle)
+	x2, y2 := cx+r*math.Cos(endAngle), cy+r*math.Sin(endAngle)
+	largeArc := 0
+	if endAngle-startAngle > math.Pi {
+		largeArc = 1
+	}
+	fmt.Fprintf(&c.buf, `<path d="M %s %s L %s %s A %s %s 0 %d 1 %s %s Z" fill="%s" stroke="#ffffff" stroke-width="1"/>`+"\n",
+		num(cx), num(cy), num(x1), num(y1), num(r), num(r), largeArc, num(x2), num(y2), hex(fill))
+}
+
+func (c *svgCanvas) Text(x, y float64, s string, size float64, a anchor, fill color.RGBA) {
+	fmt.Fprintf(&c.buf, `<text x="%s" y="%s" font-size="%s" text-anchor="%s" fill="%s">%s</text>`+"\n",
+		num(x), num(y), num(size), svgAnchor(a), hex(fill), escape(s))
+}
+
+func (c *svgCanvas) VerticalText(x, y float64, s string, size float64, fill color.RGBA) {
+	fmt.Fprintf(&c.buf, `<text x="%s" y="%s" font-size="%s" text-anchor="middle" fill="%s" transform="rotate(-90 %s %s)">%s</text>`+"\n",
+		num(x), num(y), num(size), hex(fill), num(x), num(y), escape(s))
+}
+
+// TextWidth approximates the rendered width of proportional sans-serif text
+func (c *svgCanvas) TextWidth(s string, size float64) float64 {
+	return float64(len([]rune(s))) * size * 0.6
+}
+
+func svgAnchor(a anchor) string {
+	switch a {
+	case anchorMiddle:
+		return "middle"
+	case anchorEnd:
+		return "end"
+	default:
+		return "start"
+	}
+}
+
+func num(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ListAudit returns the audit entries matching a query, oldest first, with the number of entries
+// matching its filters from the start of the trail
+func (r *MemoryRepository) ListAudit(ctx context.Context, query *domain.AuditQuery) ([]*domain.AuditEntry, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := []*domain.AuditEntry{}
+	total := 0
+	for _, entry := range r.auditLog {
+		if !query.Matches(entry) {
+			continue
+		}
+		total++
+		if entry.Seq > query.AfterSeq && len(entries) < query.Limit {
+			view := *entry
+			entries = append(entries, &view)
+		}
+	}
+	return entries, total, nil
+}
+
+// audit appends an entry of a change, made by the context's actor, to the audit trail, chained to
+// the last entry; the caller holds the write lock, so that the entry is appended if and only if the
+// change is made
+func (r *MemoryRepository) audit(ctx context.Context, action domain.AuditAction, targets []domain.AuditTarget, before, after interface{}) {
+	prevHash := domain.AuditGenesisHash
+	if n := len(r.auditLog); n > 0 {
+		prevHash = r.auditLog[n-1].Hash
+	}
+	entry := domain.NewAuditEntry(domain.ActorFromContext(ctx), action, targets, before, after)
+	entry.Seal(uint64(len(r.auditLog))+1, prevHash)
+	r.auditLog = append(r.auditLog, entry)
+}
+
+// target identifies a resource in audit entries
+func target(kind string, id uuid.UUID) domain.AuditTarget {
+	return domain.AuditTarget{Type: kind, ID: id}
+}
+
+// favouriteTargets identifies a favourite, its user and its asset in audit entries
+func favouriteTargets(fav *domain.Favourite) []domain.AuditTarget {
+	return []domain.AuditTarget{target("user", fav.UserID), target("favourite", fav.ID), target("asset", fav.AssetID)}
+}
+
+// auditedShare returns a copy of a share as recorded in audit entries, without its link token,
+// which grants access to whoever holds it
+func auditedShare(share *domain.Share) *domain.Share {
+	if share == nil {
+		return nil
+	}
+	audited := shareView(share)
+	audited.Token = ""
+	return audited
+}
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func auditActions(entries []*domain.AuditEntry) []domain.AuditAction {
+	actions := make([]domain.AuditAction, len(entries))
+	for i, entry := range entries {
+		actions[i] = entry.Action
+	}
+	return actions
+}
+
+func TestMemoryRepository_Audit(t *testing.T) {
+	repo := NewRepository()
+	userID, otherID := uuid.New(), uuid.New()
+	ctx := domain.WithActor(context.Background(), domain.Actor{UserID: &userID, RequestID: "req-1"})
+
+	// Every change is audited with its actor and request; failed changes and no-ops are not
+	asset := createTestAsset(t, domain.AssetTypeChart)
+	require.NoError(t, repo.CreateAsset(ctx, asset))
+	require.NoError(t, repo.UpdateAssetDescription(ctx, asset.ID, "Updated"))
+	assert.ErrorIs(t, repo.UpdateAssetDescription(ctx, uuid.New(), "Missing"), domain.ErrNotFound)
+	fav := domain.NewFavourite(userID, asset.ID)
+	require.NoError(t, repo.AddFavourite(ctx, fav))
+	assert.ErrorIs(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, asset.ID)), domain.ErrAlreadyExists)
+	_, err := repo.SetFavouritePinned(ctx, userID, fav.ID, false)
+	require.NoError(t, err)
+	_, err = repo.UpdateFavourite(ctx, userID, fav.ID, domain.FavouriteUpdate{Title: ptr("Mine")})
+	require.NoError(t, err)
+	collection, err := domain.NewCollection(userID, "Reports")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateCollection(ctx, collection))
+	require.NoError(t, repo.AddToCollection(ctx, userID, collection.ID, fav.ID))
+	require.NoError(t, repo.AddToCollection(ctx, userID, collection.ID, fav.ID))
+	share, err := domain.NewShare(userID, &collection.ID, nil, true, domain.SharePermissionRead)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateShare(ctx, share))
+	redeeming := domain.WithActor(context.Background(), domain.Actor{UserID: &otherID})
+	_, err = repo.RedeemShareLink(redeeming, share.Token, otherID)
+	require.NoError(t, err)
+	require.NoError(t, repo.DeleteCollection(ctx, userID, collection.ID))
+	_, err = repo.RemoveFavourites(ctx, userID, []uuid.UUID{asset.ID, uuid.New()})
+	require.NoError(t, err)
+	_, err = repo.RemoveFavourites(ctx, userID, []uuid.UUID{asset.ID})
+	require.NoError(t, err)
+	_, err = repo.DeleteAsset(context.Background(), asset.ID, domain.DeleteRestrict)
+	require.NoError(t, err)
+
+	entries, total, err := repo.ListAudit(ctx, &domain.AuditQuery{Limit: domain.MaxAuditLimit})
+	require.NoError(t, err)
+	assert.Equal(t, len(entries), total)
+	assert.Equal(t, []domain.AuditAction{
+		domain.AuditAssetCreate, domain.AuditAssetUpdate, domain.AuditFavouriteAdd, domain.AuditFavouriteUpdate,
+		domain.AuditCollectionCreate, domain.AuditCollectionAdd, domain.AuditShareCreate, domain.AuditShareRedeem,
+		domain.AuditCollectionDelete, domain.AuditFavouritesRemove, domain.AuditAssetDelete,
+	}, auditActions(entries))
+	for i, entry := range entries {
+		assert.Equal(t, uint64(i+1), entry.Seq)
+	}
+	assert.Equal(t, &userID, entries[0].ActorID)
+	assert.Equal(t, "req-1", entries[0].RequestID)
+	assert.Equal(t, &otherID, entries[7].ActorID, "the actor is the one of each change")
+	assert.Nil(t, entries[10].ActorID, "changes made without an actor are anonymous")
+
+	// Entries carry the values before and after the change
+	var before, after domain.Asset
+	require.NoError(t, json.Unmarshal(entries[1].Before, &before))
+	require.NoError(t, json.Unmarshal(entries[1].After, &after))
+	assert.Equal(t, "Test Asset", before.Description)
+	assert.Equal(t, "Updated", after.Description)
+	assert.Nil(t, entries[2].Before)
+	assert.Nil(t, entries[10].After)
+	var redeemed domain.Share
+	require.NoError(t, json.Unmarshal(entries[7].After, &redeemed))
+	assert.Equal(t, []uuid.UUID{otherID}, redeemed.UserIDs)
+	assert.Empty(t, redeemed.Token, "link tokens are not recorded")
+	assert.Contains(t, entries[8].Targets, domain.AuditTarget{Type: "share", ID: share.ID}, "revoked shares are targets too")
+
+	// Entries are filtered and paged by sequence number; totals count every match
+	page, total, err := repo.ListAudit(ctx, &domain.AuditQuery{TargetID: &fav.ID, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []domain.AuditAction{domain.AuditFavouriteAdd, domain.AuditFavouriteUpdate}, auditActions(page))
+	page, total, err = repo.ListAudit(ctx, &domain.AuditQuery{TargetID: &fav.ID, AfterSeq: page[1].Seq, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 4, total)
+	assert.Equal(t, []domain.AuditAction{domain.AuditCollectionAdd, domain.AuditFavouritesRemove}, auditActions(page))
+	page, _, err = repo.ListAudit(ctx, &domain.AuditQuery{Action: "share", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.AuditAction{domain.AuditShareCreate, domain.AuditShareRedeem}, auditActions(page))
+
+	// Listed entries are copies: the trail cannot be changed through them
+	page[0].Action = domain.AuditShareRevoke
+	require.NoError(t, repo.Sanity(ctx))
+
+	// Tampering with the trail breaks the chain
+	repo.auditLog[3].Action = domain.AuditFavouritePin
+	assert.ErrorIs(t, repo.Sanity(ctx), domain.ErrDataIntegrity)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
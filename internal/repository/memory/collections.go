@@ -0,0 +1,254 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CreateCollection stores a new collection for its user
+func (r *MemoryRepository) CreateCollection(ctx context.Context, collection *domain.Collection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := domain.CollectionNameKey(collection.Name)
+	if _, exists := r.collectionNames[collection.UserID][key]; exists {
+		return domain.ErrAlreadyExists
+	}
+
+	if r.collections[collection.UserID] == nil {
+		r.collections[collection.UserID] = make(map[uuid.UUID]*domain.Collection)
+		r.collectionNames[collection.UserID] = make(map[string]uuid.UUID)
+	}
+	stored := *collection
+	r.collections[collection.UserID][collection.ID] = &stored
+	r.collectionNames[collection.UserID][key] = collection.ID
+	r.audit(ctx, domain.AuditCollectionCreate, collectionTargets(&stored), nil, &stored)
+	return nil
+}
+
+// GetCollection retrieves one of a user's collections
+func (r *MemoryRepository) GetCollection(ctx context.Context, userID, collectionID uuid.UUID) (*domain.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collection, exists := r.collections[userID][collectionID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return r.collectionView(collection), nil
+}
+
+// ListCollections returns all of a user's collections, ordered by name
+func (r *MemoryRepository) ListCollections(ctx context.Context, userID uuid.UUID) ([]*domain.Collection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	collections := make([]*domain.Collection, 0, len(r.collections[userID]))
+	for _, collection := range r.collections[userID] {
+		collections = append(collections, r.collectionView(collection))
+	}
+	sort.Slice(collections, func(i, j int) bool {
+		a, b := domain.CollectionNameKey(collections[i].Name), domain.CollectionNameKey(collections[j].Name)
+		if a != b {
+			return a < b
+		}
+		return collections[i].CreatedAt.Before(collections[j].CreatedAt)
+	})
+	return collections, nil
+}
+
+// RenameCollection renames a collection; the new name must not be used by another of the user's collections
+func (r *MemoryRepository) RenameCollection(ctx context.Context, userID, collectionID uuid.UUID, name string) (*domain.Collection, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, exists := r.collections[userID][collectionID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	oldKey, newKey := domain.CollectionNameKey(collection.Name), domain.CollectionNameKey(name)
+	if owner, taken := r.collectionNames[userID][newKey]; taken && owner != collectionID {
+		return nil, domain.ErrAlreadyExists
+	}
+
+	renamed := *collection
+	renamed.Name = name
+	renamed.UpdatedAt = time.Now()
+	r.collections[userID][collectionID] = &renamed
+	delete(r.collectionNames[userID], oldKey)
+	r.collectionNames[userID][newKey] = collectionID
+	r.audit(ctx, domain.AuditCollectionRename, collectionTargets(collection), r.collectionView(collection), r.collectionView(&renamed))
+	return r.collectionView(&renamed), nil
+}
+
+// DeleteCollection removes a collection; its favourites are kept
+func (r *MemoryRepository) DeleteCollection(ctx context.Context, userID, collectionID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, exists := r.collections[userID][collectionID]
+	if !exists {
+		return domain.ErrNotFound
+	}
+	targets := collectionTargets(collection)
+	before := r.collectionView(collection)
+	for favID := range r.collectionMembers[collectionID] {
+		r.leaveCollection(collectionID, favID)
+	}
+	for shareID := range r.ownerShares[userID] {
+		if share := r.shares[shareID]; share.CollectionID != nil && *share.CollectionID == collectionID {
+			targets = append(targets, target("share", shareID))
+			r.dropShare(share)
+		}
+	}
+	r.audit(ctx, domain.AuditCollectionDelete, targets, before, nil)
+	delete(r.collections[userID], collectionID)
+	delete(r.collectionNames[userID], domain.CollectionNameKey(collection.Name))
+	if len(r.collections[userID]) == 0 {
+		delete(r.collections, userID)
+		delete(r.collectionNames, userID)
+	}
+	return nil
+}
+
+// AddToCollection puts one of the user's favourites into one of the user's collections.
+// Adding a member again is a no-op.
+func (r *MemoryRepository) AddToCollection(ctx context.Context, userID, collectionID, favouriteID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, exists := r.collections[userID][collectionID]
+	if !exists {
+		return domain.ErrNotFound
+	}
+	if _, exists := r.favourites[userID][favouriteID]; !exists {
+		return domain.ErrNotFound
+	}
+	if _, member := r.collectionMembers[collectionID][favouriteID]; member {
+		return nil
+	}
+	r.joinCollection(ctx, collection, favouriteID)
+	return nil
+}
+
+// AddFavouriteToCollection stores a favourite, unless the user favourited its asset already, and
+// puts the user's favourite of the asset into one of their collections, under a single lock
+func (r *MemoryRepository) AddFavouriteToCollection(ctx context.Context, favourite *domain.Favourite, collectionID uuid.UUID) (*domain.Favourite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, exists := r.collections[favourite.UserID][collectionID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	if favID, exists := r.userAssets[favourite.UserID][favourite.AssetID]; exists {
+		if _, member := r.collectionMembers[collectionID][favID]; member {
+			return nil, domain.ErrAlreadyExists
+		}
+		favourite = r.favourites[favourite.UserID][favID]
+	} else {
+		if err := r.addFavourite(favourite); err != nil {
+			return nil, err
+		}
+		r.audit(ctx, domain.AuditFavouriteAdd, favouriteTargets(favourite), nil, favourite)
+	}
+	r.joinCollection(ctx, collection, favourite.ID)
+	return r.favouriteView(favourite, r.assets[favourite.AssetID]), nil
+}
+
+// joinCollection puts a favourite into a collection it is not a member of; the caller holds the
+// write lock
+func (r *MemoryRepository) joinCollection(ctx context.Context, collection *domain.Collection, favouriteID uuid.UUID) {
+	if r.collectionMembers[collection.ID] == nil {
+		r.collectionMembers[collection.ID] = make(map[uuid.UUID]struct{})
+	}
+	r.collectionMembers[collection.ID][favouriteID] = struct{}{}
+	if r.favouriteCollections[favouriteID] == nil {
+		r.favouriteCollections[favouriteID] = make(map[uuid.UUID]struct{})
+	}
+	r.favouriteCollections[favouriteID][collection.ID] = struct{}{}
+	r.touchCollection(collection)
+	r.audit(ctx, domain.AuditCollectionAdd, append(collectionTargets(collection), target("favourite", favouriteID)),
+		nil, r.collectionView(r.collections[collection.UserID][collection.ID]))
+}
+
+// RemoveFromCollection takes a favourite out of a collection
+func (r *MemoryRepository) RemoveFromCollection(ctx context.Context, userID, collectionID, favouriteID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	collection, exists := r.collections[userID][collectionID]
+	if !exists {
+		return domain.ErrNotFound
+	}
+	if _, member := r.collectionMembers[collectionID][favouriteID]; !member {
+		return domain.ErrNotFound
+	}
+	before := r.collectionView(collection)
+	r.leaveCollection(collectionID, favouriteID)
+	r.touchCollection(collection)
+	r.audit(ctx, domain.AuditCollectionRemove, append(collectionTargets(collection), target("favourite", favouriteID)),
+		before, r.collectionView(r.collections[userID][collectionID]))
+	return nil
+}
+
+// leaveCollection removes a favourite from a collection's members
+func (r *MemoryRepository) leaveCollection(collectionID, favouriteID uuid.UUID) {
+	delete(r.collectionMembers[collectionID], favouriteID)
+	if len(r.collectionMembers[collectionID]) == 0 {
+		delete(r.collectionMembers, collectionID)
+	}
+	delete(r.favouriteCollections[favouriteID], collectionID)
+	if len(r.favouriteCollections[favouriteID]) == 0 {
+		delete(r.favouriteCollections, favouriteID)
+	}
+}
+
+// touchCollection records a change of a collection's members
+func (r *MemoryRepository) touchCollection(collection *domain.Collection) {
+	touched := *collection
+	touched.UpdatedAt = time.Now()
+	r.collections[collection.UserID][collection.ID] = &touched
+}
+
+// collectionTargets identifies a collection and its user in audit entries
+func collectionTargets(collection *domain.Collection) []domain.AuditTarget {
+	return []domain.AuditTarget{target("user", collection.UserID), target("collection", collection.ID)}
+}
+
+// collectionView returns a copy of a stored collection with its member count
+func (r *MemoryRepository) collectionView(collection *domain.Collection) *domain.Collection {
+	view := *collection
+	view.FavouriteCount = len(r.collectionMembers[collection.ID])
+	return &view
+}
+
+// favouriteView returns a copy of a stored favourite with its asset and collections attached
+func (r *MemoryRepository) favouriteView(fav *domain.Favourite, asset *domain.Asset) *domain.Favourite {
+	view := *fav
+	view.Asset = asset
+	if collections := r.favouriteCollections[fav.ID]; len(collections) > 0 {
+		view.CollectionIDs = make([]uuid.UUID, 0, len(collections))
+		for collectionID := range collections {
+			view.CollectionIDs = append(view.CollectionIDs, collectionID)
+		}
+		sort.Slice(view.CollectionIDs, func(i, j int) bool {
+			return bytes.Compare(view.CollectionIDs[i][:], view.CollectionIDs[j][:]) < 0
+		})
+	}
+	return &view
+}
+
+// collectionFavourites returns a copy of the IDs of a collection's members
+func (r *MemoryRepository) collectionFavourites(collectionID uuid.UUID) map[uuid.UUID]struct{} {
+	members := make(map[uuid.UUID]struct{}, len(r.collectionMembers[collectionID]))
+	for favID := range r.collectionMembers[collectionID] {
+		members[favID] = struct{}{}
+	}
+	return members
+}
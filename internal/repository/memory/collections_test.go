@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createCollection(t *testing.T, repo *MemoryRepository, userID uuid.UUID, name string) *domain.Collection {
+	t.Helper()
+
+	collection, err := domain.NewCollection(userID, name)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateCollection(context.Background(), collection))
+	return collection
+}
+
+func TestMemoryRepository_Collections(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID, otherUserID := uuid.New(), uuid.New()
+
+	review := createCollection(t, repo, userID, "Q4 review")
+	uk := createCollection(t, repo, userID, "UK audiences")
+	createCollection(t, repo, otherUserID, "Q4 review") // names are unique per user only
+
+	duplicate, err := domain.NewCollection(userID, "q4 REVIEW")
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.CreateCollection(ctx, duplicate), domain.ErrAlreadyExists)
+
+	t.Run("list and get", func(t *testing.T) {
+		collections, err := repo.ListCollections(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, collections, 2)
+		assert.Equal(t, "Q4 review", collections[0].Name)
+		assert.Equal(t, "UK audiences", collections[1].Name)
+
+		_, err = repo.GetCollection(ctx, otherUserID, review.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound, "collections are user-scoped")
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		_, err := repo.RenameCollection(ctx, userID, uk.ID, "q4 review")
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+
+		renamed, err := repo.RenameCollection(ctx, userID, uk.ID, "British audiences")
+		require.NoError(t, err)
+		assert.Equal(t, "British audiences", renamed.Name)
+		assert.True(t, renamed.UpdatedAt.After(uk.UpdatedAt) || renamed.UpdatedAt.Equal(uk.UpdatedAt))
+
+		// The old name is free again, and a case-only rename is allowed
+		createCollection(t, repo, userID, "UK audiences")
+		_, err = repo.RenameCollection(ctx, userID, uk.ID, "BRITISH audiences")
+		assert.NoError(t, err)
+
+		_, err = repo.RenameCollection(ctx, userID, uuid.New(), "x")
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestMemoryRepository_CollectionMembers(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	favs := make([]*domain.Favourite, 3)
+	for i, d := range []string{"a", "b", "c"} {
+		asset := createTaggedAsset(t, repo, d)
+		favs[i] = domain.NewFavourite(userID, asset.ID)
+		require.NoError(t, repo.AddFavourite(ctx, favs[i]))
+	}
+	review := createCollection(t, repo, userID, "Q4 review")
+	uk := createCollection(t, repo, userID, "UK audiences")
+
+	require.NoError(t, repo.AddToCollection(ctx, userID, review.ID, favs[0].ID))
+	require.NoError(t, repo.AddToCollection(ctx, userID, review.ID, favs[2].ID))
+	require.NoError(t, repo.AddToCollection(ctx, userID, review.ID, favs[2].ID)) // no-op
+	require.NoError(t, repo.AddToCollection(ctx, userID, uk.ID, favs[2].ID))
+
+	assert.ErrorIs(t, repo.AddToCollection(ctx, uuid.New(), review.ID, favs[1].ID), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.AddToCollection(ctx, userID, review.ID, uuid.New()), domain.ErrNotFound)
+	assert.ErrorIs(t, repo.RemoveFromCollection(ctx, userID, uk.ID, favs[0].ID), domain.ErrNotFound)
+
+	list := func(collectionID uuid.UUID) []string {
+		query := domain.NewPageQuery(10, 0, "description", "asc")
+		query.CollectionID = &collectionID
+		listed, total, err := repo.ListFavourites(ctx, userID, query)
+		require.NoError(t, err)
+		names := make([]string, len(listed))
+		for i, fav := range listed {
+			names[i] = fav.Asset.Description
+		}
+		assert.Equal(t, len(names), total)
+		return names
+	}
+
+	assert.Equal(t, []string{"a", "c"}, list(review.ID))
+	assert.Equal(t, []string{"c"}, list(uk.ID))
+
+	fav, err := repo.GetFavourite(ctx, userID, favs[2].ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{review.ID, uk.ID}, fav.CollectionIDs)
+
+	got, err := repo.GetCollection(ctx, userID, review.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.FavouriteCount)
+
+	t.Run("unknown collection", func(t *testing.T) {
+		query := domain.NewPageQuery(10, 0, "", "")
+		missing := uuid.New()
+		query.CollectionID = &missing
+		_, _, err := repo.ListFavourites(ctx, userID, query)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("removing a favourite leaves its collections", func(t *testing.T) {
+		require.NoError(t, repo.RemoveFavourite(ctx, userID, favs[0].ID))
+		assert.Equal(t, []string{"c"}, list(review.ID))
+		require.NoError(t, repo.Sanity(ctx))
+	})
+
+	t.Run("deleting an asset leaves the collections of its favourites", func(t *testing.T) {
+		_, err := repo.DeleteAsset(ctx, favs[2].AssetID, domain.DeleteRestrict)
+		require.NoError(t, err)
+		assert.Empty(t, list(review.ID))
+		assert.Empty(t, list(uk.ID))
+		require.NoError(t, repo.Sanity(ctx))
+	})
+
+	t.Run("deleting a collection keeps its favourites", func(t *testing.T) {
+		require.NoError(t, repo.AddToCollection(ctx, userID, uk.ID, favs[1].ID))
+		require.NoError(t, repo.DeleteCollection(ctx, userID, uk.ID))
+
+		fav, err := repo.GetFavourite(ctx, userID, favs[1].ID)
+		require.NoError(t, err)
+		assert.Empty(t, fav.CollectionIDs)
+		assert.ErrorIs(t, repo.DeleteCollection(ctx, userID, uk.ID), domain.ErrNotFound)
+
+		collections, err := repo.ListCollections(ctx, userID)
+		require.NoError(t, err)
+		assert.Len(t, collections, 1)
+	})
+}
+
+func TestMemoryRepository_AddFavouriteToCollection(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+	favourited := createTaggedAsset(t, repo, "favourited")
+	fresh := createTaggedAsset(t, repo, "fresh")
+	existing := domain.NewFavourite(userID, favourited.ID)
+	require.NoError(t, repo.AddFavourite(ctx, existing))
+	review := createCollection(t, repo, userID, "Q4 review")
+
+	// A new favourite is stored and put into the collection at once, an existing one is reused
+	added, err := repo.AddFavouriteToCollection(ctx, domain.NewFavourite(userID, fresh.ID), review.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{review.ID}, added.CollectionIDs)
+	assert.Equal(t, "fresh", added.Asset.Description)
+	reused, err := repo.AddFavouriteToCollection(ctx, domain.NewFavourite(userID, favourited.ID), review.ID)
+	require.NoError(t, err)
+	assert.Equal(t, existing.ID, reused.ID)
+	_, err = repo.AddFavouriteToCollection(ctx, domain.NewFavourite(userID, favourited.ID), review.ID)
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+
+	// Nothing is stored when the collection or the asset is missing
+	_, err = repo.AddFavouriteToCollection(ctx, domain.NewFavourite(userID, uuid.New()), review.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	other := createTaggedAsset(t, repo, "other")
+	_, err = repo.AddFavouriteToCollection(ctx, domain.NewFavourite(userID, other.ID), uuid.New())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	isFavourite, err := repo.IsFavourite(ctx, userID, other.ID)
+	require.NoError(t, err)
+	assert.False(t, isFavourite)
+
+	collection, err := repo.GetCollection(ctx, userID, review.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, collection.FavouriteCount)
+	require.NoError(t, repo.Sanity(ctx))
+}
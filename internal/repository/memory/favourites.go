@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// AddFavourites favourites a batch of assets for a user under a single lock, so that the batch
+// applies as a whole. Results are reported per asset ID, in order; an asset listed twice is
+// reported as already existing the second time.
+func (r *MemoryRepository) AddFavourites(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.BatchResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]domain.BatchResult, len(assetIDs))
+	for i, assetID := range assetIDs {
+		results[i] = domain.BatchResult{AssetID: assetID}
+		fav := domain.NewFavourite(userID, assetID)
+		switch err := r.addFavourite(fav); {
+		case err == nil:
+			results[i].Status = domain.BatchCreated
+			results[i].Favourite = r.favouriteView(fav, nil)
+		case errors.Is(err, domain.ErrNotFound):
+			results[i].Status = domain.BatchAssetNotFound
+		case errors.Is(err, domain.ErrAlreadyExists):
+			results[i].Status = domain.BatchAlreadyExists
+		default:
+			return nil, err
+		}
+	}
+	if targets, added := batchChanges(userID, results, domain.BatchCreated); len(added) > 0 {
+		r.audit(ctx, domain.AuditFavouritesAdd, targets, nil, added)
+	}
+	return results, nil
+}
+
+// RemoveFavourites removes a user's favourites of a batch of assets under a single lock.
+// Results are reported per asset ID, in order.
+func (r *MemoryRepository) RemoveFavourites(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.BatchResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]domain.BatchResult, len(assetIDs))
+	for i, assetID := range assetIDs {
+		results[i] = domain.BatchResult{AssetID: assetID, Status: domain.BatchNotFound}
+		favID, exists := r.userAssets[userID][assetID]
+		if !exists {
+			continue
+		}
+		fav := r.favourites[userID][favID]
+		results[i].Status = domain.BatchRemoved
+		results[i].Favourite = r.favouriteView(fav, nil)
+		r.recordFavourite(domain.EventFavouriteRemoved, fav)
+		r.dropFavourite(fav)
+	}
+	if targets, removed := batchChanges(userID, results, domain.BatchRemoved); len(removed) > 0 {
+		r.audit(ctx, domain.AuditFavouritesRemove, targets, removed, nil)
+	}
+	return results, nil
+}
+
+// batchChanges returns the favourites a batch changed, those with the given status, along with
+// their audit targets
+func batchChanges(userID uuid.UUID, results []domain.BatchResult, status domain.BatchStatus) ([]domain.AuditTarget, []*domain.Favourite) {
+	targets := []domain.AuditTarget{target("user", userID)}
+	var changed []*domain.Favourite
+	for _, result := range results {
+		if result.Status == status {
+			targets = append(targets, target("favourite", result.Favourite.ID), target("asset", result.AssetID))
+			changed = append(changed, result.Favourite)
+		}
+	}
+	return targets, changed
+}
+
+// MoveFavourite places a favourite right after or right before another favourite of the same
+// pin group. Only the moved favourite gets a new position.
+func (r *MemoryRepository) MoveFavourite(ctx context.Context, userID, favouriteID uuid.UUID, afterID, beforeID *uuid.UUID) (*domain.Favourite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fav, exists := r.favourites[userID][favouriteID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	if (afterID == nil) == (beforeID == nil) {
+		return nil, domain.ErrInvalidPosition
+	}
+	neighbourID := afterID
+	if neighbourID == nil {
+		neighbourID = beforeID
+	}
+	neighbour, exists := r.favourites[userID][*neighbourID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	if neighbour.ID == fav.ID || neighbour.Pinned != fav.Pinned {
+		return nil, domain.ErrInvalidPosition
+	}
+
+	// The new position lies between the neighbour and the favourite next to it on the
+	// other side, not counting the favourite being moved
+	index := r.favouriteOrder[userID]["position"]
+	at := index.search(indexEntry{key: domain.FavouriteSortKey(neighbour, "position"), id: neighbour.ID})
+	lo, hi := "", ""
+	if afterID != nil {
+		lo = neighbour.Position
+		hi = r.groupPosition(index, at+1, 1, fav)
+	} else {
+		lo = r.groupPosition(index, at-1, -1, fav)
+		hi = neighbour.Position
+	}
+	position, err := domain.PositionBetween(lo, hi)
+	if err != nil {
+		return nil, err
+	}
+
+	moved := *fav
+	moved.Position = position
+	r.replaceFavourite(fav, &moved)
+	r.audit(ctx, domain.AuditFavouriteMove, favouriteTargets(fav), fav, &moved)
+	return r.favouriteView(&moved, r.assets[moved.AssetID]), nil
+}
+
+// SetFavouritePinned pins or unpins a favourite. A newly pinned favourite goes to the end of the
+// pinned ones, an unpinned one to the top of the others.
+func (r *MemoryRepository) SetFavouritePinned(ctx context.Context, userID, favouriteID uuid.UUID, pinned bool) (*domain.Favourite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fav, exists := r.favourites[userID][favouriteID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	if fav.Pinned == pinned {
+		return r.favouriteView(fav, r.assets[fav.AssetID]), nil
+	}
+
+	var position string
+	var err error
+	if pinned {
+		position, err = domain.PositionBetween(r.lastPosition(userID, true), "")
+	} else {
+		position, err = domain.PositionBetween("", r.firstPosition(userID, false))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *fav
+	updated.Pinned = pinned
+	updated.Position = position
+	r.replaceFavourite(fav, &updated)
+	action := domain.AuditFavouriteUnpin
+	if pinned {
+		action = domain.AuditFavouritePin
+	}
+	r.audit(ctx, action, favouriteTargets(fav), fav, &updated)
+	return r.favouriteView(&updated, r.assets[updated.AssetID]), nil
+}
+
+// UpdateFavourite changes the annotations of a favourite
+func (r *MemoryRepository) UpdateFavourite(ctx context.Context, userID, favouriteID uuid.UUID, update domain.FavouriteUpdate) (*domain.Favourite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fav, exists := r.favourites[userID][favouriteID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	updated := *fav
+	update.Apply(&updated)
+	r.replaceFavourite(fav, &updated)
+	r.indexNotes(&updated)
+	r.audit(ctx, domain.AuditFavouriteUpdate, favouriteTargets(fav), fav, &updated)
+	return r.favouriteView(&updated, r.assets[updated.AssetID]), nil
+}
+
+// replaceFavourite stores an updated version of a favourite in place of the previous one.
+// Stored favourites are replaced rather than mutated, as callers may hold the previous version.
+func (r *MemoryRepository) replaceFavourite(previous, fav *domain.Favourite) {
+	asset := r.assets[fav.AssetID]
+	r.unindexFavourite(previous, asset)
+	r.favourites[fav.UserID][fav.ID] = fav
+	r.indexFavourite(fav, asset)
+}
+
+// groupPosition walks the user's position index from entry i in the given direction and returns
+// the position of the first favourite other than skip, provided it shares skip's pin group;
+// otherwise the group ends there and the empty (open) position is returned.
+func (r *MemoryRepository) groupPosition(index *orderedIndex, i, step int, skip *domain.Favourite) string {
+	for ; i >= 0 && i < len(index.entries); i += step {
+		if index.entries[i].id == skip.ID {
+			continue
+		}
+		fav := r.favourites[skip.UserID][index.entries[i].id]
+		if fav.Pinned != skip.Pinned {
+			return ""
+		}
+		return fav.Position
+	}
+	return ""
+}
+
+// firstPosition returns the lowest position in a pin group of the user's favourites, or "" if it is empty
+func (r *MemoryRepository) firstPosition(userID uuid.UUID, pinned bool) string {
+	index := r.favouriteOrder[userID]["position"]
+	if index == nil {
+		return ""
+	}
+	i := index.search(indexEntry{key: pinGroup(pinned)})
+	if i == len(index.entries) || index.entries[i].key[:1] != pinGroup(pinned) {
+		return ""
+	}
+	return index.entries[i].key[1:]
+}
+
+// lastPosition returns the highest position in a pin group of the user's favourites, or "" if it is empty
+func (r *MemoryRepository) lastPosition(userID uuid.UUID, pinned bool) string {
+	index := r.favouriteOrder[userID]["position"]
+	if index == nil {
+		return ""
+	}
+	// Group keys are the group prefix followed by the position, so the group ends where the next prefix starts
+	i := index.search(indexEntry{key: string(rune(pinGroup(pinned)[0] + 1))}) - 1
+	if i < 0 || index.entries[i].key[:1] != pinGroup(pinned) {
+		return ""
+	}
+	return index.entries[i].key[1:]
+}
+
+// pinGroup returns the sort key prefix of a pin group (see domain.FavouriteSortKey)
+func pinGroup(pinned bool) string {
+	if pinned {
+		return "0"
+	}
+	return "1"
+}
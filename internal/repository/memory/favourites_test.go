@@ -0,0 +1,230 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_FavouritePositions(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	favs := make(map[string]*domain.Favourite)
+	for _, d := range []string{"a", "b", "c", "d"} {
+		asset := createTaggedAsset(t, repo, d)
+		favs[d] = domain.NewFavourite(userID, asset.ID)
+		require.NoError(t, repo.AddFavourite(ctx, favs[d]))
+	}
+
+	order := func() []string {
+		t.Helper()
+		listed, _, err := repo.ListFavourites(ctx, userID, domain.NewPageQuery(10, 0, "position", ""))
+		require.NoError(t, err)
+		names := make([]string, len(listed))
+		for i, fav := range listed {
+			names[i] = fav.Asset.Description
+		}
+		return names
+	}
+
+	// New favourites are appended
+	assert.Equal(t, []string{"a", "b", "c", "d"}, order())
+
+	t.Run("move", func(t *testing.T) {
+		moved, err := repo.MoveFavourite(ctx, userID, favs["d"].ID, &favs["a"].ID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "d", "b", "c"}, order())
+		assert.NotEqual(t, favs["d"].Position, moved.Position, "the stored favourite is replaced, not mutated")
+
+		_, err = repo.MoveFavourite(ctx, userID, favs["a"].ID, nil, &favs["c"].ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"d", "b", "a", "c"}, order())
+
+		// Moving next to the favourite's current neighbour keeps the order
+		_, err = repo.MoveFavourite(ctx, userID, favs["a"].ID, &favs["b"].ID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"d", "b", "a", "c"}, order())
+
+		_, err = repo.MoveFavourite(ctx, userID, favs["d"].ID, &favs["c"].ID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "a", "c", "d"}, order())
+	})
+
+	t.Run("invalid moves", func(t *testing.T) {
+		_, err := repo.MoveFavourite(ctx, userID, favs["a"].ID, &favs["a"].ID, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidPosition)
+		_, err = repo.MoveFavourite(ctx, userID, favs["a"].ID, &favs["b"].ID, &favs["c"].ID)
+		assert.ErrorIs(t, err, domain.ErrInvalidPosition)
+		unknown := uuid.New()
+		_, err = repo.MoveFavourite(ctx, userID, favs["a"].ID, &unknown, nil)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = repo.MoveFavourite(ctx, uuid.New(), favs["a"].ID, &favs["b"].ID, nil)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("pin", func(t *testing.T) {
+		pinned, err := repo.SetFavouritePinned(ctx, userID, favs["c"].ID, true)
+		require.NoError(t, err)
+		assert.True(t, pinned.Pinned)
+		_, err = repo.SetFavouritePinned(ctx, userID, favs["d"].ID, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c", "d", "b", "a"}, order())
+
+		// Pinned and unpinned favourites move within their own group only
+		_, err = repo.MoveFavourite(ctx, userID, favs["a"].ID, nil, &favs["c"].ID)
+		assert.ErrorIs(t, err, domain.ErrInvalidPosition)
+		_, err = repo.MoveFavourite(ctx, userID, favs["c"].ID, &favs["d"].ID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"d", "c", "b", "a"}, order())
+
+		// Unpinning puts the favourite first among the unpinned ones
+		_, err = repo.SetFavouritePinned(ctx, userID, favs["d"].ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c", "d", "b", "a"}, order())
+
+		// New favourites still go to the end
+		e := domain.NewFavourite(userID, createTaggedAsset(t, repo, "e").ID)
+		require.NoError(t, repo.AddFavourite(ctx, e))
+		assert.Equal(t, []string{"c", "d", "b", "a", "e"}, order())
+
+		_, err = repo.SetFavouritePinned(ctx, userID, uuid.New(), true)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	assert.NoError(t, repo.Sanity(ctx))
+}
+
+func TestMemoryRepository_UpdateFavourite(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID, otherUserID := uuid.New(), uuid.New()
+
+	asset := createTaggedAsset(t, repo, "Q4 sales", "finance")
+	fav := domain.NewFavourite(userID, asset.ID)
+	require.NoError(t, repo.AddFavourite(ctx, fav))
+	otherFav := domain.NewFavourite(otherUserID, asset.ID)
+	require.NoError(t, repo.AddFavourite(ctx, otherFav))
+	unrelated := domain.NewFavourite(userID, createTaggedAsset(t, repo, "Social media usage").ID)
+	require.NoError(t, repo.AddFavourite(ctx, unrelated))
+
+	title, note := "Board deck", "ask finance about the churn spike"
+	updated, err := repo.UpdateFavourite(ctx, userID, fav.ID, domain.FavouriteUpdate{Title: &title, Note: &note})
+	require.NoError(t, err)
+	assert.Equal(t, "Board deck", updated.Title)
+	assert.Equal(t, "ask finance about the churn spike", updated.Note)
+	assert.Empty(t, fav.Title, "the stored favourite is replaced, not mutated")
+
+	_, err = repo.UpdateFavourite(ctx, otherUserID, fav.ID, domain.FavouriteUpdate{Title: &title})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	search := func(userID uuid.UUID, text string) []uuid.UUID {
+		t.Helper()
+		favs, total, err := repo.SearchFavourites(ctx, userID, text, domain.NewPageQuery(10, 0, "", ""))
+		require.NoError(t, err)
+		assert.Equal(t, len(favs), total)
+		ids := make([]uuid.UUID, len(favs))
+		for i, f := range favs {
+			ids[i] = f.ID
+		}
+		return ids
+	}
+
+	t.Run("annotations are searchable by their user only", func(t *testing.T) {
+		assert.Equal(t, []uuid.UUID{fav.ID}, search(userID, "churn"))
+		assert.Equal(t, []uuid.UUID{fav.ID}, search(userID, "board"))
+		assert.Empty(t, search(otherUserID, "churn"))
+		assert.Equal(t, []uuid.UUID{otherFav.ID}, search(otherUserID, "sales"))
+	})
+
+	t.Run("tokens may match the asset or the annotations", func(t *testing.T) {
+		assert.Equal(t, []uuid.UUID{fav.ID}, search(userID, "sales churn"))
+		assert.Empty(t, search(userID, "social churn"))
+	})
+
+	t.Run("clearing an annotation drops its terms", func(t *testing.T) {
+		empty := ""
+		_, err := repo.UpdateFavourite(ctx, userID, fav.ID, domain.FavouriteUpdate{Note: &empty})
+		require.NoError(t, err)
+		assert.Empty(t, search(userID, "churn"))
+		assert.Equal(t, []uuid.UUID{fav.ID}, search(userID, "board"))
+
+		got, err := repo.GetFavourite(ctx, userID, fav.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Board deck", got.Title)
+		assert.Empty(t, got.Note)
+	})
+
+	t.Run("removed favourites are not found", func(t *testing.T) {
+		require.NoError(t, repo.RemoveFavourite(ctx, userID, fav.ID))
+		assert.Empty(t, search(userID, "board"))
+	})
+}
+
+func TestMemoryRepository_BatchFavourites(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	a, b, c := createTaggedAsset(t, repo, "a"), createTaggedAsset(t, repo, "b"), createTaggedAsset(t, repo, "c")
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, a.ID)))
+	missing := uuid.New()
+
+	statuses := func(results []domain.BatchResult) []domain.BatchStatus {
+		out := make([]domain.BatchStatus, len(results))
+		for i, result := range results {
+			out[i] = result.Status
+		}
+		return out
+	}
+
+	results, err := repo.AddFavourites(ctx, userID, []uuid.UUID{a.ID, b.ID, missing, c.ID, b.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BatchStatus{
+		domain.BatchAlreadyExists, domain.BatchCreated, domain.BatchAssetNotFound, domain.BatchCreated, domain.BatchAlreadyExists,
+	}, statuses(results))
+	assert.Equal(t, missing, results[2].AssetID)
+	require.NotNil(t, results[1].Favourite)
+	assert.Equal(t, b.ID, results[1].Favourite.AssetID)
+	assert.Nil(t, results[0].Favourite)
+
+	// Batch additions are appended in request order
+	listed, total, err := repo.ListFavourites(ctx, userID, domain.NewPageQuery(10, 0, "position", ""))
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []uuid.UUID{a.ID, b.ID, c.ID}, []uuid.UUID{listed[0].AssetID, listed[1].AssetID, listed[2].AssetID})
+
+	results, err = repo.RemoveFavourites(ctx, userID, []uuid.UUID{b.ID, missing, b.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.BatchStatus{domain.BatchRemoved, domain.BatchNotFound, domain.BatchNotFound}, statuses(results))
+	assert.Equal(t, results[0].Favourite.ID, listed[1].ID)
+
+	isFav, err := repo.IsFavourite(ctx, userID, b.ID)
+	require.NoError(t, err)
+	assert.False(t, isFav)
+	assert.NoError(t, repo.Sanity(ctx))
+}
+
+func TestMemoryRepository_FavouritedAssets(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	a, b := createTaggedAsset(t, repo, "a"), createTaggedAsset(t, repo, "b")
+	fav := domain.NewFavourite(userID, a.ID)
+	require.NoError(t, repo.AddFavourite(ctx, fav))
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(uuid.New(), b.ID)))
+
+	favourited, err := repo.FavouritedAssets(ctx, userID, []uuid.UUID{a.ID, b.ID, uuid.New()})
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID]uuid.UUID{a.ID: fav.ID}, favourited)
+
+	favourited, err = repo.FavouritedAssets(ctx, uuid.New(), []uuid.UUID{a.ID})
+	require.NoError(t, err)
+	assert.Empty(t, favourited)
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// filteredAssets returns the IDs of the assets selected by the query's tag filter and Filter, or
+// nil when the query selects every asset. Time ranges are looked up in the ordered indexes, and
+// the remaining clauses are only checked against the assets left over.
+func (r *MemoryRepository) filteredAssets(query *domain.PageQuery) map[uuid.UUID]struct{} {
+	var selected map[uuid.UUID]struct{}
+	if len(query.Tags) > 0 {
+		selected = r.taggedAssets(query.Tags, query.TagMatch)
+	}
+	f := query.Filter
+	if f == nil {
+		return selected
+	}
+
+	selected = intersect(selected, r.assetOrder["created_at"].timeRange(f.CreatedAt))
+	selected = intersect(selected, r.assetOrder["updated_at"].timeRange(f.UpdatedAt))
+	if selected == nil {
+		selected = make(map[uuid.UUID]struct{}, len(r.assets))
+		for assetID := range r.assets {
+			selected[assetID] = struct{}{}
+		}
+	}
+	for assetID := range selected {
+		if !f.MatchAsset(r.assets[assetID]) {
+			delete(selected, assetID)
+		}
+	}
+	return selected
+}
+
+// filteredFavourites returns the IDs of the user's favourites selected by the query's collection,
+// tag filter and Filter, or nil when the query selects all of them. The user's ordered indexes cover the
+// favourite's creation time and the asset's update time.
+func (r *MemoryRepository) filteredFavourites(userID uuid.UUID, query *domain.PageQuery) map[uuid.UUID]struct{} {
+	var selected map[uuid.UUID]struct{}
+	if query.AssetID != nil {
+		selected = make(map[uuid.UUID]struct{}, 1)
+		if favID, ok := r.userAssets[userID][*query.AssetID]; ok {
+			selected[favID] = struct{}{}
+		}
+	}
+	if query.CollectionID != nil {
+		selected = intersect(selected, r.collectionFavourites(*query.CollectionID))
+	}
+	if len(query.Tags) > 0 {
+		selected = intersect(selected, r.taggedFavourites(userID, query))
+	}
+	f := query.Filter
+	if f == nil {
+		return selected
+	}
+
+	userFavs := r.favourites[userID]
+	order := r.favouriteOrder[userID]
+	selected = intersect(selected, order["created_at"].timeRange(f.FavouritedAt))
+	selected = intersect(selected, order["updated_at"].timeRange(f.UpdatedAt))
+	if selected == nil {
+		selected = make(map[uuid.UUID]struct{}, len(userFavs))
+		for favID := range userFavs {
+			selected[favID] = struct{}{}
+		}
+	}
+	for favID := range selected {
+		fav := userFavs[favID]
+		if !f.MatchFavourite(fav, r.assets[fav.AssetID]) {
+			delete(selected, favID)
+		}
+	}
+	return selected
+}
+
+// intersect returns the IDs present in both sets, where a nil set stands for everything. The
+// result may be one of the arguments.
+func intersect(a, b map[uuid.UUID]struct{}) map[uuid.UUID]struct{} {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+	for id := range a {
+		if _, ok := b[id]; !ok {
+			delete(a, id)
+		}
+	}
+	return a
+}
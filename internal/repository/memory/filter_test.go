@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_Filter(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+	day := func(d int) time.Time { return time.Date(2025, 10, d, 12, 0, 0, 0, time.UTC) }
+
+	create := func(assetType domain.AssetType, description string, created int, data interface{}, tags ...string) *domain.Asset {
+		asset, err := domain.NewAsset(assetType, description, data)
+		require.NoError(t, err)
+		require.NoError(t, asset.SetTags(tags))
+		asset.CreatedAt, asset.UpdatedAt = day(created), day(created)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+
+		fav := domain.NewFavourite(userID, asset.ID)
+		fav.CreatedAt = day(created + 10)
+		require.NoError(t, repo.AddFavourite(ctx, fav))
+		return asset
+	}
+	chart := domain.ChartData{Title: "chart"}
+	insight := domain.InsightData{Text: "insight"}
+	create(domain.AssetTypeChart, "a sales", 1, chart, "tv")
+	create(domain.AssetTypeChart, "b sales", 5, chart)
+	create(domain.AssetTypeInsight, "c sales", 5, insight, "tv")
+	costs := create(domain.AssetTypeInsight, "d costs", 9, insight)
+	create(domain.AssetTypeChart, "e costs", 12, chart, "tv")
+
+	tests := []struct {
+		name   string
+		filter string
+		tags   []string
+		want   []string
+	}{
+		{name: "type", filter: "type:chart", want: []string{"a sales", "b sales", "e costs"}},
+		{name: "created range", filter: "created_at>=2025-10-05,created_at<2025-10-10", want: []string{"b sales", "c sales", "d costs"}},
+		{name: "updated day", filter: "updated_at:2025-10-05", want: []string{"b sales", "c sales"}},
+		{name: "description", filter: "description~SALES", want: []string{"a sales", "b sales", "c sales"}},
+		{name: "combined", filter: "type:chart,created_at>2025-10-01,description~sales", want: []string{"b sales"}},
+		{name: "with tags", filter: "type:chart", tags: []string{"tv"}, want: []string{"a sales", "e costs"}},
+		{name: "empty range", filter: "created_at>2025-10-10,created_at<2025-10-02", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := domain.NewPageQuery(10, 0, "description", "asc")
+			require.NoError(t, query.SetTagFilter(tt.tags, ""))
+			require.NoError(t, query.SetFilter([]string{tt.filter}, domain.FilterAssets))
+
+			assets, total, err := repo.ListAssets(ctx, query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, descriptions(assets))
+			assert.Equal(t, len(tt.want), total)
+		})
+	}
+
+	t.Run("favourites", func(t *testing.T) {
+		query := domain.NewPageQuery(1, 1, "created_at", "asc")
+		require.NoError(t, query.SetFilter([]string{"favourited_at>2025-10-15,type:chart|insight"}, domain.FilterFavourites))
+
+		favs, total, err := repo.ListFavourites(ctx, userID, query)
+		require.NoError(t, err)
+		assert.Equal(t, 2, total)
+		require.Len(t, favs, 1)
+		assert.Equal(t, "e costs", favs[0].Asset.Description)
+
+		query = domain.NewPageQuery(10, 0, "created_at", "asc")
+		require.NoError(t, query.SetFilter([]string{"updated_at<2025-10-06,description~costs"}, domain.FilterFavourites))
+		favs, total, err = repo.ListFavourites(ctx, userID, query)
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+		assert.Empty(t, favs)
+	})
+
+	t.Run("favourites by asset", func(t *testing.T) {
+		query := domain.NewPageQuery(10, 0, "created_at", "asc")
+		query.AssetID = &costs.ID
+		favs, total, err := repo.ListFavourites(ctx, userID, query)
+		require.NoError(t, err)
+		assert.Equal(t, 1, total)
+		require.Len(t, favs, 1)
+		assert.Equal(t, costs.ID, favs[0].AssetID)
+
+		require.NoError(t, query.SetFilter([]string{"type:chart"}, domain.FilterFavourites))
+		_, total, err = repo.ListFavourites(ctx, userID, query)
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+
+		_, total, err = repo.ListFavourites(ctx, uuid.New(), query)
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+	})
+}
@@ -1,31 +1,65 @@
 // Package memory is an in-memory implementation of the repository interface, limited by the physical memory available
 // of the running instance (node or container). This implementation provides:
-//   - For individual Get, Add, Update, Remove operations on assets, on average O(1) time complexity.
-//     In the worst-case scenario (key hash collisions) operations could degrade to O(N),
-//     but this should be rare as well-randomized uuid.UUID keys are used.
-//   - For AddFavourite and RemoveFavourite operations, average O(M) time complexity where M is the number of favourites
-//     for a user, due to the need to check for existing favourites before adding/removing. An additional index that would
-//     map (userID, assetID) to favouriteID could reduce this to O(1) at the cost of increased memory usage.
-//   - For IsFavourite operations, average O(M) time complexity where M is the number of favourites for a user,
-//     as it requires scanning the user's favourites. An additional index mapping (userID, assetID) to favouriteID
-//     could reduce this to O(1) but would increase memory usage.
-//   - For List operations (ListAssets, ListFavourites), a dominant time complexity of O(N log N) due to sorting.
-//     Pagination is applied after sorting, which is O(1), so N refers to the total number of items before pagination.
-//   - For DeleteAsset operations, a quite poor O(U * <F>) time complexity where U is the number of users and
-//     <F> is the average number of favourites per user, since it requires scanning all users' favourites to remove hanging
-//     references to the deleted asset. Depending on how rare (or needed at all) asset deletions are, this may be acceptable.
-//   - Thread syncrhonization via sync.RWMutex allowing concurrent read but serializing write operations. This is generally
+//   - For GetAsset, on average O(1) time complexity. In the worst-case scenario (key hash collisions) lookups could
+//     degrade to O(N), but this should be rare as well-randomized uuid.UUID keys are used. Adding, updating and
+//     removing an asset also maintains the ordered indexes below, which costs O(N) per asset.
+//   - For IsFavourite, average O(1) time complexity thanks to an index mapping (userID, assetID) to favouriteID, at
+//     the cost of some additional memory per favourite. AddFavourite and RemoveFavourite find duplicates and the
+//     favourite through it in O(1), then update the user's ordered indexes below.
+//   - For List operations (ListAssets, ListFavourites), assets and each user's favourites are kept in ordered indexes,
+//     one per sort field, sorted by (sort key, ID). A page is read in O(log N + L) for a page of L items, whether it is
+//     addressed by cursor (keyset pagination) or by offset, instead of sorting all N items on every call. Keeping the
+//     indexes sorted makes every asset and favourite write O(N) (a binary search, then a slice shift of up to N
+//     entries per index), so that loading N assets or N favourites of a user one by one costs O(N^2) overall.
+//     Updating an asset also re-positions it in the indexes of every user who favourited it.
+//   - With a tag filter, candidates come from an inverted tag index (tag -> assetIDs), intersected from the rarest tag
+//     for AND filters and unioned for OR filters. Small candidate sets are sorted on their own (O(C log C)), large ones
+//     are filtered while walking the ordered index.
+//   - With a filter (domain.Filter), time ranges on indexed fields are read from the ordered indexes in O(log N + M)
+//     for M entries in range; other clauses are checked per remaining candidate, or per item when no clause narrows
+//     the candidates (O(N)), so that totals stay exact.
+//   - For DeleteAsset operations, O(U) time complexity where U is the number of users who favourited the asset,
+//     thanks to an index mapping each asset to its fans.
+//   - Full-text search uses an inverted index (internal/search) over the assets' searchable text, updated on every
+//     asset mutation. A search costs O(P + R log R), where P is the number of postings of the matching terms and R the
+//     number of results to rank. Favourite annotations (titles and notes) have an index per user, keyed by
+//     favouriteID, so that a user's ranking and search cost do not depend on the notes of others; searching
+//     favourites combines the asset index with the user's token by token.
+//   - Collections keep their members in a set per collection, mirrored by a set of collections per favourite, so that
+//     membership changes are O(1), removing a favourite leaves its collections in O(C) for its C collections, and
+//     listing a collection narrows the favourite indexes to its members like a tag filter does.
+//   - Each asset carries its favourite count, updated on every favourite added or removed, with an ordered index of
+//     its own for listing by popularity. Favourites added and removed are also counted per asset in hourly buckets,
+//     kept for domain.MaxTrendingWindow; ranking trending assets costs O(E + T log T) for the E bucket entries in the
+//     window and the T assets found there, without visiting any user's favourites.
+//   - Recommendations come from a co-favourite index counting, for each pair of assets, the users who favourited both.
+//     Only the maxPairedFavourites most recent favourites of each user are paired, bounding the pairs a user adds
+//     whatever their number of favourites; adding or removing a favourite updates the pairs of the assets entering or
+//     leaving the user's most recent ones, O(maxPairedFavourites) each. Recommending visits the co-favourites of each
+//     of the user's favourites, O(F * K) for K co-favourites per asset, plus sorting the candidates.
+//   - Shares are kept by ID, with a set of shares per owner and per recipient and a map of link tokens, so that
+//     access checks, redeeming a link and the "shared with me" listing do not scan other users' shares.
+//   - Webhook deliveries are kept per webhook, by ID and in creation order, so that saving a delivery is O(1) but for
+//     dropping the oldest succeeded ones beyond domain.MaxWebhookHistory, and listing the history is O(D) for D deliveries.
+//   - Events of changes to favourites and assets are appended to an outbox under the lock of the change, numbered by
+//     a sequence; reading pending events is O(L) for L events read, acknowledging them O(A) for A events acknowledged.
+//   - Audit entries are appended to a trail under the lock of the change they record, chained by hash, in O(1) but
+//     for snapshotting the values changed. Listing the trail checks every entry against the filters, O(N), so that
+//     totals stay exact.
+//   - Asset references (insights pointing at audiences) are tracked in a reverse index, so that checking whether
+//     an asset is referenced on deletion is O(1) and cascading deletes only visit the referencing assets.
+//   - Thread synchronization via sync.RWMutex allowing concurrent read but serializing write operations. This is generally
 //     a good approach for in-memory stores. However, under high write contention, the mutex itself can also become a bottleneck.
 package memory
 
 import (
 	"context"
 	"fmt"
-	"sort"
 	"sync"
 	"time"
 
 	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/search"
 	"github.com/google/uuid"
 )
 
@@ -35,13 +69,73 @@ type MemoryRepository struct {
 	assets     map[uuid.UUID]*domain.Asset                   // assets indexed by assetID
 	favourites map[uuid.UUID]map[uuid.UUID]*domain.Favourite // favourites indexed first by userID, with each indexed by favouriteID
 	// (userID -> favouriteID -> Favourite)
+	userAssets   map[uuid.UUID]map[uuid.UUID]uuid.UUID // userID -> assetID -> favouriteID
+	assetUsers   map[uuid.UUID]map[uuid.UUID]struct{}  // assetID -> IDs of the users who favourited it
+	referencedBy map[uuid.UUID]map[uuid.UUID]struct{}  // assetID -> IDs of the assets referencing it
+	tagIndex     map[string]map[uuid.UUID]struct{}     // tag -> IDs of the assets carrying it
+	searchIndex  *search.Index                         // full-text index over assets
+	notesIndexes map[uuid.UUID]*search.Index           // userID -> full-text index over the user's favourite annotations
+
+	assetOrder     map[string]*orderedIndex               // sort field -> ordered asset IDs
+	favouriteOrder map[uuid.UUID]map[string]*orderedIndex // userID -> sort field -> ordered favourite IDs
+
+	collections          map[uuid.UUID]map[uuid.UUID]*domain.Collection // userID -> collectionID -> Collection
+	collectionNames      map[uuid.UUID]map[string]uuid.UUID             // userID -> name key -> collectionID
+	collectionMembers    map[uuid.UUID]map[uuid.UUID]struct{}           // collectionID -> IDs of its favourites
+	favouriteCollections map[uuid.UUID]map[uuid.UUID]struct{}           // favouriteID -> IDs of its collections
+
+	shares      map[uuid.UUID]*domain.Share          // shareID -> Share
+	ownerShares map[uuid.UUID]map[uuid.UUID]struct{} // ownerID -> IDs of the user's shares
+	sharedWith  map[uuid.UUID]map[uuid.UUID]struct{} // userID -> IDs of the shares granted to the user
+	shareTokens map[string]uuid.UUID                 // link token -> shareID
+
+	activity       map[int64]map[uuid.UUID]*activityCounts // bucket start (Unix time) -> assetID -> favourites added and removed
+	activityPruned int64                                   // bucket in which expired activity was last dropped
+
+	coFavourites map[uuid.UUID]map[uuid.UUID]int // assetID -> assetID -> number of users who favourited both
+
+	webhooks    map[uuid.UUID]*domain.Webhook                       // webhookID -> Webhook
+	deliveries  map[uuid.UUID]map[uuid.UUID]*domain.WebhookDelivery // webhookID -> deliveryID -> WebhookDelivery
+	deliveryLog map[uuid.UUID][]uuid.UUID                           // webhookID -> IDs of its deliveries, oldest first
+
+	outbox    []domain.Event // recorded events not yet acknowledged, oldest first
+	outboxSeq uint64         // ID of the last event recorded
+
+	auditLog []*domain.AuditEntry // audit trail, by sequence number (entry i has Seq i+1)
 }
 
 // NewRepository creates a new in-memory repository
 func NewRepository() *MemoryRepository {
 	return &MemoryRepository{
-		assets:     make(map[uuid.UUID]*domain.Asset),
-		favourites: make(map[uuid.UUID]map[uuid.UUID]*domain.Favourite),
+		assets:       make(map[uuid.UUID]*domain.Asset),
+		favourites:   make(map[uuid.UUID]map[uuid.UUID]*domain.Favourite),
+		userAssets:   make(map[uuid.UUID]map[uuid.UUID]uuid.UUID),
+		assetUsers:   make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		referencedBy: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		tagIndex:     make(map[string]map[uuid.UUID]struct{}),
+		searchIndex:  search.NewIndex(),
+		notesIndexes: make(map[uuid.UUID]*search.Index),
+
+		assetOrder:     newOrderedIndexes(assetSortFields),
+		favouriteOrder: make(map[uuid.UUID]map[string]*orderedIndex),
+
+		collections:          make(map[uuid.UUID]map[uuid.UUID]*domain.Collection),
+		collectionNames:      make(map[uuid.UUID]map[string]uuid.UUID),
+		collectionMembers:    make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		favouriteCollections: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+
+		shares:      make(map[uuid.UUID]*domain.Share),
+		ownerShares: make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		sharedWith:  make(map[uuid.UUID]map[uuid.UUID]struct{}),
+		shareTokens: make(map[string]uuid.UUID),
+
+		activity: make(map[int64]map[uuid.UUID]*activityCounts),
+
+		coFavourites: make(map[uuid.UUID]map[uuid.UUID]int),
+
+		webhooks:    make(map[uuid.UUID]*domain.Webhook),
+		deliveries:  make(map[uuid.UUID]map[uuid.UUID]*domain.WebhookDelivery),
+		deliveryLog: make(map[uuid.UUID][]uuid.UUID),
 	}
 }
 
@@ -50,60 +144,44 @@ func (r *MemoryRepository) ListFavourites(ctx context.Context, userID uuid.UUID,
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
+	if query.CollectionID != nil {
+		if _, exists := r.collections[userID][*query.CollectionID]; !exists {
+			return nil, 0, domain.ErrNotFound
+		}
+	}
+
 	userFavs, exists := r.favourites[userID]
-	if !exists {
+	if !exists || len(userFavs) == 0 {
 		return []*domain.Favourite{}, 0, nil
 	}
 
-	// Convert map to slice
-	favs := make([]*domain.Favourite, 0, len(userFavs))
-	for _, fav := range userFavs {
-		// Attach asset data
-		if asset, ok := r.assets[fav.AssetID]; ok {
-			favCopy := *fav
-			favCopy.Asset = asset
-			favs = append(favs, &favCopy)
-		} else {
-			// Return an error if the asset linked to a favourite does not exist
-			return nil, 0, domain.ErrDataIntegrity
-		}
+	field := sortField(query.SortBy, favouriteSortFields)
+	index := r.favouriteOrder[userID][field]
+	total := len(userFavs)
+	var match func(uuid.UUID) bool
+	if selected := r.filteredFavourites(userID, query); selected != nil {
+		total = len(selected)
+		index, match = narrow(index, selected, func(favID uuid.UUID) string {
+			fav := *userFavs[favID]
+			fav.Asset = r.assets[fav.AssetID]
+			return domain.FavouriteSortKey(&fav, field)
+		})
 	}
 
-	total := len(favs)
-
-	// Sort based on query
-	sort.Slice(favs, func(i, j int) bool {
-		switch query.SortBy {
-		case "type":
-			if query.Order == "asc" {
-				return favs[i].Asset.Type < favs[j].Asset.Type
-			}
-			return favs[i].Asset.Type > favs[j].Asset.Type
-		case "description":
-			if query.Order == "asc" {
-				return favs[i].Asset.Description < favs[j].Asset.Description
-			}
-			return favs[i].Asset.Description > favs[j].Asset.Description
-		default: // created_at
-			if query.Order == "asc" {
-				return favs[i].CreatedAt.Before(favs[j].CreatedAt)
-			}
-			return favs[i].CreatedAt.After(favs[j].CreatedAt)
+	ids := index.page(query, match)
+	favs := make([]*domain.Favourite, 0, len(ids))
+	for _, favID := range ids {
+		fav := userFavs[favID]
+		// Attach asset data; it is at hand, so it is attached whether or not query.IncludeAsset asks for it
+		asset, ok := r.assets[fav.AssetID]
+		if !ok {
+			// Return an error if the asset linked to a favourite does not exist
+			return nil, 0, domain.ErrDataIntegrity
 		}
-	})
-
-	// Apply pagination
-	start := query.Offset
-	end := query.Offset + query.Limit
-
-	if start > len(favs) {
-		return []*domain.Favourite{}, total, nil
-	}
-	if end > len(favs) {
-		end = len(favs)
+		favs = append(favs, r.favouriteView(fav, asset))
 	}
 
-	return favs[start:end], total, nil
+	return favs, total, nil
 }
 
 // GetFavourite retrieves a specific favourite
@@ -122,13 +200,7 @@ func (r *MemoryRepository) GetFavourite(ctx context.Context, userID, favouriteID
 	}
 
 	// Attach asset data
-	if asset, ok := r.assets[fav.AssetID]; ok {
-		favCopy := *fav
-		favCopy.Asset = asset
-		return &favCopy, nil
-	}
-
-	return fav, nil
+	return r.favouriteView(fav, r.assets[fav.AssetID]), nil
 }
 
 // AddFavourite adds a new favourite for a user
@@ -136,6 +208,15 @@ func (r *MemoryRepository) AddFavourite(ctx context.Context, favourite *domain.F
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if err := r.addFavourite(favourite); err != nil {
+		return err
+	}
+	r.audit(ctx, domain.AuditFavouriteAdd, favouriteTargets(favourite), nil, favourite)
+	return nil
+}
+
+// addFavourite stores a new favourite and indexes it; the caller holds the write lock
+func (r *MemoryRepository) addFavourite(favourite *domain.Favourite) error {
 	// Check if asset exists
 	if _, exists := r.assets[favourite.AssetID]; !exists {
 		return domain.ErrNotFound
@@ -147,13 +228,34 @@ func (r *MemoryRepository) AddFavourite(ctx context.Context, favourite *domain.F
 	}
 
 	// Check if already favourited
-	for _, fav := range r.favourites[favourite.UserID] {
-		if fav.AssetID == favourite.AssetID {
-			return domain.ErrAlreadyExists
+	if _, exists := r.userAssets[favourite.UserID][favourite.AssetID]; exists {
+		return domain.ErrAlreadyExists
+	}
+
+	// New favourites go to the end of the unpinned ones
+	if favourite.Position == "" {
+		position, err := domain.PositionBetween(r.lastPosition(favourite.UserID, false), "")
+		if err != nil {
+			return err
 		}
+		favourite.Position = position
 	}
 
+	paired := r.pairedAssets(favourite.UserID)
 	r.favourites[favourite.UserID][favourite.ID] = favourite
+	if r.userAssets[favourite.UserID] == nil {
+		r.userAssets[favourite.UserID] = make(map[uuid.UUID]uuid.UUID)
+	}
+	r.userAssets[favourite.UserID][favourite.AssetID] = favourite.ID
+	if r.assetUsers[favourite.AssetID] == nil {
+		r.assetUsers[favourite.AssetID] = make(map[uuid.UUID]struct{})
+	}
+	r.assetUsers[favourite.AssetID][favourite.UserID] = struct{}{}
+	r.favouriteAdded(favourite)
+	r.indexFavourite(favourite, r.assets[favourite.AssetID])
+	r.repairFavourites(favourite.UserID, paired)
+	r.indexNotes(favourite)
+	r.recordFavourite(domain.EventFavouriteAdded, favourite)
 	return nil
 }
 
@@ -167,11 +269,14 @@ func (r *MemoryRepository) RemoveFavourite(ctx context.Context, userID, favourit
 		return domain.ErrNotFound
 	}
 
-	if _, exists := userFavs[favouriteID]; !exists {
+	fav, exists := userFavs[favouriteID]
+	if !exists {
 		return domain.ErrNotFound
 	}
 
-	delete(userFavs, favouriteID)
+	r.recordFavourite(domain.EventFavouriteRemoved, fav)
+	r.audit(ctx, domain.AuditFavouriteRemove, favouriteTargets(fav), fav, nil)
+	r.dropFavourite(fav)
 	return nil
 }
 
@@ -180,18 +285,22 @@ func (r *MemoryRepository) IsFavourite(ctx context.Context, userID, assetID uuid
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	userFavs, exists := r.favourites[userID]
-	if !exists {
-		return false, nil
-	}
+	_, exists := r.userAssets[userID][assetID]
+	return exists, nil
+}
 
-	for _, fav := range userFavs {
-		if fav.AssetID == assetID {
-			return true, nil
+// FavouritedAssets looks up a batch of assets in the user's favourites, in O(1) per asset under a single read lock
+func (r *MemoryRepository) FavouritedAssets(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	favourited := make(map[uuid.UUID]uuid.UUID)
+	for _, assetID := range assetIDs {
+		if favID, exists := r.userAssets[userID][assetID]; exists {
+			favourited[assetID] = favID
 		}
 	}
-
-	return false, nil
+	return favourited, nil
 }
 
 // GetAsset retrieves an asset by ID
@@ -216,7 +325,27 @@ func (r *MemoryRepository) CreateAsset(ctx context.Context, asset *domain.Asset)
 		return domain.ErrAlreadyExists
 	}
 
-	r.assets[asset.ID] = asset
+	refs := asset.References()
+	for _, ref := range refs {
+		target, exists := r.assets[ref.AssetID]
+		if !exists {
+			return fmt.Errorf("%w: %s %s does not exist", domain.ErrInvalidReference, ref.Type, ref.AssetID)
+		}
+		if target.Type != ref.Type {
+			return fmt.Errorf("%w: %s is a %s, not a %s", domain.ErrInvalidReference, ref.AssetID, target.Type, ref.Type)
+		}
+	}
+
+	asset.FavouriteCount = 0 // counted as favourites are added
+	r.putAsset(nil, asset)
+	for _, ref := range refs {
+		if r.referencedBy[ref.AssetID] == nil {
+			r.referencedBy[ref.AssetID] = make(map[uuid.UUID]struct{})
+		}
+		r.referencedBy[ref.AssetID][asset.ID] = struct{}{}
+	}
+	r.recordAsset(domain.EventAssetCreated, asset.ID, asset)
+	r.audit(ctx, domain.AuditAssetCreate, []domain.AuditTarget{target("asset", asset.ID)}, nil, asset)
 	return nil
 }
 
@@ -230,33 +359,62 @@ func (r *MemoryRepository) UpdateAssetDescription(ctx context.Context, assetID u
 		return domain.ErrNotFound
 	}
 
-	asset.Description = description
-	asset.UpdatedAt = time.Now()
+	updated := *asset
+	updated.Description = description
+	updated.UpdatedAt = time.Now()
+	r.putAsset(asset, &updated)
+	r.recordAsset(domain.EventAssetUpdated, assetID, &updated)
+	r.audit(ctx, domain.AuditAssetUpdate, []domain.AuditTarget{target("asset", assetID)}, asset, &updated)
 	return nil
 }
 
-// DeleteAsset removes an asset
-func (r *MemoryRepository) DeleteAsset(ctx context.Context, assetID uuid.UUID) error {
+// DeleteAsset removes an asset along with its favourites, honouring the delete policy for referenced assets
+func (r *MemoryRepository) DeleteAsset(ctx context.Context, assetID uuid.UUID, policy domain.DeletePolicy) ([]uuid.UUID, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	if _, exists := r.assets[assetID]; !exists {
-		return domain.ErrNotFound
+		return nil, domain.ErrNotFound
 	}
 
-	// Remove asset
-	delete(r.assets, assetID)
+	if n := len(r.referencedBy[assetID]); n > 0 && policy != domain.DeleteCascade {
+		return nil, fmt.Errorf("%w: %s is referenced by %d asset(s)", domain.ErrAssetReferenced, assetID, n)
+	}
 
-	// Remove from all user favourites
-	for _, userFavs := range r.favourites {
-		for favID, fav := range userFavs {
-			if fav.AssetID == assetID {
-				delete(userFavs, favID)
+	// Collect the asset and, transitively, everything referencing it
+	deleted := []uuid.UUID{assetID}
+	seen := map[uuid.UUID]bool{assetID: true}
+	for i := 0; i < len(deleted); i++ {
+		for refID := range r.referencedBy[deleted[i]] {
+			if !seen[refID] {
+				seen[refID] = true
+				deleted = append(deleted, refID)
 			}
 		}
 	}
 
-	return nil
+	// Remove assets and their reference index entries
+	targets := make([]domain.AuditTarget, len(deleted))
+	removed := make([]*domain.Asset, len(deleted))
+	for i, id := range deleted {
+		targets[i], removed[i] = target("asset", id), r.assets[id]
+	}
+	for _, id := range deleted {
+		for _, ref := range r.assets[id].References() {
+			if referrers := r.referencedBy[ref.AssetID]; referrers != nil {
+				delete(referrers, id)
+				if len(referrers) == 0 {
+					delete(r.referencedBy, ref.AssetID)
+				}
+			}
+		}
+		delete(r.referencedBy, id)
+		r.dropAsset(r.assets[id])
+		r.recordAsset(domain.EventAssetDeleted, id, nil)
+	}
+	r.audit(ctx, domain.AuditAssetDelete, targets, removed, nil)
+
+	return deleted, nil
 }
 
 // ListAssets returns paginated list of all assets in the system
@@ -264,71 +422,242 @@ func (r *MemoryRepository) ListAssets(ctx context.Context, query *domain.PageQue
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
-	// Convert map to slice
-	assets := make([]*domain.Asset, 0, len(r.assets))
-	for _, asset := range r.assets {
-		assets = append(assets, asset)
+	field := sortField(query.SortBy, assetSortFields)
+	index := r.assetOrder[field]
+	total := len(r.assets)
+	var match func(uuid.UUID) bool
+	if selected := r.filteredAssets(query); selected != nil {
+		total = len(selected)
+		index, match = narrow(index, selected, func(assetID uuid.UUID) string {
+			return domain.AssetSortKey(r.assets[assetID], field)
+		})
 	}
 
-	total := len(assets)
+	ids := index.page(query, match)
+	assets := make([]*domain.Asset, len(ids))
+	for i, assetID := range ids {
+		assets[i] = r.assets[assetID]
+	}
 
-	// Sort based on query
-	sort.Slice(assets, func(i, j int) bool {
-		switch query.SortBy {
-		case "type":
-			if query.Order == "asc" {
-				return assets[i].Type < assets[j].Type
-			}
-			return assets[i].Type > assets[j].Type
-		case "description":
-			if query.Order == "asc" {
-				return assets[i].Description < assets[j].Description
-			}
-			return assets[i].Description > assets[j].Description
-		case "updated_at":
-			if query.Order == "asc" {
-				return assets[i].UpdatedAt.Before(assets[j].UpdatedAt)
-			}
-			return assets[i].UpdatedAt.After(assets[j].UpdatedAt)
-		default: // created_at
-			if query.Order == "asc" {
-				return assets[i].CreatedAt.Before(assets[j].CreatedAt)
-			}
-			return assets[i].CreatedAt.After(assets[j].CreatedAt)
+	return assets, total, nil
+}
+
+// Ping checks if the repository is accessible
+func (r *MemoryRepository) Ping(ctx context.Context) error {
+	return nil
+}
+
+// putAsset stores a new (previous is nil) or updated asset and brings every index up to date, in
+// O(N) for N assets as inserting into each sorted asset order shifts the entries after it.
+// Stored assets are replaced rather than mutated, as callers may hold the previous version.
+func (r *MemoryRepository) putAsset(previous, asset *domain.Asset) {
+	if previous != nil {
+		r.unindexTags(previous.ID, previous.Tags)
+		for _, field := range assetSortFields {
+			r.assetOrder[field].remove(domain.AssetSortKey(previous, field), previous.ID)
 		}
-	})
+	}
 
-	// Apply pagination
-	start := query.Offset
-	end := query.Offset + query.Limit
+	r.assets[asset.ID] = asset
+	r.indexTags(asset.ID, asset.Tags)
+	r.searchIndex.Put(asset.ID, assetSearchFields(asset))
+	for _, field := range assetSortFields {
+		r.assetOrder[field].insert(domain.AssetSortKey(asset, field), asset.ID)
+	}
+
+	// Favourites sorted by asset fields move along with the asset
+	if previous != nil {
+		for userID := range r.assetUsers[asset.ID] {
+			fav := r.favourites[userID][r.userAssets[userID][asset.ID]]
+			r.unindexFavourite(fav, previous)
+			r.indexFavourite(fav, asset)
+		}
+	}
+}
 
-	if start > len(assets) {
-		return []*domain.Asset{}, total, nil
+// dropAsset removes an asset, its favourites and its index entries. The favourites' removal is
+// recorded, as the event telling their users of the asset's deletion.
+func (r *MemoryRepository) dropAsset(asset *domain.Asset) {
+	for userID := range r.assetUsers[asset.ID] {
+		fav := r.favourites[userID][r.userAssets[userID][asset.ID]]
+		r.recordFavourite(domain.EventFavouriteRemoved, fav)
+		r.dropFavourite(fav)
 	}
-	if end > len(assets) {
-		end = len(assets)
+	asset = r.assets[asset.ID] // dropping its favourites replaced the asset with a recount
+	r.forgetActivity(asset.ID)
+	r.unindexTags(asset.ID, asset.Tags)
+	r.searchIndex.Remove(asset.ID)
+	for _, field := range assetSortFields {
+		r.assetOrder[field].remove(domain.AssetSortKey(asset, field), asset.ID)
 	}
+	delete(r.assets, asset.ID)
+}
 
-	return assets[start:end], total, nil
+// dropFavourite removes a favourite and its index entries
+func (r *MemoryRepository) dropFavourite(fav *domain.Favourite) {
+	paired := r.pairedAssets(fav.UserID)
+	r.unindexFavourite(fav, r.assets[fav.AssetID])
+	r.unindexNotes(fav)
+	for collectionID := range r.favouriteCollections[fav.ID] {
+		r.leaveCollection(collectionID, fav.ID)
+	}
+	delete(r.favourites[fav.UserID], fav.ID)
+	delete(r.userAssets[fav.UserID], fav.AssetID)
+	r.repairFavourites(fav.UserID, paired)
+	delete(r.assetUsers[fav.AssetID], fav.UserID)
+	if len(r.assetUsers[fav.AssetID]) == 0 {
+		delete(r.assetUsers, fav.AssetID)
+	}
+	r.favouriteRemoved(fav)
 }
 
-// Ping checks if the repository is accessible
-func (r *MemoryRepository) Ping(ctx context.Context) error {
-	return nil
+// indexFavourite adds a favourite of the given asset version to its user's ordered indexes
+func (r *MemoryRepository) indexFavourite(fav *domain.Favourite, asset *domain.Asset) {
+	orders := r.favouriteOrder[fav.UserID]
+	if orders == nil {
+		orders = newOrderedIndexes(favouriteSortFields)
+		r.favouriteOrder[fav.UserID] = orders
+	}
+	withAsset := *fav
+	withAsset.Asset = asset
+	for _, field := range favouriteSortFields {
+		orders[field].insert(domain.FavouriteSortKey(&withAsset, field), fav.ID)
+	}
+}
+
+// unindexFavourite removes a favourite of the given asset version from its user's ordered indexes
+func (r *MemoryRepository) unindexFavourite(fav *domain.Favourite, asset *domain.Asset) {
+	orders := r.favouriteOrder[fav.UserID]
+	if orders == nil {
+		return
+	}
+	withAsset := *fav
+	withAsset.Asset = asset
+	for _, field := range favouriteSortFields {
+		orders[field].remove(domain.FavouriteSortKey(&withAsset, field), fav.ID)
+	}
 }
 
-// Sanity performs a sanity test for orphan favourites.
+// Sanity performs a sanity test for orphan favourites, favourite counts, activity and co-favourite
+// pairs out of line with the favourites, dangling asset references, collection members that are not favourites of
+// the collection's owner, shares of missing collections, webhook deliveries out of line with their webhooks and
+// outbox events out of order.
 func (r *MemoryRepository) Sanity(ctx context.Context) error {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	// Check for orphan favourites
+	favourited := make(map[uuid.UUID]int, len(r.assets))
 	for userID, userFavs := range r.favourites {
 		for favID, fav := range userFavs {
+			favourited[fav.AssetID]++
 			if _, exists := r.assets[fav.AssetID]; !exists {
 				return fmt.Errorf("sanity check failed: orphan favourite found (userID: %s, favouriteID: %s, assetID: %s)", userID, favID, fav.AssetID)
 			}
 		}
 	}
+
+	// Check favourite counts, and activity of missing assets
+	for assetID, asset := range r.assets {
+		if asset.FavouriteCount != favourited[assetID] {
+			return fmt.Errorf("sanity check failed: favourite count mismatch (assetID: %s, count: %d, favourites: %d)", assetID, asset.FavouriteCount, favourited[assetID])
+		}
+	}
+	for _, counts := range r.activity {
+		for assetID := range counts {
+			if _, exists := r.assets[assetID]; !exists {
+				return fmt.Errorf("sanity check failed: activity of a missing asset found (assetID: %s)", assetID)
+			}
+		}
+	}
+
+	// Check the co-favourite index against the pairs of each user's most recent favourites
+	pairs := make(map[[2]uuid.UUID]int)
+	for userID := range r.favouriteOrder {
+		assets := r.pairedAssets(userID)
+		for _, assetID := range assets {
+			for _, otherID := range assets {
+				if otherID != assetID {
+					pairs[[2]uuid.UUID{assetID, otherID}]++
+				}
+			}
+		}
+	}
+	for assetID, others := range r.coFavourites {
+		for otherID, together := range others {
+			if pairs[[2]uuid.UUID{assetID, otherID}] != together {
+				return fmt.Errorf("sanity check failed: co-favourite count mismatch (assetID: %s, otherID: %s, count: %d, favourites: %d)", assetID, otherID, together, pairs[[2]uuid.UUID{assetID, otherID}])
+			}
+			delete(pairs, [2]uuid.UUID{assetID, otherID})
+		}
+	}
+	for pair := range pairs {
+		return fmt.Errorf("sanity check failed: co-favourite pair missing (assetID: %s, otherID: %s)", pair[0], pair[1])
+	}
+
+	// Check for dangling references
+	for assetID, asset := range r.assets {
+		for _, ref := range asset.References() {
+			if _, exists := r.assets[ref.AssetID]; !exists {
+				return fmt.Errorf("sanity check failed: dangling reference found (assetID: %s, referencedID: %s)", assetID, ref.AssetID)
+			}
+		}
+	}
+
+	// Check for dangling collection members
+	for userID, collections := range r.collections {
+		for collectionID := range collections {
+			for favID := range r.collectionMembers[collectionID] {
+				if _, exists := r.favourites[userID][favID]; !exists {
+					return fmt.Errorf("sanity check failed: dangling collection member found (userID: %s, collectionID: %s, favouriteID: %s)", userID, collectionID, favID)
+				}
+			}
+		}
+	}
+
+	// Check for shares of missing collections
+	for shareID, share := range r.shares {
+		if share.CollectionID == nil {
+			continue
+		}
+		if _, exists := r.collections[share.OwnerID][*share.CollectionID]; !exists {
+			return fmt.Errorf("sanity check failed: share of a missing collection found (shareID: %s, collectionID: %s)", shareID, *share.CollectionID)
+		}
+	}
+
+	// Check for deliveries of missing webhooks, and for delivery logs out of line with the deliveries
+	for webhookID, deliveries := range r.deliveries {
+		if _, exists := r.webhooks[webhookID]; !exists {
+			return fmt.Errorf("sanity check failed: deliveries of a missing webhook found (webhookID: %s)", webhookID)
+		}
+		log := r.deliveryLog[webhookID]
+		if len(log) != len(deliveries) {
+			return fmt.Errorf("sanity check failed: delivery log of webhook %s has %d entries for %d deliveries", webhookID, len(log), len(deliveries))
+		}
+		for _, deliveryID := range log {
+			if delivery, exists := deliveries[deliveryID]; !exists || delivery.WebhookID != webhookID {
+				return fmt.Errorf("sanity check failed: delivery log of webhook %s lists a foreign delivery %s", webhookID, deliveryID)
+			}
+		}
+	}
+
+	// Check that outbox events are numbered in order, by the sequence
+	var lastEventID uint64
+	for _, event := range r.outbox {
+		if event.ID <= lastEventID || event.ID > r.outboxSeq {
+			return fmt.Errorf("sanity check failed: outbox event %d out of order (previous: %d, sequence: %d)", event.ID, lastEventID, r.outboxSeq)
+		}
+		lastEventID = event.ID
+	}
+
+	// Check that the audit trail is numbered in order and chained unbroken
+	for i, entry := range r.auditLog {
+		if entry.Seq != uint64(i+1) {
+			return fmt.Errorf("sanity check failed: audit entry %d found at position %d", entry.Seq, i+1)
+		}
+	}
+	if _, err := domain.VerifyAuditChain(domain.AuditGenesisHash, r.auditLog); err != nil {
+		return fmt.Errorf("sanity check failed: %w", err)
+	}
 	return nil
 }
@@ -86,8 +86,9 @@ func TestMemoryRepository_DeleteAsset(t *testing.T) {
 	require.NoError(t, repo.AddFavourite(ctx, fav))
 
 	// Delete asset
-	err := repo.DeleteAsset(ctx, asset.ID)
+	deleted, err := repo.DeleteAsset(ctx, asset.ID, domain.DeleteRestrict)
 	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{asset.ID}, deleted)
 
 	// Verify asset is deleted
 	_, err = repo.GetAsset(ctx, asset.ID)
@@ -99,6 +100,73 @@ func TestMemoryRepository_DeleteAsset(t *testing.T) {
 	assert.Empty(t, favs)
 }
 
+func TestMemoryRepository_AssetReferences(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	newInsight := func(t *testing.T, audienceID uuid.UUID) *domain.Asset {
+		value := 40.0
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, "Millennials on social media", domain.InsightData{
+			Text:       "40% of millennials spend more than 3 hours on social media daily",
+			Value:      &value,
+			Unit:       "%",
+			AudienceID: &audienceID,
+		})
+		require.NoError(t, err)
+		return asset
+	}
+
+	t.Run("reference must exist and be an audience", func(t *testing.T) {
+		repo := NewRepository()
+		chart := createTestAsset(t, domain.AssetTypeChart)
+		require.NoError(t, repo.CreateAsset(ctx, chart))
+
+		err := repo.CreateAsset(ctx, newInsight(t, uuid.New()))
+		assert.ErrorIs(t, err, domain.ErrInvalidReference)
+
+		err = repo.CreateAsset(ctx, newInsight(t, chart.ID))
+		assert.ErrorIs(t, err, domain.ErrInvalidReference)
+	})
+
+	t.Run("restrict refuses to delete a referenced audience", func(t *testing.T) {
+		repo := NewRepository()
+		audience := createTestAsset(t, domain.AssetTypeAudience)
+		require.NoError(t, repo.CreateAsset(ctx, audience))
+		insight := newInsight(t, audience.ID)
+		require.NoError(t, repo.CreateAsset(ctx, insight))
+
+		_, err := repo.DeleteAsset(ctx, audience.ID, domain.DeleteRestrict)
+		assert.ErrorIs(t, err, domain.ErrAssetReferenced)
+
+		// Once the insight is gone the audience can be deleted
+		_, err = repo.DeleteAsset(ctx, insight.ID, domain.DeleteRestrict)
+		require.NoError(t, err)
+		_, err = repo.DeleteAsset(ctx, audience.ID, domain.DeleteRestrict)
+		require.NoError(t, err)
+		assert.NoError(t, repo.Sanity(ctx))
+	})
+
+	t.Run("cascade deletes referencing insights and their favourites", func(t *testing.T) {
+		repo := NewRepository()
+		audience := createTestAsset(t, domain.AssetTypeAudience)
+		require.NoError(t, repo.CreateAsset(ctx, audience))
+		insight := newInsight(t, audience.ID)
+		require.NoError(t, repo.CreateAsset(ctx, insight))
+		require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, insight.ID)))
+
+		deleted, err := repo.DeleteAsset(ctx, audience.ID, domain.DeleteCascade)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{audience.ID, insight.ID}, deleted)
+
+		_, err = repo.GetAsset(ctx, insight.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		isFav, err := repo.IsFavourite(ctx, userID, insight.ID)
+		require.NoError(t, err)
+		assert.False(t, isFav)
+		assert.NoError(t, repo.Sanity(ctx))
+	})
+}
+
 func TestMemoryRepository_AddFavourite(t *testing.T) {
 	repo := NewRepository()
 	ctx := context.Background()
@@ -351,8 +419,8 @@ func TestMemoryRepository_ListAssets(t *testing.T) {
 		time.Sleep(10 * time.Millisecond)
 
 		asset3, _ := domain.NewAsset(domain.AssetTypeAudience, "Third Audience", domain.AudienceData{
-			Gender: "Male", BirthCountry: "USA", AgeGroups: []string{"18-24"},
-			HoursSocialDaily: 3.0, PurchasesLastMonth: 5,
+			Gender: "Male", BirthCountry: "USA", AgeGroups: []domain.AgeGroup{"18-24"},
+			HoursSocialDaily: domain.AtLeast(3.0), PurchasesLastMonth: domain.AtLeast(5),
 		})
 
 		repo.CreateAsset(ctx, asset1)
@@ -414,8 +482,8 @@ func TestMemoryRepository_ListAssets(t *testing.T) {
 
 		// Create assets in reverse order
 		audienceAsset, _ := domain.NewAsset(domain.AssetTypeAudience, "Audience", domain.AudienceData{
-			Gender: "Female", BirthCountry: "UK", AgeGroups: []string{"25-34"},
-			HoursSocialDaily: 2.5, PurchasesLastMonth: 3,
+			Gender: "Female", BirthCountry: "GB", AgeGroups: []domain.AgeGroup{"25-34"},
+			HoursSocialDaily: domain.AtLeast(2.5), PurchasesLastMonth: domain.AtLeast(3),
 		})
 		insightAsset, _ := domain.NewAsset(domain.AssetTypeInsight, "Insight", domain.InsightData{
 			Text: "Some insight",
@@ -442,8 +510,8 @@ func TestMemoryRepository_ListAssets(t *testing.T) {
 		repo := NewRepository()
 
 		audienceAsset, _ := domain.NewAsset(domain.AssetTypeAudience, "Audience", domain.AudienceData{
-			Gender: "Female", BirthCountry: "UK", AgeGroups: []string{"25-34"},
-			HoursSocialDaily: 2.5, PurchasesLastMonth: 3,
+			Gender: "Female", BirthCountry: "GB", AgeGroups: []domain.AgeGroup{"25-34"},
+			HoursSocialDaily: domain.AtLeast(2.5), PurchasesLastMonth: domain.AtLeast(3),
 		})
 		chartAsset, _ := domain.NewAsset(domain.AssetTypeChart, "Chart", domain.ChartData{
 			Title: "Chart", AxisXTitle: "X", AxisYTitle: "Y", Data: [][]float64{{1, 2}},
@@ -616,9 +684,9 @@ func createTestAsset(t *testing.T, assetType domain.AssetType) *domain.Asset {
 		data = domain.AudienceData{
 			Gender:             "Male",
 			BirthCountry:       "USA",
-			AgeGroups:          []string{"25-34", "35-44"},
-			HoursSocialDaily:   2.5,
-			PurchasesLastMonth: 5,
+			AgeGroups:          []domain.AgeGroup{"25-34", "35-44"},
+			HoursSocialDaily:   domain.AtLeast(2.5),
+			PurchasesLastMonth: domain.AtLeast(5),
 		}
 	default:
 		t.Fatalf("unsupported asset type for test: %s", assetType)
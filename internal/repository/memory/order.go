@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"bytes"
+	"slices"
+	"sort"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// assetSortFields are the fields assets can be listed by. Favourites can be listed by the same
+// fields but popularity, where created_at is the favourite's and the others are the asset's, and
+// by the user's manual order. Popularity changes with every favourite, which would re-position
+// the asset in the indexes of all its fans, so favourites are not indexed by it.
+var (
+	assetSortFields     = []string{"created_at", "updated_at", "type", "description", "popularity"}
+	favouriteSortFields = append(slices.Clone(assetSortFields[:4]), "position")
+)
+
+// sortField maps a requested sort field onto one of the indexed fields, defaulting to created_at
+func sortField(sortBy string, fields []string) string {
+	for _, field := range fields {
+		if sortBy == field {
+			return field
+		}
+	}
+	return "created_at"
+}
+
+// newOrderedIndexes creates an empty ordered index per sort field
+func newOrderedIndexes(fields []string) map[string]*orderedIndex {
+	indexes := make(map[string]*orderedIndex, len(fields))
+	for _, field := range fields {
+		indexes[field] = &orderedIndex{}
+	}
+	return indexes
+}
+
+// narrow restricts a listing to a selection of IDs. Selections much smaller than the index are
+// sorted into a temporary index of their own; larger ones are filtered while walking the index.
+func narrow(index *orderedIndex, selected map[uuid.UUID]struct{}, key func(uuid.UUID) string) (*orderedIndex, func(uuid.UUID) bool) {
+	if len(selected)*8 < len(index.entries) {
+		entries := make([]indexEntry, 0, len(selected))
+		for id := range selected {
+			entries = append(entries, indexEntry{key: key(id), id: id})
+		}
+		return newOrderedIndex(entries), nil
+	}
+	return index, func(id uuid.UUID) bool {
+		_, ok := selected[id]
+		return ok
+	}
+}
+
+// indexEntry is a position in an ordered index
+type indexEntry struct {
+	key string
+	id  uuid.UUID
+}
+
+func (e indexEntry) less(other indexEntry) bool {
+	if e.key != other.key {
+		return e.key < other.key
+	}
+	return bytes.Compare(e.id[:], other.id[:]) < 0
+}
+
+// orderedIndex keeps entries sorted ascending by (key, id). Lookups are O(log N); inserts and
+// removals shift the tail of the slice, which is cheap in practice compared to re-sorting
+// the whole collection for every page.
+type orderedIndex struct {
+	entries []indexEntry
+}
+
+// newOrderedIndex builds an index from unsorted entries
+func newOrderedIndex(entries []indexEntry) *orderedIndex {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].less(entries[j]) })
+	return &orderedIndex{entries: entries}
+}
+
+// search returns the position of the first entry not less than e
+func (x *orderedIndex) search(e indexEntry) int {
+	return sort.Search(len(x.entries), func(i int) bool { return !x.entries[i].less(e) })
+}
+
+func (x *orderedIndex) insert(key string, id uuid.UUID) {
+	e := indexEntry{key: key, id: id}
+	i := x.search(e)
+	x.entries = append(x.entries, indexEntry{})
+	copy(x.entries[i+1:], x.entries[i:])
+	x.entries[i] = e
+}
+
+func (x *orderedIndex) remove(key string, id uuid.UUID) {
+	e := indexEntry{key: key, id: id}
+	if i := x.search(e); i < len(x.entries) && x.entries[i] == e {
+		x.entries = append(x.entries[:i], x.entries[i+1:]...)
+	}
+}
+
+// timeRange returns the IDs of the entries whose time sort keys lie within r, or nil when r is
+// unbounded. The index must be keyed by domain.TimeSortKey.
+func (x *orderedIndex) timeRange(r domain.TimeRange) map[uuid.UUID]struct{} {
+	if r.IsZero() {
+		return nil
+	}
+	lo, hi := 0, len(x.entries)
+	if !r.From.IsZero() {
+		lo = x.search(indexEntry{key: domain.TimeSortKey(r.From)})
+	}
+	if !r.To.IsZero() {
+		hi = x.search(indexEntry{key: domain.TimeSortKey(r.To)})
+	}
+	ids := make(map[uuid.UUID]struct{}, max(hi-lo, 0))
+	for i := lo; i < hi; i++ {
+		ids[x.entries[i].id] = struct{}{}
+	}
+	return ids
+}
+
+// page returns the IDs of one page of the index in the query's order. Without a cursor, the
+// first Offset matching entries are skipped; with one, the page starts right after the cursor
+// position (or ends right before it, for CursorPrev). Only entries accepted by match are
+// returned; a nil match accepts everything and lets offsets be applied in O(1).
+func (x *orderedIndex) page(query *domain.PageQuery, match func(uuid.UUID) bool) []uuid.UUID {
+	descending := query.Order != "asc"
+	backward := query.Cursor != nil && query.Cursor.Direction == domain.CursorPrev
+	if backward {
+		descending = !descending
+	}
+
+	// Find where the walk starts
+	var i int
+	skip := 0
+	switch {
+	case query.Cursor != nil:
+		at := indexEntry{key: query.Cursor.Key, id: query.Cursor.ID}
+		i = x.search(at)
+		if descending {
+			i-- // the entry before the cursor position
+		} else if i < len(x.entries) && x.entries[i] == at {
+			i++ // the entry after the cursor itself
+		}
+	case match == nil:
+		if descending {
+			i = len(x.entries) - 1 - query.Offset
+		} else {
+			i = query.Offset
+		}
+	default:
+		skip = query.Offset
+		if descending {
+			i = len(x.entries) - 1
+		}
+	}
+
+	step := 1
+	if descending {
+		step = -1
+	}
+	ids := make([]uuid.UUID, 0, min(query.Limit, len(x.entries)))
+	for ; i >= 0 && i < len(x.entries) && len(ids) < query.Limit; i += step {
+		id := x.entries[i].id
+		if match != nil && !match(id) {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	// Pages fetched backwards are returned in the listing's order
+	if backward {
+		for l, r := 0, len(ids)-1; l < r; l, r = l+1, r-1 {
+			ids[l], ids[r] = ids[r], ids[l]
+		}
+	}
+	return ids
+}
@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedIndex(t *testing.T) {
+	x := &orderedIndex{}
+	ids := make([]uuid.UUID, 5)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	// Equal keys are ordered by ID
+	for _, id := range ids {
+		x.insert("same", id)
+	}
+	x.insert("a", ids[0])
+	x.remove("same", ids[0])
+	x.remove("missing", ids[1]) // no-op
+
+	require.Len(t, x.entries, 5)
+	assert.Equal(t, indexEntry{key: "a", id: ids[0]}, x.entries[0])
+	for i := 2; i < len(x.entries); i++ {
+		assert.True(t, x.entries[i-1].less(x.entries[i]))
+	}
+
+	query := domain.NewPageQuery(2, 1, "created_at", "asc")
+	assert.Equal(t, []uuid.UUID{x.entries[1].id, x.entries[2].id}, x.page(query, nil))
+	query.Order = "desc"
+	assert.Equal(t, []uuid.UUID{x.entries[3].id, x.entries[2].id}, x.page(query, nil))
+
+	// Offsets apply to matching entries only
+	query = domain.NewPageQuery(2, 1, "created_at", "asc")
+	odd := func(id uuid.UUID) bool { return id != x.entries[1].id && id != x.entries[3].id }
+	assert.Equal(t, []uuid.UUID{x.entries[2].id, x.entries[4].id}, x.page(query, odd))
+}
+
+func TestMemoryRepository_CursorPagination(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+
+	for _, d := range []string{"c", "a", "e", "b", "d"} {
+		createTaggedAsset(t, repo, d)
+	}
+
+	pageAt := func(after *domain.Asset, direction domain.CursorDirection) []string {
+		query := domain.NewPageQuery(2, 0, "description", "asc")
+		query.Cursor = &domain.Cursor{
+			SortBy: "description", Order: "asc", Direction: direction,
+			Key: domain.AssetSortKey(after, "description"), ID: after.ID,
+		}
+		assets, _, err := repo.ListAssets(ctx, query)
+		require.NoError(t, err)
+		return descriptions(assets)
+	}
+
+	first, _, err := repo.ListAssets(ctx, domain.NewPageQuery(2, 0, "description", "asc"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, descriptions(first))
+
+	// Items created between fetches do not shift the following page
+	createTaggedAsset(t, repo, "aa")
+	assert.Equal(t, []string{"c", "d"}, pageAt(first[1], domain.CursorNext))
+
+	// Backwards from "c" yields the page before it, in listing order
+	third, _, err := repo.ListAssets(ctx, domain.NewPageQuery(1, 3, "description", "asc"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"c"}, descriptions(third))
+	assert.Equal(t, []string{"aa", "b"}, pageAt(third[0], domain.CursorPrev))
+
+	// The cursor item itself may be gone; the position still holds
+	_, err = repo.DeleteAsset(ctx, third[0].ID, domain.DeleteRestrict)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d", "e"}, pageAt(third[0], domain.CursorNext))
+	assert.Equal(t, []string{"aa", "b"}, pageAt(third[0], domain.CursorPrev))
+}
+
+func TestMemoryRepository_FavouriteCursorPagination(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	for _, d := range []string{"b", "a", "c"} {
+		asset := createTaggedAsset(t, repo, d)
+		require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, asset.ID)))
+	}
+
+	query := domain.NewPageQuery(2, 0, "description", "desc")
+	favs, total, err := repo.ListFavourites(ctx, userID, query)
+	require.NoError(t, err)
+	require.Len(t, favs, 2)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "c", favs[0].Asset.Description)
+
+	last := favs[1]
+	query.Cursor = &domain.Cursor{
+		SortBy: "description", Order: "desc", Direction: domain.CursorNext,
+		Key: domain.FavouriteSortKey(last, "description"), ID: last.ID,
+	}
+	favs, _, err = repo.ListFavourites(ctx, userID, query)
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+	assert.Equal(t, "a", favs[0].Asset.Description)
+}
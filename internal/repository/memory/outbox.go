@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// PendingEvents returns, at most limit, the recorded events not yet acknowledged, oldest first
+func (r *MemoryRepository) PendingEvents(ctx context.Context, limit int) ([]domain.Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := len(r.outbox)
+	if limit > 0 {
+		n = min(n, limit)
+	}
+	return append([]domain.Event(nil), r.outbox[:n]...), nil
+}
+
+// AckEvents drops the recorded events up to an ID from the outbox
+func (r *MemoryRepository) AckEvents(ctx context.Context, lastEventID uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	acked := 0
+	for acked < len(r.outbox) && r.outbox[acked].ID <= lastEventID {
+		acked++
+	}
+	if acked == len(r.outbox) {
+		r.outbox = nil // release the drained array
+	} else {
+		r.outbox = r.outbox[acked:]
+	}
+	return nil
+}
+
+// record numbers and timestamps the event of a change and adds it to the outbox; the caller holds
+// the write lock, so that the event is recorded if and only if the change is made
+func (r *MemoryRepository) record(event domain.Event) {
+	r.outboxSeq++
+	event.ID = r.outboxSeq
+	event.OccurredAt = time.Now()
+	r.outbox = append(r.outbox, event)
+}
+
+// recordAsset records an event about an asset; asset is nil for deleted assets
+func (r *MemoryRepository) recordAsset(eventType domain.EventType, assetID uuid.UUID, asset *domain.Asset) {
+	r.record(domain.NewAssetEvent(eventType, assetID, asset))
+}
+
+// recordFavourite records an event about a favourite, with its asset attached
+func (r *MemoryRepository) recordFavourite(eventType domain.EventType, fav *domain.Favourite) {
+	r.record(domain.NewFavouriteEvent(eventType, r.favouriteView(fav, r.assets[fav.AssetID])))
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventTypes(events []domain.Event) []domain.EventType {
+	types := make([]domain.EventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	return types
+}
+
+func TestMemoryRepository_Outbox(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// Every change is recorded with its event, numbered in order; failed changes record nothing
+	asset := createTestAsset(t, domain.AssetTypeChart)
+	require.NoError(t, repo.CreateAsset(ctx, asset))
+	require.NoError(t, repo.UpdateAssetDescription(ctx, asset.ID, "Updated"))
+	_, err := repo.AddAssetTags(ctx, asset.ID, []string{"sales"})
+	require.NoError(t, err)
+	_, err = repo.RemoveAssetTags(ctx, asset.ID, []string{"sales"})
+	require.NoError(t, err)
+	fav := domain.NewFavourite(userID, asset.ID)
+	require.NoError(t, repo.AddFavourite(ctx, fav))
+	assert.ErrorIs(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, asset.ID)), domain.ErrAlreadyExists)
+	require.NoError(t, repo.RemoveFavourite(ctx, userID, fav.ID))
+	_, err = repo.AddFavourites(ctx, userID, []uuid.UUID{asset.ID, uuid.New()})
+	require.NoError(t, err)
+	_, err = repo.RemoveFavourites(ctx, userID, []uuid.UUID{asset.ID, uuid.New()})
+	require.NoError(t, err)
+	kept := domain.NewFavourite(userID, asset.ID)
+	require.NoError(t, repo.AddFavourite(ctx, kept))
+	deleted, err := repo.DeleteAsset(ctx, asset.ID, domain.DeleteRestrict)
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+
+	events, err := repo.PendingEvents(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.EventType{
+		domain.EventAssetCreated, domain.EventAssetUpdated, domain.EventAssetUpdated, domain.EventAssetUpdated,
+		domain.EventFavouriteAdded, domain.EventFavouriteRemoved, domain.EventFavouriteAdded, domain.EventFavouriteRemoved,
+		domain.EventFavouriteAdded, domain.EventFavouriteRemoved, domain.EventAssetDeleted,
+	}, eventTypes(events))
+	for i, event := range events {
+		assert.Equal(t, uint64(i+1), event.ID)
+		assert.False(t, event.OccurredAt.IsZero())
+	}
+	assert.Equal(t, "Updated", events[1].Asset.Description)
+	assert.Equal(t, fav.ID, *events[4].FavouriteID)
+	require.NotNil(t, events[4].Favourite.Asset)
+	assert.Equal(t, 1, events[4].Favourite.Asset.FavouriteCount, "favourite events carry their asset, counted")
+	assert.Equal(t, kept.ID, *events[9].FavouriteID, "deleting an asset removes its favourites first")
+	assert.Nil(t, events[10].Asset)
+	require.NoError(t, repo.Sanity(ctx))
+
+	// Events stay pending until acknowledged, and are read again meanwhile
+	batch, err := repo.PendingEvents(ctx, 4)
+	require.NoError(t, err)
+	assert.Equal(t, events[:4], batch)
+	require.NoError(t, repo.AckEvents(ctx, batch[len(batch)-1].ID))
+	pending, err := repo.PendingEvents(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, events[4:], pending)
+
+	// Numbering goes on after the outbox is drained
+	require.NoError(t, repo.AckEvents(ctx, events[len(events)-1].ID))
+	pending, err = repo.PendingEvents(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+	require.NoError(t, repo.CreateAsset(ctx, createTestAsset(t, domain.AssetTypeInsight)))
+	pending, err = repo.PendingEvents(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, uint64(len(events)+1), pending[0].ID)
+	assert.NoError(t, repo.Sanity(ctx))
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// activityCounts counts the favourites of an asset added and removed within a bucket
+type activityCounts struct {
+	added, removed int
+}
+
+// TrendingAssets ranks the assets by the net number of favourites they gained since the given
+// time, counted per bucket from the start of the bucket since falls into. Only assets that
+// gained favourites are returned, at most limit of them; ties go to the more added, then to the
+// more popular asset.
+func (r *MemoryRepository) TrendingAssets(ctx context.Context, since time.Time, limit int) ([]domain.TrendingAsset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	from := bucketOf(since)
+	totals := make(map[uuid.UUID]*activityCounts)
+	for bucket, counts := range r.activity {
+		if bucket < from {
+			continue
+		}
+		for assetID, c := range counts {
+			total := totals[assetID]
+			if total == nil {
+				total = &activityCounts{}
+				totals[assetID] = total
+			}
+			total.added += c.added
+			total.removed += c.removed
+		}
+	}
+
+	trending := make([]domain.TrendingAsset, 0, len(totals))
+	for assetID, total := range totals {
+		if net := total.added - total.removed; net > 0 {
+			trending = append(trending, domain.TrendingAsset{
+				Asset:   r.assets[assetID],
+				Added:   total.added,
+				Removed: total.removed,
+				Net:     net,
+			})
+		}
+	}
+	sort.Slice(trending, func(i, j int) bool {
+		a, b := trending[i], trending[j]
+		switch {
+		case a.Net != b.Net:
+			return a.Net > b.Net
+		case a.Added != b.Added:
+			return a.Added > b.Added
+		case a.Asset.FavouriteCount != b.Asset.FavouriteCount:
+			return a.Asset.FavouriteCount > b.Asset.FavouriteCount
+		}
+		return bytes.Compare(a.Asset.ID[:], b.Asset.ID[:]) < 0
+	})
+	if len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending, nil
+}
+
+// favouriteAdded counts a new favourite towards its asset's popularity, at the favourite's
+// creation time
+func (r *MemoryRepository) favouriteAdded(fav *domain.Favourite) {
+	r.countFavourites(fav.AssetID, 1)
+	r.recordActivity(fav.AssetID, fav.CreatedAt, func(c *activityCounts) { c.added++ })
+}
+
+// favouriteRemoved counts a removed favourite against its asset's popularity, now
+func (r *MemoryRepository) favouriteRemoved(fav *domain.Favourite) {
+	r.countFavourites(fav.AssetID, -1)
+	r.recordActivity(fav.AssetID, time.Now(), func(c *activityCounts) { c.removed++ })
+}
+
+// countFavourites replaces an asset by a copy with its favourite count changed by delta, and
+// re-positions it in the popularity index. The asset's other indexes are unaffected.
+func (r *MemoryRepository) countFavourites(assetID uuid.UUID, delta int) {
+	asset := r.assets[assetID]
+	r.assetOrder["popularity"].remove(domain.AssetSortKey(asset, "popularity"), assetID)
+	counted := *asset
+	counted.FavouriteCount += delta
+	r.assets[assetID] = &counted
+	r.assetOrder["popularity"].insert(domain.AssetSortKey(&counted, "popularity"), assetID)
+}
+
+// recordActivity counts an event of an asset in the bucket of the given time. Events older than
+// domain.MaxTrendingWindow are not counted, and buckets falling out of it are dropped once per
+// bucket.
+func (r *MemoryRepository) recordActivity(assetID uuid.UUID, at time.Time, count func(*activityCounts)) {
+	now := bucketOf(time.Now())
+	oldest := bucketOf(time.Now().Add(-domain.MaxTrendingWindow))
+	if now != r.activityPruned {
+		for bucket := range r.activity {
+			if bucket < oldest {
+				delete(r.activity, bucket)
+			}
+		}
+		r.activityPruned = now
+	}
+
+	bucket := bucketOf(at)
+	if bucket < oldest {
+		return
+	}
+	if r.activity[bucket] == nil {
+		r.activity[bucket] = make(map[uuid.UUID]*activityCounts)
+	}
+	counts := r.activity[bucket][assetID]
+	if counts == nil {
+		counts = &activityCounts{}
+		r.activity[bucket][assetID] = counts
+	}
+	count(counts)
+}
+
+// forgetActivity drops the activity of a deleted asset
+func (r *MemoryRepository) forgetActivity(assetID uuid.UUID) {
+	for bucket, counts := range r.activity {
+		delete(counts, assetID)
+		if len(counts) == 0 {
+			delete(r.activity, bucket)
+		}
+	}
+}
+
+// bucketOf returns the activity bucket of a time, as the Unix time the bucket starts at
+func bucketOf(t time.Time) int64 {
+	return t.Truncate(domain.TrendingBucket).Unix()
+}
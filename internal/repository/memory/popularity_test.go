@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_FavouriteCounts(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	users := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	a := createTaggedAsset(t, repo, "a")
+	b := createTaggedAsset(t, repo, "b")
+	c := createTaggedAsset(t, repo, "c")
+	for _, userID := range users {
+		require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, b.ID)))
+	}
+	_, err := repo.AddFavourites(ctx, users[0], []uuid.UUID{a.ID, c.ID})
+	require.NoError(t, err)
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(users[1], c.ID)))
+
+	count := func(assetID uuid.UUID) int {
+		asset, err := repo.GetAsset(ctx, assetID)
+		require.NoError(t, err)
+		return asset.FavouriteCount
+	}
+	assert.Equal(t, []int{1, 3, 2}, []int{count(a.ID), count(b.ID), count(c.ID)})
+
+	popular := func() []string {
+		assets, _, err := repo.ListAssets(ctx, domain.NewPageQuery(10, 0, "popularity", "desc"))
+		require.NoError(t, err)
+		return descriptions(assets)
+	}
+	assert.Equal(t, []string{"b", "c", "a"}, popular())
+
+	// Removals count down, and updates keep the count
+	favs, _, err := repo.ListFavourites(ctx, users[2], domain.NewPageQuery(10, 0, "", ""))
+	require.NoError(t, err)
+	require.NoError(t, repo.RemoveFavourite(ctx, users[2], favs[0].ID))
+	_, err = repo.RemoveFavourites(ctx, users[1], []uuid.UUID{b.ID})
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateAssetDescription(ctx, b.ID, "b2"))
+	assert.Equal(t, 1, count(b.ID))
+	assert.Equal(t, "c", popular()[0])
+	assert.NoError(t, repo.Sanity(ctx))
+
+	// Favourites embed the counted asset; deleting an asset leaves consistent counts behind
+	favs, _, err = repo.ListFavourites(ctx, users[1], domain.NewPageQuery(10, 0, "", ""))
+	require.NoError(t, err)
+	require.Len(t, favs, 1)
+	assert.Equal(t, 2, favs[0].Asset.FavouriteCount)
+	_, err = repo.DeleteAsset(ctx, c.ID, domain.DeleteRestrict)
+	require.NoError(t, err)
+	assert.Len(t, popular(), 2)
+	assert.NoError(t, repo.Sanity(ctx))
+}
+
+func TestMemoryRepository_TrendingAssets(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	now := time.Now()
+
+	old := createTaggedAsset(t, repo, "old")
+	recent := createTaggedAsset(t, repo, "recent")
+	churned := createTaggedAsset(t, repo, "churned")
+	favourite := func(asset *domain.Asset, ago time.Duration) *domain.Favourite {
+		fav := domain.NewFavourite(uuid.New(), asset.ID)
+		fav.CreatedAt = now.Add(-ago)
+		require.NoError(t, repo.AddFavourite(ctx, fav))
+		return fav
+	}
+	for i := 0; i < 3; i++ {
+		favourite(old, 10*24*time.Hour)
+	}
+	favourite(old, 40*24*time.Hour) // beyond the kept activity
+	favourite(recent, 3*time.Hour)
+	favourite(recent, 2*24*time.Hour)
+	fav := favourite(churned, 3*time.Hour)
+	require.NoError(t, repo.RemoveFavourite(ctx, fav.UserID, fav.ID))
+
+	trending := func(window time.Duration, limit int) []string {
+		assets, err := repo.TrendingAssets(ctx, now.Add(-window), limit)
+		require.NoError(t, err)
+		out := make([]string, len(assets))
+		for i, asset := range assets {
+			out[i] = asset.Asset.Description
+		}
+		return out
+	}
+	assert.Equal(t, []string{"recent"}, trending(7*24*time.Hour, 10))
+	assert.Equal(t, []string{"old", "recent"}, trending(30*24*time.Hour, 10))
+	assert.Equal(t, []string{"old"}, trending(30*24*time.Hour, 1))
+	assert.Empty(t, trending(time.Hour, 10), "the window starts within the last two buckets")
+
+	assets, err := repo.TrendingAssets(ctx, now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, domain.TrendingAsset{Asset: assets[0].Asset, Added: 1, Removed: 0, Net: 1}, assets[0])
+	assert.Equal(t, 2, assets[0].Asset.FavouriteCount)
+
+	_, err = repo.DeleteAsset(ctx, recent.ID, domain.DeleteRestrict)
+	require.NoError(t, err)
+	assert.Empty(t, trending(7*24*time.Hour, 10))
+	assert.NoError(t, repo.Sanity(ctx))
+}
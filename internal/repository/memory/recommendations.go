@@ -0,0 +1,147 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// contribution is the part of a candidate's score coming from one of the user's favourites
+type contribution struct {
+	assetID uuid.UUID
+	score   float64
+}
+
+// Recommendations suggests assets to a user from the assets favourited together with the user's
+// favourites, as counted among the most recent favourites of each user (see maxPairedFavourites). Each favourite adds, for every asset co-favourited with it that the user has not
+// favourited, their co-favourite similarity weighted by the favourite's recency at the given
+// time and by whether both assets are of the same type. Favourites are visited in a fixed
+// order, so that equal data yields equal scores. Users without favourites get no suggestions.
+func (r *MemoryRepository) Recommendations(ctx context.Context, userID uuid.UUID, at time.Time, limit int) ([]domain.Recommendation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	owned := r.userAssets[userID]
+	sources := make([]uuid.UUID, 0, len(owned))
+	for assetID := range owned {
+		sources = append(sources, assetID)
+	}
+	sort.Slice(sources, func(i, j int) bool { return bytes.Compare(sources[i][:], sources[j][:]) < 0 })
+
+	candidates := make(map[uuid.UUID][]contribution)
+	for _, sourceID := range sources {
+		source := r.assets[sourceID]
+		recency := domain.RecencyWeight(r.favourites[userID][owned[sourceID]].CreatedAt, at)
+		for candidateID, together := range r.coFavourites[sourceID] {
+			if _, favourited := owned[candidateID]; favourited {
+				continue
+			}
+			candidate := r.assets[candidateID]
+			score := domain.CoFavouriteSimilarity(together, source.FavouriteCount, candidate.FavouriteCount) *
+				recency * domain.TypeWeight(source.Type, candidate.Type)
+			candidates[candidateID] = append(candidates[candidateID], contribution{assetID: sourceID, score: score})
+		}
+	}
+
+	recommendations := make([]domain.Recommendation, 0, len(candidates))
+	for candidateID, contributions := range candidates {
+		// contributions are in source order, which makes the sum deterministic
+		recommendation := domain.Recommendation{Asset: r.assets[candidateID]}
+		for _, c := range contributions {
+			recommendation.Score += c.score
+		}
+		sort.SliceStable(contributions, func(i, j int) bool { return contributions[i].score > contributions[j].score })
+		for _, c := range contributions[:min(len(contributions), domain.MaxRecommendationReasons)] {
+			recommendation.BasedOn = append(recommendation.BasedOn, c.assetID)
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		a, b := recommendations[i], recommendations[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return bytes.Compare(a.Asset.ID[:], b.Asset.ID[:]) < 0
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+// maxPairedFavourites bounds the favourites of a user paired in the co-favourite index: only the
+// most recent ones are, so that a user adds at most maxPairedFavourites² pairs, however many
+// favourites they have
+const maxPairedFavourites = 100
+
+// pairedAssets returns the assets of the user's favourites paired in the co-favourite index: those
+// of the maxPairedFavourites most recent favourites
+func (r *MemoryRepository) pairedAssets(userID uuid.UUID) []uuid.UUID {
+	orders := r.favouriteOrder[userID]
+	if orders == nil {
+		return nil
+	}
+	entries := orders["created_at"].entries
+	entries = entries[max(0, len(entries)-maxPairedFavourites):]
+	assets := make([]uuid.UUID, len(entries))
+	for i, e := range entries {
+		assets[i] = r.favourites[userID][e.id].AssetID
+	}
+	return assets
+}
+
+// repairFavourites updates the co-favourite index once the user's paired assets changed from
+// before: assets leaving stop being counted with the others, and assets joining start being
+// counted with them, in O(maxPairedFavourites) per asset
+func (r *MemoryRepository) repairFavourites(userID uuid.UUID, before []uuid.UUID) {
+	after := r.pairedAssets(userID)
+	paired := make(map[uuid.UUID]struct{}, len(after))
+	for _, assetID := range after {
+		paired[assetID] = struct{}{}
+	}
+
+	current := make(map[uuid.UUID]struct{}, len(before))
+	for _, assetID := range before {
+		current[assetID] = struct{}{}
+	}
+	for _, assetID := range before {
+		if _, ok := paired[assetID]; ok {
+			continue
+		}
+		delete(current, assetID)
+		for otherID := range current {
+			r.countPair(assetID, otherID, -1)
+			r.countPair(otherID, assetID, -1)
+		}
+	}
+	for _, assetID := range after {
+		if _, ok := current[assetID]; ok {
+			continue
+		}
+		for otherID := range current {
+			r.countPair(assetID, otherID, 1)
+			r.countPair(otherID, assetID, 1)
+		}
+		current[assetID] = struct{}{}
+	}
+}
+
+// countPair changes the number of users who favourited both assets, one way round
+func (r *MemoryRepository) countPair(assetID, otherID uuid.UUID, delta int) {
+	pairs := r.coFavourites[assetID]
+	if pairs == nil {
+		pairs = make(map[uuid.UUID]int)
+		r.coFavourites[assetID] = pairs
+	}
+	pairs[otherID] += delta
+	if pairs[otherID] <= 0 {
+		delete(pairs, otherID)
+		if len(pairs) == 0 {
+			delete(r.coFavourites, assetID)
+		}
+	}
+}
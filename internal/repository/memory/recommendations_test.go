@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// coFavouriteDataset is the offline dataset of testdata/cofavourites.json: assets and
+// favourites made some days before a fixed time, and the recommendations expected then
+type coFavouriteDataset struct {
+	At     time.Time `json:"at"`
+	Assets []struct {
+		Key         string           `json:"key"`
+		ID          uuid.UUID        `json:"id"`
+		Type        domain.AssetType `json:"type"`
+		Description string           `json:"description"`
+	} `json:"assets"`
+	Favourites []coFavourite `json:"favourites"`
+	Expected   map[string][]struct {
+		Asset   string   `json:"asset"`
+		BasedOn []string `json:"based_on"`
+	} `json:"expected"`
+}
+
+type coFavourite struct {
+	User    string `json:"user"`
+	Asset   string `json:"asset"`
+	DaysAgo int    `json:"days_ago"`
+}
+
+func loadCoFavouriteDataset(t *testing.T) *coFavouriteDataset {
+	t.Helper()
+
+	raw, err := os.ReadFile("testdata/cofavourites.json")
+	require.NoError(t, err)
+	var dataset coFavouriteDataset
+	require.NoError(t, json.Unmarshal(raw, &dataset))
+	return &dataset
+}
+
+// userID derives a stable user ID from a user name of the dataset
+func (d *coFavouriteDataset) userID(name string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(name))
+}
+
+// assetIDs maps the dataset's asset keys to asset IDs
+func (d *coFavouriteDataset) assetIDs() map[string]uuid.UUID {
+	ids := make(map[string]uuid.UUID, len(d.Assets))
+	for _, asset := range d.Assets {
+		ids[asset.Key] = asset.ID
+	}
+	return ids
+}
+
+// load creates the dataset's assets and the given favourites in a new repository
+func (d *coFavouriteDataset) load(t *testing.T, favourites []coFavourite) *MemoryRepository {
+	t.Helper()
+
+	repo := NewRepository()
+	ctx := context.Background()
+	for _, a := range d.Assets {
+		var data interface{} = domain.ChartData{Title: a.Description}
+		if a.Type == domain.AssetTypeInsight {
+			data = domain.InsightData{Text: a.Description}
+		}
+		asset, err := domain.NewAsset(a.Type, a.Description, data)
+		require.NoError(t, err)
+		asset.ID = a.ID
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+	}
+	ids := d.assetIDs()
+	for _, f := range favourites {
+		fav := domain.NewFavourite(d.userID(f.User), ids[f.Asset])
+		fav.CreatedAt = d.At.AddDate(0, 0, -f.DaysAgo)
+		require.NoError(t, repo.AddFavourite(ctx, fav))
+	}
+	return repo
+}
+
+func TestMemoryRepository_Recommendations(t *testing.T) {
+	dataset := loadCoFavouriteDataset(t)
+	repo := dataset.load(t, dataset.Favourites)
+	ctx := context.Background()
+	keys := make(map[uuid.UUID]string)
+	for key, id := range dataset.assetIDs() {
+		keys[id] = key
+	}
+
+	for user, expected := range dataset.Expected {
+		t.Run(user, func(t *testing.T) {
+			recommendations, err := repo.Recommendations(ctx, dataset.userID(user), dataset.At, 10)
+			require.NoError(t, err)
+			require.Len(t, recommendations, len(expected))
+			for i, want := range expected {
+				got := recommendations[i]
+				assert.Equal(t, want.Asset, keys[got.Asset.ID], "rank %d", i)
+				basedOn := make([]string, len(got.BasedOn))
+				for j, id := range got.BasedOn {
+					basedOn[j] = keys[id]
+				}
+				assert.Equal(t, want.BasedOn, basedOn, "rank %d", i)
+				assert.Positive(t, got.Score)
+			}
+		})
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		recommendations, err := repo.Recommendations(ctx, dataset.userID("returning"), dataset.At, 2)
+		require.NoError(t, err)
+		require.Len(t, recommendations, 2)
+		assert.Equal(t, "sales_region", keys[recommendations[0].Asset.ID])
+	})
+}
+
+// Recommendations kept up to date as favourites come and go match those computed from scratch
+func TestMemoryRepository_RecommendationsIncremental(t *testing.T) {
+	dataset := loadCoFavouriteDataset(t)
+	repo := dataset.load(t, dataset.Favourites)
+	ctx := context.Background()
+	ids := dataset.assetIDs()
+
+	// analyst1 drops sales_channel, analyst2 picks up costs, and sales_doubled is deleted
+	favs, _, err := repo.ListFavourites(ctx, dataset.userID("analyst1"), domain.NewPageQuery(10, 0, "", ""))
+	require.NoError(t, err)
+	for _, fav := range favs {
+		if fav.AssetID == ids["sales_channel"] {
+			require.NoError(t, repo.RemoveFavourite(ctx, fav.UserID, fav.ID))
+		}
+	}
+	added := coFavourite{User: "analyst2", Asset: "costs", DaysAgo: 2}
+	fav := domain.NewFavourite(dataset.userID(added.User), ids[added.Asset])
+	fav.CreatedAt = dataset.At.AddDate(0, 0, -added.DaysAgo)
+	require.NoError(t, repo.AddFavourite(ctx, fav))
+	_, err = repo.DeleteAsset(ctx, ids["sales_doubled"], domain.DeleteRestrict)
+	require.NoError(t, err)
+	require.NoError(t, repo.Sanity(ctx))
+
+	var remaining []coFavourite
+	for _, f := range dataset.Favourites {
+		if (f.User != "analyst1" || f.Asset != "sales_channel") && f.Asset != "sales_doubled" {
+			remaining = append(remaining, f)
+		}
+	}
+	rebuilt := dataset.load(t, append(remaining, added))
+	_, err = rebuilt.DeleteAsset(ctx, ids["sales_doubled"], domain.DeleteRestrict)
+	require.NoError(t, err)
+
+	type ranked struct {
+		AssetID uuid.UUID
+		Score   float64
+		BasedOn []uuid.UUID
+	}
+	rank := func(repo *MemoryRepository, user string) []ranked {
+		recommendations, err := repo.Recommendations(ctx, dataset.userID(user), dataset.At, 10)
+		require.NoError(t, err)
+		out := make([]ranked, len(recommendations))
+		for i, r := range recommendations {
+			out[i] = ranked{AssetID: r.Asset.ID, Score: r.Score, BasedOn: r.BasedOn}
+		}
+		return out
+	}
+	for _, user := range []string{"analyst1", "analyst2", "analyst3", "newcomer", "returning"} {
+		assert.Equal(t, rank(rebuilt, user), rank(repo, user), user)
+	}
+	assert.NotEmpty(t, rank(repo, "analyst2"))
+}
+
+// Only the most recent favourites of a user are paired, and older ones take the place of those removed
+func TestMemoryRepository_RecommendationsPairedFavourites(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var favs []*domain.Favourite
+	for i := 0; i < maxPairedFavourites+10; i++ {
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, "insight", domain.InsightData{Text: "insight"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+		fav := domain.NewFavourite(userID, asset.ID)
+		fav.CreatedAt = start.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, repo.AddFavourite(ctx, fav))
+		favs = append(favs, fav)
+	}
+	pairs := func() int {
+		n := 0
+		for _, others := range repo.coFavourites {
+			n += len(others)
+		}
+		return n
+	}
+	assert.Equal(t, maxPairedFavourites*(maxPairedFavourites-1), pairs())
+	assert.Empty(t, repo.coFavourites[favs[0].AssetID])
+	require.NoError(t, repo.Sanity(ctx))
+
+	require.NoError(t, repo.RemoveFavourite(ctx, userID, favs[len(favs)-1].ID))
+	assert.Equal(t, maxPairedFavourites*(maxPairedFavourites-1), pairs())
+	assert.Len(t, repo.coFavourites[favs[9].AssetID], maxPairedFavourites-1)
+	assert.Empty(t, repo.coFavourites[favs[len(favs)-1].AssetID])
+	require.NoError(t, repo.Sanity(ctx))
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/search"
+	"github.com/google/uuid"
+)
+
+// assetSearchFields returns an asset's searchable text: its description and tags, chart and axis
+// titles, insight text and source, and the countries of audiences. Titles and descriptions weigh
+// more than body text.
+func assetSearchFields(asset *domain.Asset) []search.Field {
+	fields := []search.Field{
+		{Text: asset.Description, Weight: 3},
+		{Text: strings.Join(asset.Tags, " "), Weight: 2},
+		{Text: string(asset.Type), Weight: 0.5},
+	}
+
+	switch asset.Type {
+	case domain.AssetTypeChart:
+		if chart, err := domain.ParseChartData(asset.Data); err == nil {
+			fields = append(fields,
+				search.Field{Text: chart.Title, Weight: 3},
+				search.Field{Text: chart.AxisXTitle + " " + chart.AxisYTitle, Weight: 1},
+			)
+		}
+	case domain.AssetTypeInsight:
+		if insight, err := domain.ParseInsightData(asset.Data); err == nil {
+			fields = append(fields, search.Field{Text: insight.Text + " " + insight.Source, Weight: 1})
+		}
+	case domain.AssetTypeAudience:
+		if audience, err := domain.ParseAudienceData(asset.Data); err == nil {
+			var countries []string
+			for _, country := range audience.Countries() {
+				countries = append(countries, string(country), country.Alpha3())
+			}
+			fields = append(fields, search.Field{Text: strings.Join(countries, " "), Weight: 1})
+		}
+	}
+	return fields
+}
+
+// indexNotes indexes the annotations of a favourite in its user's notes index
+func (r *MemoryRepository) indexNotes(fav *domain.Favourite) {
+	notes := r.notesIndexes[fav.UserID]
+	if notes == nil {
+		notes = search.NewIndex()
+		r.notesIndexes[fav.UserID] = notes
+	}
+	notes.Put(fav.ID, favouriteSearchFields(fav))
+}
+
+// unindexNotes removes the annotations of a favourite from its user's notes index, dropping the
+// index once empty
+func (r *MemoryRepository) unindexNotes(fav *domain.Favourite) {
+	notes := r.notesIndexes[fav.UserID]
+	if notes == nil {
+		return
+	}
+	notes.Remove(fav.ID)
+	if notes.Len() == 0 {
+		delete(r.notesIndexes, fav.UserID)
+	}
+}
+
+// favouriteSearchFields returns the annotations of a favourite for full-text indexing. They are
+// searched together with the favourited asset's fields (see assetSearchFields).
+func favouriteSearchFields(fav *domain.Favourite) []search.Field {
+	return []search.Field{
+		{Text: fav.Title, Weight: 3},
+		{Text: fav.Note, Weight: 1},
+	}
+}
+
+// SearchAssets returns the assets matching a full-text query, most relevant first
+func (r *MemoryRepository) SearchAssets(ctx context.Context, text string, query *domain.PageQuery) ([]*domain.Asset, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	scores := r.searchIndex.Search(text)
+	r.applyTagFilter(scores, query)
+
+	assets := make([]*domain.Asset, 0, len(scores))
+	for assetID := range scores {
+		assets = append(assets, r.assets[assetID])
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		return byRelevance(scores[assets[i].ID], scores[assets[j].ID], assets[i], assets[j])
+	})
+
+	total := len(assets)
+	start, end := pageBounds(query, total)
+	return assets[start:end], total, nil
+}
+
+// SearchFavourites returns the user's favourites matching a full-text query, most relevant first.
+// Each query token must match the favourite's asset or its annotations; a token's score adds up
+// over both.
+func (r *MemoryRepository) SearchFavourites(ctx context.Context, userID uuid.UUID, text string, query *domain.PageQuery) ([]*domain.Favourite, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	userFavs := r.favourites[userID]
+	if len(userFavs) == 0 {
+		return []*domain.Favourite{}, 0, nil
+	}
+
+	assetTokens := r.searchIndex.TokenScores(text)
+	noteTokens := make([]map[uuid.UUID]float64, len(assetTokens))
+	if notes := r.notesIndexes[userID]; notes != nil {
+		noteTokens = notes.TokenScores(text)
+	}
+	var scores map[uuid.UUID]float64 // favouriteID -> score
+	for i := range assetTokens {
+		tokenScores := make(map[uuid.UUID]float64)
+		for assetID, score := range assetTokens[i] {
+			if favID, ok := r.userAssets[userID][assetID]; ok {
+				tokenScores[favID] += score
+			}
+		}
+		for favID, score := range noteTokens[i] {
+			tokenScores[favID] += score
+		}
+		if scores == nil {
+			scores = tokenScores
+			continue
+		}
+		for favID, score := range scores {
+			if tokenScore, ok := tokenScores[favID]; ok {
+				scores[favID] = score + tokenScore
+			} else {
+				delete(scores, favID)
+			}
+		}
+	}
+
+	var tagged map[uuid.UUID]struct{}
+	if len(query.Tags) > 0 {
+		tagged = r.taggedAssets(query.Tags, query.TagMatch)
+	}
+	favs := make([]*domain.Favourite, 0, len(scores))
+	for favID := range scores {
+		fav := userFavs[favID]
+		if tagged != nil {
+			if _, ok := tagged[fav.AssetID]; !ok {
+				continue
+			}
+		}
+		favs = append(favs, r.favouriteView(fav, r.assets[fav.AssetID]))
+	}
+	sort.Slice(favs, func(i, j int) bool {
+		return byRelevance(scores[favs[i].ID], scores[favs[j].ID], favs[i].Asset, favs[j].Asset)
+	})
+
+	total := len(favs)
+	start, end := pageBounds(query, total)
+	return favs[start:end], total, nil
+}
+
+// applyTagFilter drops the search hits that do not satisfy the query's tag filter
+func (r *MemoryRepository) applyTagFilter(scores map[uuid.UUID]float64, query *domain.PageQuery) {
+	if len(query.Tags) == 0 {
+		return
+	}
+	tagged := r.taggedAssets(query.Tags, query.TagMatch)
+	for assetID := range scores {
+		if _, ok := tagged[assetID]; !ok {
+			delete(scores, assetID)
+		}
+	}
+}
+
+// byRelevance orders by descending score, then newest asset first, with the asset ID as a stable tie-breaker
+func byRelevance(scoreA, scoreB float64, a, b *domain.Asset) bool {
+	if scoreA != scoreB {
+		return scoreA > scoreB
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.After(b.CreatedAt)
+	}
+	return a.ID.String() < b.ID.String()
+}
+
+// pageBounds returns the slice bounds of the query's page within total items
+func pageBounds(query *domain.PageQuery, total int) (int, int) {
+	start := min(query.Offset, total)
+	end := min(query.Offset+query.Limit, total)
+	return start, end
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_Search(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+	query := domain.NewPageQuery(10, 0, "", "")
+
+	chart, err := domain.NewAsset(domain.AssetTypeChart, "Quarterly numbers", domain.ChartData{
+		Title: "Q4 Sales", AxisXTitle: "Month", AxisYTitle: "Revenue",
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, chart))
+
+	insight := createTaggedAsset(t, repo, "Sales grew in Q4 thanks to social media", "marketing")
+
+	audience, err := domain.NewAsset(domain.AssetTypeAudience, "Young Brits", domain.AudienceData{
+		Any: []domain.AudienceData{{BirthCountry: "GB"}, {BirthCountry: "IE"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, audience))
+
+	search := func(text string) []string {
+		assets, total, err := repo.SearchAssets(ctx, text, query)
+		require.NoError(t, err)
+		assert.Equal(t, len(assets), total)
+		return descriptions(assets)
+	}
+
+	assert.Equal(t, []string{"Quarterly numbers", "Sales grew in Q4 thanks to social media"}, search("q4 sales"))
+	assert.Equal(t, []string{"Quarterly numbers"}, search("revenue"))
+	assert.Equal(t, []string{"Young Brits"}, search("gbr"))
+	assert.Equal(t, []string{"Young Brits"}, search("ie"))
+	assert.Equal(t, []string{"Sales grew in Q4 thanks to social media"}, search("market"))
+
+	// The index follows every mutation
+	require.NoError(t, repo.UpdateAssetDescription(ctx, chart.ID, "Holiday season"))
+	assert.Equal(t, []string{"Holiday season"}, search("holiday"))
+	assert.Empty(t, search("quarterly"))
+
+	_, err = repo.AddAssetTags(ctx, audience.ID, []string{"ireland"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Young Brits"}, search("ireland"))
+
+	_, err = repo.DeleteAsset(ctx, insight.ID, domain.DeleteRestrict)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Holiday season"}, search("sales"))
+
+	// Favourites search only sees the user's favourites
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, audience.ID)))
+	favs, total, err := repo.SearchFavourites(ctx, userID, "young", query)
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, audience.ID, favs[0].Asset.ID)
+
+	_, total, err = repo.SearchFavourites(ctx, userID, "holiday", query)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestMemoryRepository_SearchNotesPerUser(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+	query := domain.NewPageQuery(10, 0, "", "")
+
+	annotate := func(userID uuid.UUID, note string) *domain.Favourite {
+		asset := createTaggedAsset(t, repo, "Unrelated", "misc")
+		fav := domain.NewFavourite(userID, asset.ID)
+		require.NoError(t, repo.AddFavourite(ctx, fav))
+		_, err := repo.UpdateFavourite(ctx, userID, fav.ID, domain.FavouriteUpdate{Note: &note})
+		require.NoError(t, err)
+		return fav
+	}
+	ranking := func() []uuid.UUID {
+		favs, _, err := repo.SearchFavourites(ctx, userID, "red blue", query)
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, len(favs))
+		for i, fav := range favs {
+			ids[i] = fav.ID
+		}
+		return ids
+	}
+
+	// Among the user's notes, "blue" is the commoner term, so "red" weighs more
+	redder := annotate(userID, "red red blue")
+	bluer := annotate(userID, "red blue blue")
+	annotate(userID, "blue")
+	assert.Equal(t, []uuid.UUID{redder.ID, bluer.ID}, ranking())
+
+	// Other users' notes neither shift the ranking nor show up
+	for i := 0; i < 5; i++ {
+		annotate(otherID, "red")
+	}
+	assert.Equal(t, []uuid.UUID{redder.ID, bluer.ID}, ranking())
+
+	// Indexes go with the user's last annotated favourite
+	require.Contains(t, repo.notesIndexes, otherID)
+	for _, fav := range repo.favourites[otherID] {
+		require.NoError(t, repo.RemoveFavourite(ctx, otherID, fav.ID))
+	}
+	assert.NotContains(t, repo.notesIndexes, otherID)
+}
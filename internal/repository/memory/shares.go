@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CreateShare stores a new share of its owner's favourites or of one of the owner's collections
+func (r *MemoryRepository) CreateShare(ctx context.Context, share *domain.Share) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if share.CollectionID != nil {
+		if _, exists := r.collections[share.OwnerID][*share.CollectionID]; !exists {
+			return domain.ErrNotFound
+		}
+	}
+	if _, exists := r.shares[share.ID]; exists {
+		return domain.ErrAlreadyExists
+	}
+
+	stored := *share
+	stored.UserIDs = slices.Clone(share.UserIDs)
+	r.shares[share.ID] = &stored
+	addToSet(r.ownerShares, share.OwnerID, share.ID)
+	for _, userID := range stored.UserIDs {
+		addToSet(r.sharedWith, userID, share.ID)
+	}
+	if stored.Token != "" {
+		r.shareTokens[stored.Token] = share.ID
+	}
+	r.audit(ctx, domain.AuditShareCreate, shareTargets(&stored), nil, auditedShare(&stored))
+	return nil
+}
+
+// GetShare retrieves a share by ID
+func (r *MemoryRepository) GetShare(ctx context.Context, shareID uuid.UUID) (*domain.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	share, exists := r.shares[shareID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return shareView(share), nil
+}
+
+// ListShares returns the shares a user created, oldest first
+func (r *MemoryRepository) ListShares(ctx context.Context, ownerID uuid.UUID) ([]*domain.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.sharesOf(r.ownerShares[ownerID]), nil
+}
+
+// ListSharedWith returns the shares granted to a user, oldest first
+func (r *MemoryRepository) ListSharedWith(ctx context.Context, userID uuid.UUID) ([]*domain.Share, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.sharesOf(r.sharedWith[userID]), nil
+}
+
+// RedeemShareLink adds a user to the recipients of the share with the given link token. Redeeming
+// a link again, or one's own link, changes nothing.
+func (r *MemoryRepository) RedeemShareLink(ctx context.Context, token string, userID uuid.UUID) (*domain.Share, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shareID, exists := r.shareTokens[token]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	share := r.shares[shareID]
+	if share.OwnerID == userID || share.GrantedTo(userID) {
+		return shareView(share), nil
+	}
+
+	redeemed := *share
+	redeemed.UserIDs = append(slices.Clone(share.UserIDs), userID)
+	r.shares[shareID] = &redeemed
+	addToSet(r.sharedWith, userID, shareID)
+	r.audit(ctx, domain.AuditShareRedeem, append(shareTargets(share), target("user", userID)), auditedShare(share), auditedShare(&redeemed))
+	return shareView(&redeemed), nil
+}
+
+// DeleteShare revokes one of a user's shares, for its recipients and its link alike
+func (r *MemoryRepository) DeleteShare(ctx context.Context, ownerID, shareID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	share, exists := r.shares[shareID]
+	if !exists || share.OwnerID != ownerID {
+		return domain.ErrNotFound
+	}
+	r.dropShare(share)
+	r.audit(ctx, domain.AuditShareRevoke, shareTargets(share), auditedShare(share), nil)
+	return nil
+}
+
+// dropShare removes a share and its entries in the owner, recipient and token indexes
+func (r *MemoryRepository) dropShare(share *domain.Share) {
+	delete(r.shares, share.ID)
+	removeFromSet(r.ownerShares, share.OwnerID, share.ID)
+	for _, userID := range share.UserIDs {
+		removeFromSet(r.sharedWith, userID, share.ID)
+	}
+	if share.Token != "" {
+		delete(r.shareTokens, share.Token)
+	}
+}
+
+// shareTargets identifies a share, its owner and its collection, if any, in audit entries
+func shareTargets(share *domain.Share) []domain.AuditTarget {
+	targets := []domain.AuditTarget{target("user", share.OwnerID), target("share", share.ID)}
+	if share.CollectionID != nil {
+		targets = append(targets, target("collection", *share.CollectionID))
+	}
+	return targets
+}
+
+// sharesOf returns copies of the given shares, oldest first
+func (r *MemoryRepository) sharesOf(shareIDs map[uuid.UUID]struct{}) []*domain.Share {
+	shares := make([]*domain.Share, 0, len(shareIDs))
+	for shareID := range shareIDs {
+		shares = append(shares, shareView(r.shares[shareID]))
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		if !shares[i].CreatedAt.Equal(shares[j].CreatedAt) {
+			return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+		}
+		return shares[i].ID.String() < shares[j].ID.String()
+	})
+	return shares
+}
+
+// shareView returns a copy of a stored share that callers may modify
+func shareView(share *domain.Share) *domain.Share {
+	view := *share
+	view.UserIDs = slices.Clone(share.UserIDs)
+	return &view
+}
+
+// addToSet adds an ID to the set under key, creating the set if needed
+func addToSet(sets map[uuid.UUID]map[uuid.UUID]struct{}, key, id uuid.UUID) {
+	if sets[key] == nil {
+		sets[key] = make(map[uuid.UUID]struct{})
+	}
+	sets[key][id] = struct{}{}
+}
+
+// removeFromSet removes an ID from the set under key, dropping the set once empty
+func removeFromSet(sets map[uuid.UUID]map[uuid.UUID]struct{}, key, id uuid.UUID) {
+	delete(sets[key], id)
+	if len(sets[key]) == 0 {
+		delete(sets, key)
+	}
+}
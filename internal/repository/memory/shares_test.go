@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_Shares(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	ownerID, userID, otherUserID := uuid.New(), uuid.New(), uuid.New()
+	review := createCollection(t, repo, ownerID, "Q4 review")
+
+	direct, err := domain.NewShare(ownerID, nil, []uuid.UUID{userID}, false, domain.SharePermissionEdit)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateShare(ctx, direct))
+	link, err := domain.NewShare(ownerID, &review.ID, nil, true, domain.SharePermissionRead)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateShare(ctx, link))
+
+	missing := uuid.New()
+	orphan, err := domain.NewShare(ownerID, &missing, []uuid.UUID{userID}, false, "")
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.CreateShare(ctx, orphan), domain.ErrNotFound)
+	foreign, err := domain.NewShare(otherUserID, &review.ID, []uuid.UUID{userID}, false, "")
+	require.NoError(t, err)
+	assert.ErrorIs(t, repo.CreateShare(ctx, foreign), domain.ErrNotFound, "collections are shared by their owner only")
+
+	shareIDs := func(shares []*domain.Share, err error) []uuid.UUID {
+		require.NoError(t, err)
+		ids := make([]uuid.UUID, len(shares))
+		for i, share := range shares {
+			ids[i] = share.ID
+		}
+		return ids
+	}
+	assert.ElementsMatch(t, []uuid.UUID{direct.ID, link.ID}, shareIDs(repo.ListShares(ctx, ownerID)))
+	assert.Equal(t, []uuid.UUID{direct.ID}, shareIDs(repo.ListSharedWith(ctx, userID)))
+	assert.Empty(t, shareIDs(repo.ListSharedWith(ctx, otherUserID)))
+
+	t.Run("redeem link", func(t *testing.T) {
+		_, err := repo.RedeemShareLink(ctx, "unknown", otherUserID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		redeemed, err := repo.RedeemShareLink(ctx, link.Token, otherUserID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{otherUserID}, redeemed.UserIDs)
+		_, err = repo.RedeemShareLink(ctx, link.Token, otherUserID)
+		require.NoError(t, err)
+		_, err = repo.RedeemShareLink(ctx, link.Token, ownerID)
+		require.NoError(t, err)
+
+		stored, err := repo.GetShare(ctx, link.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{otherUserID}, stored.UserIDs)
+		assert.Equal(t, []uuid.UUID{link.ID}, shareIDs(repo.ListSharedWith(ctx, otherUserID)))
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		assert.ErrorIs(t, repo.DeleteShare(ctx, userID, direct.ID), domain.ErrNotFound, "only the owner revokes")
+		require.NoError(t, repo.DeleteShare(ctx, ownerID, direct.ID))
+		_, err := repo.GetShare(ctx, direct.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Empty(t, shareIDs(repo.ListSharedWith(ctx, userID)))
+	})
+
+	t.Run("deleting the collection revokes its shares", func(t *testing.T) {
+		require.NoError(t, repo.DeleteCollection(ctx, ownerID, review.ID))
+		_, err := repo.GetShare(ctx, link.ID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = repo.RedeemShareLink(ctx, link.Token, userID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Empty(t, shareIDs(repo.ListShares(ctx, ownerID)))
+		assert.Empty(t, shareIDs(repo.ListSharedWith(ctx, otherUserID)))
+		assert.NoError(t, repo.Sanity(ctx))
+	})
+}
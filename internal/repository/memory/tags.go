@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// AddAssetTags adds tags to an asset, ignoring tags it already carries
+func (r *MemoryRepository) AddAssetTags(ctx context.Context, assetID uuid.UUID, tags []string) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset, exists := r.assets[assetID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	updated := *asset
+	if err := updated.SetTags(append(append([]string{}, asset.Tags...), tags...)); err != nil {
+		return nil, err
+	}
+	updated.UpdatedAt = time.Now()
+	r.putAsset(asset, &updated)
+	r.recordAsset(domain.EventAssetUpdated, assetID, &updated)
+	r.audit(ctx, domain.AuditAssetTag, []domain.AuditTarget{target("asset", assetID)}, asset, &updated)
+	return &updated, nil
+}
+
+// RemoveAssetTags removes tags from an asset, ignoring tags it does not carry
+func (r *MemoryRepository) RemoveAssetTags(ctx context.Context, assetID uuid.UUID, tags []string) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset, exists := r.assets[assetID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	removed := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		removed[tag] = true
+	}
+	remaining := make([]string, 0, len(asset.Tags))
+	for _, tag := range asset.Tags {
+		if !removed[tag] {
+			remaining = append(remaining, tag)
+		}
+	}
+
+	updated := *asset
+	if err := updated.SetTags(remaining); err != nil {
+		return nil, fmt.Errorf("%w: stored tags of asset %s are invalid: %v", domain.ErrDataIntegrity, assetID, err)
+	}
+	updated.UpdatedAt = time.Now()
+	r.putAsset(asset, &updated)
+	r.recordAsset(domain.EventAssetUpdated, assetID, &updated)
+	r.audit(ctx, domain.AuditAssetUntag, []domain.AuditTarget{target("asset", assetID)}, asset, &updated)
+	return &updated, nil
+}
+
+// ListTags returns every tag in use with its number of assets, most used first
+func (r *MemoryRepository) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := make([]domain.TagCount, 0, len(r.tagIndex))
+	for tag, assetIDs := range r.tagIndex {
+		counts = append(counts, domain.TagCount{Tag: tag, Count: len(assetIDs)})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Tag < counts[j].Tag
+	})
+	return counts, nil
+}
+
+func (r *MemoryRepository) indexTags(assetID uuid.UUID, tags []string) {
+	for _, tag := range tags {
+		if r.tagIndex[tag] == nil {
+			r.tagIndex[tag] = make(map[uuid.UUID]struct{})
+		}
+		r.tagIndex[tag][assetID] = struct{}{}
+	}
+}
+
+func (r *MemoryRepository) unindexTags(assetID uuid.UUID, tags []string) {
+	for _, tag := range tags {
+		if assetIDs := r.tagIndex[tag]; assetIDs != nil {
+			delete(assetIDs, assetID)
+			if len(assetIDs) == 0 {
+				delete(r.tagIndex, tag)
+			}
+		}
+	}
+}
+
+// taggedAssets returns the IDs of the assets satisfying a tag filter. AND filters intersect the
+// index entries starting from the rarest tag, so the cost is bounded by the smallest entry.
+func (r *MemoryRepository) taggedAssets(tags []string, match domain.TagMatch) map[uuid.UUID]struct{} {
+	if match == domain.TagMatchAny {
+		result := make(map[uuid.UUID]struct{})
+		for _, tag := range tags {
+			for assetID := range r.tagIndex[tag] {
+				result[assetID] = struct{}{}
+			}
+		}
+		return result
+	}
+
+	sets := make([]map[uuid.UUID]struct{}, len(tags))
+	for i, tag := range tags {
+		sets[i] = r.tagIndex[tag]
+		if len(sets[i]) == 0 {
+			return map[uuid.UUID]struct{}{}
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool { return len(sets[i]) < len(sets[j]) })
+
+	result := make(map[uuid.UUID]struct{}, len(sets[0]))
+next:
+	for assetID := range sets[0] {
+		for _, set := range sets[1:] {
+			if _, ok := set[assetID]; !ok {
+				continue next
+			}
+		}
+		result[assetID] = struct{}{}
+	}
+	return result
+}
+
+// taggedFavourites returns the IDs of the user's favourites satisfying the query's tag filter.
+// Depending on which side is smaller, it either looks the tagged assets up in the user's
+// (assetID -> favourite) index or checks the user's favourites against the tagged assets.
+func (r *MemoryRepository) taggedFavourites(userID uuid.UUID, query *domain.PageQuery) map[uuid.UUID]struct{} {
+	userFavs := r.favourites[userID]
+	candidates := r.taggedAssets(query.Tags, query.TagMatch)
+	selected := make(map[uuid.UUID]struct{}, min(len(candidates), len(userFavs)))
+	if len(candidates) < len(userFavs) {
+		for assetID := range candidates {
+			if favID, ok := r.userAssets[userID][assetID]; ok {
+				selected[favID] = struct{}{}
+			}
+		}
+		return selected
+	}
+	for favID, fav := range userFavs {
+		if _, ok := candidates[fav.AssetID]; ok {
+			selected[favID] = struct{}{}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTaggedAsset(t *testing.T, repo *MemoryRepository, description string, tags ...string) *domain.Asset {
+	t.Helper()
+
+	asset, err := domain.NewAsset(domain.AssetTypeInsight, description, domain.InsightData{Text: description})
+	require.NoError(t, err)
+	require.NoError(t, asset.SetTags(tags))
+	require.NoError(t, repo.CreateAsset(context.Background(), asset))
+	return asset
+}
+
+func descriptions(assets []*domain.Asset) []string {
+	out := make([]string, len(assets))
+	for i, asset := range assets {
+		out[i] = asset.Description
+	}
+	return out
+}
+
+func TestMemoryRepository_TagFilter(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	a := createTaggedAsset(t, repo, "a", "sports", "gen-z")
+	b := createTaggedAsset(t, repo, "b", "sports")
+	c := createTaggedAsset(t, repo, "c", "gen-z", "tv")
+	createTaggedAsset(t, repo, "d")
+
+	for _, asset := range []*domain.Asset{a, b, c} {
+		require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, asset.ID)))
+	}
+
+	tests := []struct {
+		name  string
+		tags  []string
+		match string
+		want  []string
+	}{
+		{name: "single tag", tags: []string{"sports"}, want: []string{"a", "b"}},
+		{name: "all tags", tags: []string{"sports", "gen-z"}, want: []string{"a"}},
+		{name: "any tag", tags: []string{"sports", "tv"}, match: "any", want: []string{"a", "b", "c"}},
+		{name: "unknown tag", tags: []string{"sports", "music"}, want: []string{}},
+		{name: "no filter", want: []string{"a", "b", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := domain.NewPageQuery(10, 0, "description", "asc")
+			require.NoError(t, query.SetTagFilter(tt.tags, tt.match))
+
+			assets, total, err := repo.ListAssets(ctx, query)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.want), total)
+			assert.Equal(t, tt.want, descriptions(assets))
+
+			// Favourites see the same filter; asset "d" is not favourited
+			favs, total, err := repo.ListFavourites(ctx, userID, query)
+			require.NoError(t, err)
+			favDescriptions := []string{}
+			for _, fav := range favs {
+				favDescriptions = append(favDescriptions, fav.Asset.Description)
+			}
+			want := []string{}
+			for _, d := range tt.want {
+				if d != "d" {
+					want = append(want, d)
+				}
+			}
+			assert.Equal(t, len(want), total)
+			assert.Equal(t, want, favDescriptions)
+		})
+	}
+}
+
+func TestMemoryRepository_AssetTags(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+
+	a := createTaggedAsset(t, repo, "a", "sports")
+	b := createTaggedAsset(t, repo, "b", "sports", "tv")
+
+	updated, err := repo.AddAssetTags(ctx, a.ID, []string{"gen-z", "sports"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gen-z", "sports"}, updated.Tags)
+	assert.Equal(t, []string{"sports"}, a.Tags, "previously returned versions are not mutated")
+
+	counts, err := repo.ListTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.TagCount{{Tag: "sports", Count: 2}, {Tag: "gen-z", Count: 1}, {Tag: "tv", Count: 1}}, counts)
+
+	updated, err = repo.RemoveAssetTags(ctx, b.ID, []string{"tv", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sports"}, updated.Tags)
+
+	_, err = repo.DeleteAsset(ctx, a.ID, domain.DeleteRestrict)
+	require.NoError(t, err)
+
+	counts, err = repo.ListTags(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.TagCount{{Tag: "sports", Count: 1}}, counts)
+
+	_, err = repo.AddAssetTags(ctx, uuid.New(), []string{"sports"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
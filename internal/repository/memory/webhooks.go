@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CreateWebhook stores a new webhook
+func (r *MemoryRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.webhooks[webhook.ID]; exists {
+		return domain.ErrAlreadyExists
+	}
+	stored := *webhook
+	stored.EventTypes = slices.Clone(webhook.EventTypes)
+	r.webhooks[webhook.ID] = &stored
+	r.audit(ctx, domain.AuditWebhookCreate, []domain.AuditTarget{target("webhook", webhook.ID)}, nil, &stored)
+	return nil
+}
+
+// GetWebhook retrieves a webhook by ID
+func (r *MemoryRepository) GetWebhook(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhook, exists := r.webhooks[webhookID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return webhook, nil
+}
+
+// ListWebhooks returns all webhooks, oldest first
+func (r *MemoryRepository) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	webhooks := make([]*domain.Webhook, 0, len(r.webhooks))
+	for _, webhook := range r.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		a, b := webhooks[i], webhooks[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return bytes.Compare(a.ID[:], b.ID[:]) < 0
+	})
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook with its deliveries
+func (r *MemoryRepository) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	webhook, exists := r.webhooks[webhookID]
+	if !exists {
+		return domain.ErrNotFound
+	}
+	r.audit(ctx, domain.AuditWebhookDelete, []domain.AuditTarget{target("webhook", webhookID)}, webhook, nil)
+	delete(r.webhooks, webhookID)
+	delete(r.deliveries, webhookID)
+	delete(r.deliveryLog, webhookID)
+	return nil
+}
+
+// SaveDelivery stores a new delivery of its webhook, or replaces a stored one, then drops the
+// oldest succeeded deliveries beyond domain.MaxWebhookHistory
+func (r *MemoryRepository) SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.webhooks[delivery.WebhookID]; !exists {
+		return domain.ErrNotFound
+	}
+	deliveries := r.deliveries[delivery.WebhookID]
+	if deliveries == nil {
+		deliveries = make(map[uuid.UUID]*domain.WebhookDelivery)
+		r.deliveries[delivery.WebhookID] = deliveries
+	}
+	previous, exists := deliveries[delivery.ID]
+	if !exists {
+		r.deliveryLog[delivery.WebhookID] = append(r.deliveryLog[delivery.WebhookID], delivery.ID)
+	}
+	stored := *delivery
+	stored.Attempts = slices.Clone(delivery.Attempts)
+	deliveries[delivery.ID] = &stored
+
+	// Attempts are the dispatcher's own doing; only redeliveries, asked for, are audited
+	if exists && stored.Redeliveries > previous.Redeliveries {
+		r.audit(ctx, domain.AuditWebhookRedeliver, []domain.AuditTarget{target("webhook", delivery.WebhookID), target("delivery", delivery.ID)}, previous, &stored)
+	}
+
+	r.pruneDeliveries(delivery.WebhookID)
+	return nil
+}
+
+// pruneDeliveries drops a webhook's oldest succeeded deliveries beyond domain.MaxWebhookHistory
+func (r *MemoryRepository) pruneDeliveries(webhookID uuid.UUID) {
+	deliveries := r.deliveries[webhookID]
+	succeeded := 0
+	for _, delivery := range deliveries {
+		if delivery.Status == domain.DeliverySucceeded {
+			succeeded++
+		}
+	}
+	if succeeded <= domain.MaxWebhookHistory {
+		return
+	}
+
+	log := r.deliveryLog[webhookID]
+	kept := log[:0]
+	for _, deliveryID := range log {
+		if succeeded > domain.MaxWebhookHistory && deliveries[deliveryID].Status == domain.DeliverySucceeded {
+			delete(deliveries, deliveryID)
+			succeeded--
+			continue
+		}
+		kept = append(kept, deliveryID)
+	}
+	r.deliveryLog[webhookID] = kept
+}
+
+// GetDelivery retrieves a delivery of a webhook by ID
+func (r *MemoryRepository) GetDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	delivery, exists := r.deliveries[webhookID][deliveryID]
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+	return delivery, nil
+}
+
+// ListDeliveries returns a webhook's deliveries with the given status, or all of them when empty,
+// newest first
+func (r *MemoryRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, exists := r.webhooks[webhookID]; !exists {
+		return nil, domain.ErrNotFound
+	}
+	log := r.deliveryLog[webhookID]
+	deliveries := make([]*domain.WebhookDelivery, 0, len(log))
+	for i := len(log) - 1; i >= 0; i-- {
+		delivery := r.deliveries[webhookID][log[i]]
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
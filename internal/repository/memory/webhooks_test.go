@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createWebhook(t *testing.T, repo *MemoryRepository) *domain.Webhook {
+	t.Helper()
+
+	webhook, err := domain.NewWebhook("https://example.com/hooks", []domain.EventType{domain.EventAssetCreated}, "a-webhook-secret-for-tests")
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateWebhook(context.Background(), webhook))
+	return webhook
+}
+
+func TestMemoryRepository_Webhooks(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+
+	first := createWebhook(t, repo)
+	second := createWebhook(t, repo)
+	assert.ErrorIs(t, repo.CreateWebhook(ctx, first), domain.ErrAlreadyExists)
+	webhooks, err := repo.ListWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{first.ID, second.ID}, []uuid.UUID{webhooks[0].ID, webhooks[1].ID})
+
+	delivery := domain.NewWebhookDelivery(first.ID, domain.Event{ID: 1, Type: domain.EventAssetCreated})
+	require.NoError(t, repo.SaveDelivery(ctx, delivery))
+	dead := delivery.WithAttempt(domain.DeliveryAttempt{At: time.Now(), StatusCode: 500}, domain.DeliveryDead, nil)
+	require.NoError(t, repo.SaveDelivery(ctx, dead))
+	other := domain.NewWebhookDelivery(first.ID, domain.Event{ID: 2, Type: domain.EventAssetCreated})
+	require.NoError(t, repo.SaveDelivery(ctx, other))
+	assert.ErrorIs(t, repo.SaveDelivery(ctx, domain.NewWebhookDelivery(uuid.New(), domain.Event{})), domain.ErrNotFound)
+
+	stored, err := repo.GetDelivery(ctx, first.ID, delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryDead, stored.Status)
+	_, err = repo.GetDelivery(ctx, second.ID, delivery.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	all, err := repo.ListDeliveries(ctx, first.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{other.ID, delivery.ID}, []uuid.UUID{all[0].ID, all[1].ID}, "newest first")
+	deadLetters, err := repo.ListDeliveries(ctx, first.ID, domain.DeliveryDead)
+	require.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+	require.NoError(t, repo.Sanity(ctx))
+
+	require.NoError(t, repo.DeleteWebhook(ctx, first.ID))
+	_, err = repo.ListDeliveries(ctx, first.ID, "")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, repo.DeleteWebhook(ctx, first.ID), domain.ErrNotFound)
+	assert.NoError(t, repo.Sanity(ctx))
+}
+
+func TestMemoryRepository_DeliveryHistory(t *testing.T) {
+	repo := NewRepository()
+	ctx := context.Background()
+	webhook := createWebhook(t, repo)
+
+	// Dead letters and pending deliveries outlive the history of succeeded ones
+	var lastEventID uint64
+	deliver := func(status domain.DeliveryStatus) *domain.WebhookDelivery {
+		lastEventID++
+		delivery := domain.NewWebhookDelivery(webhook.ID, domain.Event{ID: lastEventID, Type: domain.EventAssetCreated})
+		if status != domain.DeliveryPending {
+			delivery = delivery.WithAttempt(domain.DeliveryAttempt{At: time.Now()}, status, nil)
+		}
+		require.NoError(t, repo.SaveDelivery(ctx, delivery))
+		return delivery
+	}
+	dead := deliver(domain.DeliveryDead)
+	pending := deliver(domain.DeliveryPending)
+	oldest := deliver(domain.DeliverySucceeded)
+	for i := 0; i < domain.MaxWebhookHistory; i++ {
+		deliver(domain.DeliverySucceeded)
+	}
+
+	all, err := repo.ListDeliveries(ctx, webhook.ID, "")
+	require.NoError(t, err)
+	assert.Len(t, all, domain.MaxWebhookHistory+2)
+	_, err = repo.GetDelivery(ctx, webhook.ID, oldest.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	for _, kept := range []*domain.WebhookDelivery{dead, pending} {
+		_, err = repo.GetDelivery(ctx, webhook.ID, kept.ID)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, repo.Sanity(ctx))
+}
@@ -4,6 +4,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/gioannid/platform-go-challenge/internal/domain"
 	"github.com/google/uuid"
@@ -19,14 +20,100 @@ type FavouriteRepository interface {
 	AddFavourite(ctx context.Context, favourite *domain.Favourite) error
 	RemoveFavourite(ctx context.Context, userID, favouriteID uuid.UUID) error
 	IsFavourite(ctx context.Context, userID, assetID uuid.UUID) (bool, error)
+	// FavouritedAssets returns, keyed by asset ID, the favourite IDs of those assets the user has favourited
+	FavouritedAssets(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error)
+	// Batches of favourites by asset ID, each applied under a single transaction; one result per asset ID, in order
+	AddFavourites(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.BatchResult, error)
+	RemoveFavourites(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.BatchResult, error)
+	// UpdateFavourite changes a favourite's annotations; the update is expected to be normalized
+	UpdateFavourite(ctx context.Context, userID, favouriteID uuid.UUID, update domain.FavouriteUpdate) (*domain.Favourite, error)
+
+	// Manual ordering. Favourites are ordered by position, pinned ones first; new favourites are
+	// appended to the unpinned ones. MoveFavourite places a favourite right after or right before
+	// (exactly one of afterID, beforeID) another of the same pin group, else ErrInvalidPosition.
+	MoveFavourite(ctx context.Context, userID, favouriteID uuid.UUID, afterID, beforeID *uuid.UUID) (*domain.Favourite, error)
+	SetFavouritePinned(ctx context.Context, userID, favouriteID uuid.UUID, pinned bool) (*domain.Favourite, error)
+
+	// Collections are user-owned groups of favourites; names are unique per user (ErrAlreadyExists).
+	// Listing favourites with query.CollectionID set returns the members of that collection only.
+	// Removing a favourite removes it from its collections.
+	CreateCollection(ctx context.Context, collection *domain.Collection) error
+	GetCollection(ctx context.Context, userID, collectionID uuid.UUID) (*domain.Collection, error)
+	ListCollections(ctx context.Context, userID uuid.UUID) ([]*domain.Collection, error)
+	RenameCollection(ctx context.Context, userID, collectionID uuid.UUID, name string) (*domain.Collection, error)
+	DeleteCollection(ctx context.Context, userID, collectionID uuid.UUID) error
+	AddToCollection(ctx context.Context, userID, collectionID, favouriteID uuid.UUID) error
+	// AddFavouriteToCollection stores a favourite, unless the user favourited its asset already, and puts
+	// the user's favourite of the asset into one of their collections at once, returning it with its asset.
+	// ErrAlreadyExists reports a favourite in the collection already.
+	AddFavouriteToCollection(ctx context.Context, favourite *domain.Favourite, collectionID uuid.UUID) (*domain.Favourite, error)
+	RemoveFromCollection(ctx context.Context, userID, collectionID, favouriteID uuid.UUID) error
+
+	// Shares grant other users access to a user's favourites or to one of the user's collections
+	// (ErrNotFound if the collection is not the owner's). Redeeming a share's link token adds the
+	// user to its recipients. Deleting a share, or its collection, revokes it.
+	CreateShare(ctx context.Context, share *domain.Share) error
+	GetShare(ctx context.Context, shareID uuid.UUID) (*domain.Share, error)
+	ListShares(ctx context.Context, ownerID uuid.UUID) ([]*domain.Share, error)
+	ListSharedWith(ctx context.Context, userID uuid.UUID) ([]*domain.Share, error)
+	RedeemShareLink(ctx context.Context, token string, userID uuid.UUID) (*domain.Share, error)
+	DeleteShare(ctx context.Context, ownerID, shareID uuid.UUID) error
 
 	// Asset management
 	GetAsset(ctx context.Context, assetID uuid.UUID) (*domain.Asset, error)
-	CreateAsset(ctx context.Context, asset *domain.Asset) error
+	CreateAsset(ctx context.Context, asset *domain.Asset) error // fails with ErrInvalidReference if a referenced asset is missing
 	UpdateAssetDescription(ctx context.Context, assetID uuid.UUID, description string) error
-	DeleteAsset(ctx context.Context, assetID uuid.UUID) error
+	// DeleteAsset removes an asset and its favourites. A referenced asset is only removed with
+	// DeleteCascade, which also removes the referencing assets; the IDs of all removed assets are returned.
+	DeleteAsset(ctx context.Context, assetID uuid.UUID, policy domain.DeletePolicy) ([]uuid.UUID, error)
 	ListAssets(ctx context.Context, query *domain.PageQuery) ([]*domain.Asset, int, error)
 
+	// Popularity. Assets carry their favourite count, kept in line with the favourites added and removed,
+	// and can be listed by it (sort field popularity). TrendingAssets ranks, at most limit, assets by the net
+	// number of favourites gained since a time, counted in buckets of domain.TrendingBucket.
+	TrendingAssets(ctx context.Context, since time.Time, limit int) ([]domain.TrendingAsset, error)
+
+	// Recommendations suggests, at most limit, assets the user has not favourited, ranked by how often they were
+	// favourited together with the user's favourites, weighted by the favourites' recency at the given time and by type.
+	// The co-favourite data is kept up to date as favourites are added and removed.
+	Recommendations(ctx context.Context, userID uuid.UUID, at time.Time, limit int) ([]domain.Recommendation, error)
+
+	// Tags. Tags passed in are expected to be normalized; the updated asset is returned.
+	AddAssetTags(ctx context.Context, assetID uuid.UUID, tags []string) (*domain.Asset, error)
+	RemoveAssetTags(ctx context.Context, assetID uuid.UUID, tags []string) (*domain.Asset, error)
+	ListTags(ctx context.Context) ([]domain.TagCount, error)
+
+	// Full-text search, ranked by relevance. The query's tag filter and pagination apply; its sort order does not.
+	// Favourites match on their asset's text and on their own annotations.
+	SearchAssets(ctx context.Context, text string, query *domain.PageQuery) ([]*domain.Asset, int, error)
+	SearchFavourites(ctx context.Context, userID uuid.UUID, text string, query *domain.PageQuery) ([]*domain.Favourite, int, error)
+
+	// Webhooks subscribe URLs to events. Deliveries are kept per webhook: pending ones and dead letters
+	// until they complete or are redelivered, succeeded ones up to domain.MaxWebhookHistory. SaveDelivery
+	// stores a new delivery or replaces a stored one (ErrNotFound if its webhook is missing); ListDeliveries
+	// returns those with a status, or all when empty, newest first. Deleting a webhook deletes its deliveries.
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) error
+	GetWebhook(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error
+	SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error)
+
+	// Outbox. Mutations reporting changes to favourites and assets (domain.Event) record their events atomically
+	// with the change itself, numbered in order. PendingEvents returns, at most limit, the recorded events not yet
+	// acknowledged, oldest first; AckEvents acknowledges the events up to an ID once handled. Events handled but
+	// not acknowledged, e.g. on a crash, are returned again: they are delivered at least once.
+	PendingEvents(ctx context.Context, limit int) ([]domain.Event, error)
+	AckEvents(ctx context.Context, lastEventID uint64) error
+
+	// Audit trail. Mutations append an entry per change made, atomically with the change itself, recording the
+	// actor and request carried by the context (domain.ActorFromContext) and snapshots of the values before and
+	// after the change. Entries are never changed nor removed; they are numbered from 1 and chained by hash
+	// (domain.AuditEntry.Seal). ListAudit returns the entries matching a query, oldest first, with the number
+	// of entries matching its filters, wherever they start.
+	ListAudit(ctx context.Context, query *domain.AuditQuery) ([]*domain.AuditEntry, int, error)
+
 	// Health check
 	Ping(ctx context.Context) error
 	Sanity(ctx context.Context) error
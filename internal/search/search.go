@@ -0,0 +1,206 @@
+// Package search implements an in-memory inverted index for ranked full-text search with prefix
+// matching. Documents are sets of weighted text fields identified by UUID. The index is not
+// safe for concurrent use; callers are expected to guard it with their own lock.
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// BM25 parameters
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// prefixPenalty scales the score of a term that only matches a query token as a prefix
+const prefixPenalty = 0.6
+
+// stopWords are not indexed and ignored in queries
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "in": true, "is": true, "it": true, "of": true, "on": true, "or": true,
+	"that": true, "the": true, "this": true, "to": true, "with": true,
+}
+
+// Field is a piece of text of a document. Matches in fields with a higher weight rank higher.
+type Field struct {
+	Text   string
+	Weight float64
+}
+
+// Tokenize splits text into lowercase terms on anything that is not a letter or a digit,
+// dropping stop words
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := words[:0]
+	for _, word := range words {
+		if !stopWords[word] {
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// Index is an inverted index from terms to the documents containing them
+type Index struct {
+	postings    map[string]map[uuid.UUID]float64 // term -> document -> weighted term frequency
+	docs        map[uuid.UUID]document
+	terms       []string // sorted, for prefix lookups
+	totalLength float64
+}
+
+type document struct {
+	terms  []string // distinct terms, to remove the postings when the document goes away
+	length float64  // weighted number of terms
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[uuid.UUID]float64),
+		docs:     make(map[uuid.UUID]document),
+	}
+}
+
+// Len returns the number of indexed documents
+func (ix *Index) Len() int {
+	return len(ix.docs)
+}
+
+// Put indexes a document, replacing any previous version of it
+func (ix *Index) Put(id uuid.UUID, fields []Field) {
+	ix.Remove(id)
+
+	frequencies := make(map[string]float64)
+	var length float64
+	for _, field := range fields {
+		for _, term := range Tokenize(field.Text) {
+			frequencies[term] += field.Weight
+			length += field.Weight
+		}
+	}
+	if len(frequencies) == 0 {
+		return
+	}
+
+	doc := document{terms: make([]string, 0, len(frequencies)), length: length}
+	for term, tf := range frequencies {
+		postings, ok := ix.postings[term]
+		if !ok {
+			postings = make(map[uuid.UUID]float64)
+			ix.postings[term] = postings
+			ix.insertTerm(term)
+		}
+		postings[id] = tf
+		doc.terms = append(doc.terms, term)
+	}
+	ix.docs[id] = doc
+	ix.totalLength += length
+}
+
+// Remove drops a document from the index
+func (ix *Index) Remove(id uuid.UUID) {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range doc.terms {
+		postings := ix.postings[term]
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(ix.postings, term)
+			ix.deleteTerm(term)
+		}
+	}
+	delete(ix.docs, id)
+	ix.totalLength -= doc.length
+}
+
+// Search returns the relevance score of every document matching the query. A document matches
+// when each query token is one of its terms or a prefix of one; exact matches score higher
+// than prefix matches, and rarer terms and higher weighted fields score higher (BM25).
+func (ix *Index) Search(query string) map[uuid.UUID]float64 {
+	tokens := ix.TokenScores(query)
+	if len(tokens) == 0 {
+		return map[uuid.UUID]float64{}
+	}
+
+	scores := tokens[0]
+	for _, tokenScores := range tokens[1:] {
+		// Every token must match: keep the documents matching all tokens so far
+		for id, score := range scores {
+			if tokenScore, ok := tokenScores[id]; ok {
+				scores[id] = score + tokenScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+	return scores
+}
+
+// TokenScores scores the documents matching each query token separately, in query token order.
+// It lets callers combine the matches of several indexes token by token; Search is the
+// combination requiring every token within a single index.
+func (ix *Index) TokenScores(query string) []map[uuid.UUID]float64 {
+	tokens := Tokenize(query)
+	scores := make([]map[uuid.UUID]float64, len(tokens))
+	for i, token := range tokens {
+		if len(ix.docs) == 0 {
+			scores[i] = map[uuid.UUID]float64{}
+			continue
+		}
+		scores[i] = ix.scoreToken(token)
+	}
+	return scores
+}
+
+// scoreToken scores the documents matching a single query token, taking the best scoring term
+// per document among the terms the token is a prefix of
+func (ix *Index) scoreToken(token string) map[uuid.UUID]float64 {
+	scores := make(map[uuid.UUID]float64)
+	n := float64(len(ix.docs))
+	avgLength := ix.totalLength / n
+
+	for i := sort.SearchStrings(ix.terms, token); i < len(ix.terms) && strings.HasPrefix(ix.terms[i], token); i++ {
+		term := ix.terms[i]
+		postings := ix.postings[term]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		factor := 1.0
+		if term != token {
+			factor = prefixPenalty * float64(len(token)) / float64(len(term))
+		}
+
+		for id, tf := range postings {
+			norm := tf + k1*(1-b+b*ix.docs[id].length/avgLength)
+			score := factor * idf * tf * (k1 + 1) / norm
+			if score > scores[id] {
+				scores[id] = score
+			}
+		}
+	}
+	return scores
+}
+
+func (ix *Index) insertTerm(term string) {
+	i := sort.SearchStrings(ix.terms, term)
+	ix.terms = append(ix.terms, "")
+	copy(ix.terms[i+1:], ix.terms[i:])
+	ix.terms[i] = term
+}
+
+func (ix *Index) deleteTerm(term string) {
+	i := sort.SearchStrings(ix.terms, term)
+	if i < len(ix.terms) && ix.terms[i] == term {
+		ix.terms = append(ix.terms[:i], ix.terms[i+1:]...)
+	}
+}
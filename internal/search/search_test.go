@@ -0,0 +1,88 @@
+package search
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"q4", "sales", "chart"}, Tokenize("The Q4 sales-chart!"))
+	assert.Equal(t, []string{"café", "2025"}, Tokenize("Café, 2025"))
+	assert.Empty(t, Tokenize("  the of  "))
+}
+
+// ranked returns the IDs of the hits ordered by descending score
+func ranked(scores map[uuid.UUID]float64) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return scores[ids[i]] > scores[ids[j]] })
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	ix := NewIndex()
+	sales := uuid.New()
+	salesBody := uuid.New()
+	social := uuid.New()
+	salesman := uuid.New()
+
+	ix.Put(sales, []Field{{Text: "Q4 sales", Weight: 3}, {Text: "Revenue by month", Weight: 1}})
+	ix.Put(salesBody, []Field{{Text: "Quarterly report", Weight: 3}, {Text: "Q4 sales were strong", Weight: 1}})
+	ix.Put(social, []Field{{Text: "Social media usage", Weight: 3}})
+	ix.Put(salesman, []Field{{Text: "Salesman of the year", Weight: 3}})
+	assert.Equal(t, 4, ix.Len())
+
+	t.Run("all tokens must match", func(t *testing.T) {
+		assert.ElementsMatch(t, []uuid.UUID{sales, salesBody}, ranked(ix.Search("q4 sales")))
+		assert.Empty(t, ix.Search("q4 social"))
+	})
+
+	t.Run("higher weighted fields rank first", func(t *testing.T) {
+		assert.Equal(t, []uuid.UUID{sales, salesBody}, ranked(ix.Search("Q4 SALES")))
+	})
+
+	t.Run("prefix matches rank below exact matches", func(t *testing.T) {
+		scores := ix.Search("sales")
+		assert.Len(t, scores, 3)
+		assert.Greater(t, scores[sales], scores[salesman])
+
+		assert.ElementsMatch(t, []uuid.UUID{social}, ranked(ix.Search("soc med")))
+	})
+
+	t.Run("updates replace the previous version", func(t *testing.T) {
+		ix.Put(social, []Field{{Text: "TikTok usage", Weight: 3}})
+		assert.Empty(t, ix.Search("social"))
+		assert.Len(t, ix.Search("tiktok"), 1)
+	})
+
+	t.Run("removed documents are not found", func(t *testing.T) {
+		ix.Remove(salesman)
+		assert.Empty(t, ix.Search("salesman"))
+		assert.NotContains(t, ix.terms, "salesman")
+		assert.Equal(t, 3, ix.Len())
+	})
+
+	t.Run("queries without terms match nothing", func(t *testing.T) {
+		assert.Empty(t, ix.Search("the"))
+	})
+}
+
+func TestIndex_TokenScores(t *testing.T) {
+	ix := NewIndex()
+	assert.Equal(t, []map[uuid.UUID]float64{{}, {}}, ix.TokenScores("q4 sales"), "one entry per token, even when empty")
+
+	sales := uuid.New()
+	ix.Put(sales, []Field{{Text: "Q4 sales", Weight: 3}})
+	ix.Put(uuid.New(), []Field{{Text: "Social media", Weight: 3}})
+
+	tokens := ix.TokenScores("sales of social")
+	assert.Len(t, tokens, 2, "stop words are not tokens")
+	assert.Equal(t, []uuid.UUID{sales}, ranked(tokens[0]))
+	assert.Len(t, tokens[1], 1)
+	assert.Empty(t, ix.Search("sales of social"))
+}
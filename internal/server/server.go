@@ -3,6 +3,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gioannid/platform-go-challenge/internal/config"
@@ -17,6 +18,7 @@ import (
 // Server holds HTTP server and dependencies
 type Server struct {
 	httpServer *http.Server
+	handler    *handler.Handler
 	config     *config.Config
 }
 
@@ -61,12 +63,26 @@ func New(cfg *config.Config, h *handler.Handler, generalMW MiddlewareChain) *Ser
 	if cfg.AuthEnabled {
 		api.Use(middleware.JWTAuth(cfg.JWTSecret))
 	}
+	// Idempotency keys are scoped per user, hence applied after authentication
+	api.Use(middleware.Idempotency(middleware.IdempotencyPolicy{
+		Window:   cfg.IdempotencyWindow,
+		MaxKeys:  cfg.IdempotencyMaxKeys,
+		MaxBytes: cfg.IdempotencyMaxBytes,
+	}))
 
 	// Asset management (these handlers will be protected if auth is enabled)
 	api.HandleFunc("/assets", h.CreateAsset).Methods(http.MethodPost)
 	api.HandleFunc("/assets", h.ListAssets).Methods(http.MethodGet)
+	api.HandleFunc("/assets/search", h.SearchAssets).Methods(http.MethodGet)
+	api.HandleFunc("/assets/trending", h.TrendingAssets).Methods(http.MethodGet)
 	api.HandleFunc("/assets/{assetId}/description", h.UpdateAssetDescription).Methods(http.MethodPatch)
+	api.HandleFunc("/assets/{assetId}/match", h.MatchAudience).Methods(http.MethodPost)
+	api.HandleFunc("/assets/{assetId}/render", h.RenderAsset).Methods(http.MethodGet)
+	api.HandleFunc("/assets/{assetId}/tags", h.AddAssetTags).Methods(http.MethodPost)
+	api.HandleFunc("/assets/{assetId}/tags/{tag}", h.RemoveAssetTag).Methods(http.MethodDelete)
+	api.HandleFunc("/assets/{assetId}", h.GetAsset).Methods(http.MethodGet)
 	api.HandleFunc("/assets/{assetId}", h.DeleteAsset).Methods(http.MethodDelete)
+	api.HandleFunc("/tags", h.ListTags).Methods(http.MethodGet)
 
 	// Favourite management (these handlers will be protected if auth is enabled)
 	// TODO: Update routes to remove {userId} from path parameters.
@@ -74,17 +90,60 @@ func New(cfg *config.Config, h *handler.Handler, generalMW MiddlewareChain) *Ser
 	// passed via the request context to the handlers.
 	api.HandleFunc("/users/{userId}/favourites", h.ListFavourites).Methods(http.MethodGet)
 	api.HandleFunc("/users/{userId}/favourites", h.AddFavourite).Methods(http.MethodPost)
+	api.HandleFunc("/users/{userId}/favourites:batch", h.AddFavourites).Methods(http.MethodPost)
+	api.HandleFunc("/users/{userId}/favourites:batch", h.RemoveFavourites).Methods(http.MethodDelete)
+	api.HandleFunc("/users/{userId}/favourites/search", h.SearchFavourites).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/favourites/events", h.FavouriteEvents).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/live", h.Live).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/favourites/status", h.FavouriteStatuses).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/favourites/{favouriteId}", h.GetFavourite).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/favourites/{favouriteId}", h.UpdateFavourite).Methods(http.MethodPatch)
 	api.HandleFunc("/users/{userId}/favourites/{favouriteId}", h.RemoveFavourite).Methods(http.MethodDelete)
-
+	api.HandleFunc("/users/{userId}/favourites/{favouriteId}/position", h.MoveFavourite).Methods(http.MethodPut)
+	api.HandleFunc("/users/{userId}/favourites/{favouriteId}/pin", h.PinFavourite).Methods(http.MethodPut)
+	api.HandleFunc("/users/{userId}/favourites/{favouriteId}/pin", h.UnpinFavourite).Methods(http.MethodDelete)
+	api.HandleFunc("/users/{userId}/collections", h.ListCollections).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/collections", h.CreateCollection).Methods(http.MethodPost)
+	api.HandleFunc("/users/{userId}/collections/{collectionId}", h.RenameCollection).Methods(http.MethodPatch)
+	api.HandleFunc("/users/{userId}/collections/{collectionId}", h.DeleteCollection).Methods(http.MethodDelete)
+	api.HandleFunc("/users/{userId}/collections/{collectionId}/favourites", h.ListCollectionFavourites).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/collections/{collectionId}/favourites/{favouriteId}", h.AddToCollection).Methods(http.MethodPut)
+	api.HandleFunc("/users/{userId}/collections/{collectionId}/favourites/{favouriteId}", h.RemoveFromCollection).Methods(http.MethodDelete)
+	api.HandleFunc("/users/{userId}/recommendations", h.Recommendations).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/shares", h.ListShares).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/shares", h.CreateShare).Methods(http.MethodPost)
+	api.HandleFunc("/users/{userId}/shares/{shareId}", h.RevokeShare).Methods(http.MethodDelete)
+	api.HandleFunc("/users/{userId}/shared", h.SharedWithMe).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/shared", h.RedeemShareLink).Methods(http.MethodPost)
+	api.HandleFunc("/users/{userId}/shared/{shareId}/favourites", h.ListSharedFavourites).Methods(http.MethodGet)
+	api.HandleFunc("/users/{userId}/shared/{shareId}/favourites", h.AddSharedFavourite).Methods(http.MethodPost)
+	api.HandleFunc("/users/{userId}/shared/{shareId}/favourites/{favouriteId}", h.RemoveSharedFavourite).Methods(http.MethodDelete)
+	api.HandleFunc("/users/{userId}/shared/{shareId}/copy", h.CopySharedFavourites).Methods(http.MethodPost)
+
+	// Webhook subscriptions
+	api.HandleFunc("/webhooks", h.ListWebhooks).Methods(http.MethodGet)
+	api.HandleFunc("/webhooks", h.CreateWebhook).Methods(http.MethodPost)
+	api.HandleFunc("/webhooks/{webhookId}", h.GetWebhook).Methods(http.MethodGet)
+	api.HandleFunc("/webhooks/{webhookId}", h.DeleteWebhook).Methods(http.MethodDelete)
+	api.HandleFunc("/webhooks/{webhookId}/deliveries", h.ListWebhookDeliveries).Methods(http.MethodGet)
+	api.HandleFunc("/webhooks/{webhookId}/deliveries/{deliveryId}/redeliver", h.RedeliverWebhook).Methods(http.MethodPost)
+	api.HandleFunc("/webhooks/{webhookId}/dead-letters", h.ListDeadLetters).Methods(http.MethodGet)
+
+	// Audit trail (administrators only)
+	api.HandleFunc("/audit", h.ListAudit).Methods(http.MethodGet)
+	api.HandleFunc("/audit/verify", h.VerifyAudit).Methods(http.MethodGet)
+
+	httpServer := &http.Server{
+		Addr:         cfg.ServerAddress,
+		Handler:      r, // The main router 'r' is now the handler, with middleware applied via .Use()
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
 	return &Server{
-		httpServer: &http.Server{
-			Addr:         cfg.ServerAddress,
-			Handler:      r, // The main router 'r' is now the handler, with middleware applied via .Use()
-			ReadTimeout:  cfg.ReadTimeout,
-			WriteTimeout: cfg.WriteTimeout,
-			IdleTimeout:  cfg.IdleTimeout,
-		},
-		config: cfg,
+		httpServer: httpServer,
+		handler:    h,
+		config:     cfg,
 	}
 }
 
@@ -98,7 +157,13 @@ func (s *Server) Router() http.Handler {
 	return s.httpServer.Handler
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server. Event streams never go idle and WebSocket connections
+// are not tracked by http.Server, so the handler closes them meanwhile.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	streamsClosed := make(chan error, 1)
+	go func() {
+		streamsClosed <- s.handler.CloseStreams(ctx)
+	}()
+	err := s.httpServer.Shutdown(ctx)
+	return errors.Join(err, <-streamsClosed)
 }
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+)
+
+// ListAudit returns a page of the audit trail, oldest first, with the number of entries matching
+// the query's filters
+func (s *FavouriteService) ListAudit(ctx context.Context, query *domain.AuditQuery) ([]*domain.AuditEntry, int, error) {
+	if err := query.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListAudit(ctx, query)
+}
+
+// VerifyAudit checks the whole audit trail against its hash chain, page by page, and reports the
+// first entry off the chain. The head hash returned can be kept elsewhere, so that a later check
+// also tells whether entries were dropped from the end of the trail.
+func (s *FavouriteService) VerifyAudit(ctx context.Context) (*domain.AuditVerification, error) {
+	result := &domain.AuditVerification{Valid: true, HeadHash: domain.AuditGenesisHash}
+	query := &domain.AuditQuery{Limit: domain.MaxAuditLimit}
+	for {
+		entries, _, err := s.repo.ListAudit(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if len(entries) == 0 {
+			return result, nil
+		}
+		for i, entry := range entries {
+			head, err := domain.VerifyAuditChain(result.HeadHash, entries[i:i+1])
+			if err != nil {
+				result.Valid = false
+				result.BrokenAt = entry.Seq
+				result.Error = err.Error()
+				return result, nil
+			}
+			result.HeadHash = head
+			result.Entries++
+		}
+		query.AfterSeq = entries[len(entries)-1].Seq
+	}
+}
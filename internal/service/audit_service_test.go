@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// auditTrail returns n entries chained as the audit trail appends them
+func auditTrail(n int) []*domain.AuditEntry {
+	entries := make([]*domain.AuditEntry, n)
+	prevHash := domain.AuditGenesisHash
+	for i := range entries {
+		entries[i] = domain.NewAuditEntry(domain.Actor{RequestID: "req"}, domain.AuditAssetCreate, nil, nil, map[string]int{"n": i})
+		entries[i].Seal(uint64(i+1), prevHash)
+		prevHash = entries[i].Hash
+	}
+	return entries
+}
+
+// mockAuditPages has the mock repository list entries in pages of the largest size, as VerifyAudit reads them
+func mockAuditPages(mockRepo *MockRepository, entries []*domain.AuditEntry) {
+	for start := 0; ; {
+		end := min(start+domain.MaxAuditLimit, len(entries))
+		after := uint64(start)
+		mockRepo.On("ListAudit", mock.Anything, mock.MatchedBy(func(q *domain.AuditQuery) bool {
+			return q.AfterSeq == after
+		})).Return(entries[start:end], len(entries), nil).Once()
+		if start == end {
+			return
+		}
+		start = end
+	}
+}
+
+func TestFavouriteService_VerifyAudit(t *testing.T) {
+	ctx := context.Background()
+	entries := auditTrail(domain.MaxAuditLimit + 2)
+
+	mockRepo := new(MockRepository)
+	mockAuditPages(mockRepo, entries)
+	svc := NewFavouriteService(mockRepo)
+	result, err := svc.VerifyAudit(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &domain.AuditVerification{Valid: true, Entries: len(entries), HeadHash: entries[len(entries)-1].Hash}, result)
+	mockRepo.AssertExpectations(t)
+
+	// A tampered entry is reported, on whichever page
+	tampered := *entries[domain.MaxAuditLimit]
+	tampered.RequestID = "forged"
+	entries[domain.MaxAuditLimit] = &tampered
+	mockRepo = new(MockRepository)
+	mockAuditPages(mockRepo, entries)
+	result, err = NewFavouriteService(mockRepo).VerifyAudit(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, tampered.Seq, result.BrokenAt)
+	assert.Equal(t, domain.MaxAuditLimit, result.Entries)
+	assert.Contains(t, result.Error, "does not match its hash")
+}
+
+func TestFavouriteService_ListAudit(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	svc := NewFavouriteService(mockRepo)
+
+	// Invalid queries never reach the repository
+	now := time.Now()
+	_, _, err := svc.ListAudit(ctx, &domain.AuditQuery{Since: now, Until: now.Add(-time.Hour), Limit: 10})
+	assert.ErrorIs(t, err, domain.ErrInvalidAuditQuery)
+	mockRepo.AssertNotCalled(t, "ListAudit", mock.Anything, mock.Anything)
+
+	entries := auditTrail(2)
+	query := &domain.AuditQuery{Action: "asset", Limit: 10}
+	mockRepo.On("ListAudit", ctx, query).Return(entries, 2, nil)
+	listed, total, err := svc.ListAudit(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, entries, listed)
+	assert.Equal(t, 2, total)
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CreateCollection creates an empty collection for a user
+func (s *FavouriteService) CreateCollection(ctx context.Context, userID uuid.UUID, name string) (*domain.Collection, error) {
+	collection, err := domain.NewCollection(userID, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// GetCollection returns one of a user's collections
+func (s *FavouriteService) GetCollection(ctx context.Context, userID, collectionID uuid.UUID) (*domain.Collection, error) {
+	return s.repo.GetCollection(ctx, userID, collectionID)
+}
+
+// ListCollections returns a user's collections ordered by name
+func (s *FavouriteService) ListCollections(ctx context.Context, userID uuid.UUID) ([]*domain.Collection, error) {
+	return s.repo.ListCollections(ctx, userID)
+}
+
+// RenameCollection gives a collection a new name
+func (s *FavouriteService) RenameCollection(ctx context.Context, userID, collectionID uuid.UUID, name string) (*domain.Collection, error) {
+	normalized, err := domain.NormalizeCollectionName(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.RenameCollection(ctx, userID, collectionID, normalized)
+}
+
+// DeleteCollection deletes a collection, keeping the favourites it contained
+func (s *FavouriteService) DeleteCollection(ctx context.Context, userID, collectionID uuid.UUID) error {
+	return s.repo.DeleteCollection(ctx, userID, collectionID)
+}
+
+// AddToCollection puts one of the user's favourites into a collection
+func (s *FavouriteService) AddToCollection(ctx context.Context, userID, collectionID, favouriteID uuid.UUID) error {
+	return s.repo.AddToCollection(ctx, userID, collectionID, favouriteID)
+}
+
+// RemoveFromCollection takes a favourite out of a collection; the favourite itself is kept
+func (s *FavouriteService) RemoveFromCollection(ctx context.Context, userID, collectionID, favouriteID uuid.UUID) error {
+	return s.repo.RemoveFromCollection(ctx, userID, collectionID, favouriteID)
+}
+
+// ListCollectionFavourites returns a page of the favourites in a collection, with the same
+// pagination, sorting and filtering as ListFavourites
+func (s *FavouriteService) ListCollectionFavourites(ctx context.Context, userID, collectionID uuid.UUID, query *domain.PageQuery) ([]*domain.Favourite, domain.PageInfo, error) {
+	query.CollectionID = &collectionID
+	return s.ListFavourites(ctx, userID, query)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavouriteService_CreateCollection(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("normalizes the name", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("CreateCollection", ctx, mock.MatchedBy(func(c *domain.Collection) bool {
+			return c.UserID == userID && c.Name == "Q4 review"
+		})).Return(nil)
+
+		svc := NewFavouriteService(mockRepo)
+		collection, err := svc.CreateCollection(ctx, userID, "  Q4   review ")
+		require.NoError(t, err)
+		assert.Equal(t, "Q4 review", collection.Name)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("CreateCollection", ctx, mock.Anything).Return(domain.ErrAlreadyExists)
+
+		svc := NewFavouriteService(mockRepo)
+		_, err := svc.CreateCollection(ctx, userID, "Q4 review")
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		svc := NewFavouriteService(mockRepo)
+		for _, name := range []string{"", "   ", strings.Repeat("x", 101)} {
+			_, err := svc.CreateCollection(ctx, userID, name)
+			assert.ErrorIs(t, err, domain.ErrInvalidCollection)
+		}
+		mockRepo.AssertNotCalled(t, "CreateCollection", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFavouriteService_RenameCollection(t *testing.T) {
+	ctx := context.Background()
+	userID, collectionID := uuid.New(), uuid.New()
+
+	mockRepo := new(MockRepository)
+	renamed := &domain.Collection{ID: collectionID, UserID: userID, Name: "UK audiences"}
+	mockRepo.On("RenameCollection", ctx, userID, collectionID, "UK audiences").Return(renamed, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	got, err := svc.RenameCollection(ctx, userID, collectionID, " UK audiences")
+	require.NoError(t, err)
+	assert.Equal(t, renamed, got)
+
+	_, err = svc.RenameCollection(ctx, userID, collectionID, "")
+	assert.ErrorIs(t, err, domain.ErrInvalidCollection)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFavouriteService_ListCollectionFavourites(t *testing.T) {
+	ctx := context.Background()
+	userID, collectionID := uuid.New(), uuid.New()
+
+	mockRepo := new(MockRepository)
+	query := domain.NewPageQuery(10, 0, "created_at", "desc")
+	mockRepo.On("ListFavourites", ctx, userID, mock.MatchedBy(func(q *domain.PageQuery) bool {
+		return q.CollectionID != nil && *q.CollectionID == collectionID
+	})).Return([]*domain.Favourite{}, 0, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	_, page, err := svc.ListCollectionFavourites(ctx, userID, collectionID, query)
+	require.NoError(t, err)
+	assert.Equal(t, 0, page.Total)
+	mockRepo.AssertExpectations(t)
+}
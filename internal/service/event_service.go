@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/events"
+	"github.com/google/uuid"
+)
+
+// DispatchEvents relays the events recorded with each change to the change feed and to webhooks,
+// and attempts webhook deliveries, until the context is done. Events recorded while not running
+// are relayed once run.
+func (s *FavouriteService) DispatchEvents(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		s.webhooks.Run(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		s.outbox.Run(ctx)
+	}()
+	wg.Wait()
+}
+
+// SubscribeEvents subscribes a user to the change feed: events about the user's favourites and
+// about the assets the user has favourited, as of when each event is published. With a non-zero
+// lastEventID the events published after it are replayed, as long as they are still buffered.
+// The caller must close the subscription.
+func (s *FavouriteService) SubscribeEvents(userID uuid.UUID, lastEventID uint64) *events.Subscription {
+	favourited := func(assetID uuid.UUID) bool {
+		isFav, err := s.repo.IsFavourite(context.Background(), userID, assetID)
+		return err == nil && isFav
+	}
+	return s.events.Subscribe(func(event domain.Event) bool {
+		return event.VisibleTo(userID, favourited)
+	}, lastEventID)
+}
+
+// CloseEvents ends all change feed subscriptions, e.g. on shutdown
+func (s *FavouriteService) CloseEvents() {
+	s.events.Close()
+}
+
+// WatchEvents subscribes a user to events about the user's favourites and about the assets the
+// watched function reports as watched; the watched assets may change while subscribed. The
+// caller must close the subscription.
+func (s *FavouriteService) WatchEvents(userID uuid.UUID, watched func(assetID uuid.UUID) bool) *events.Subscription {
+	return s.events.Subscribe(func(event domain.Event) bool {
+		return event.VisibleTo(userID, watched)
+	}, 0)
+}
+
+// ExistingAssets splits a batch of asset IDs into those of existing assets and the others
+func (s *FavouriteService) ExistingAssets(ctx context.Context, assetIDs []uuid.UUID) ([]uuid.UUID, []uuid.UUID, error) {
+	if err := validateBatch(assetIDs); err != nil {
+		return nil, nil, err
+	}
+	var found, notFound []uuid.UUID
+	for _, assetID := range assetIDs {
+		_, err := s.repo.GetAsset(ctx, assetID)
+		switch {
+		case err == nil:
+			found = append(found, assetID)
+		case errors.Is(err, domain.ErrNotFound):
+			notFound = append(notFound, assetID)
+		default:
+			return nil, nil, err
+		}
+	}
+	return found, notFound, nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// dispatchRecorded runs the service's event dispatching, with the repository's outbox holding
+// the events given, numbered from 1 in order, until the test ends
+func dispatchRecorded(t *testing.T, mockRepo *MockRepository, svc *FavouriteService, recorded ...domain.Event) {
+	t.Helper()
+
+	for i := range recorded {
+		recorded[i].ID = uint64(i + 1)
+	}
+	mockRepo.On("ListWebhooks", mock.Anything).Return([]*domain.Webhook{}, nil)
+	mockRepo.On("PendingEvents", mock.Anything, mock.Anything).Return(recorded, nil).Once()
+	mockRepo.On("PendingEvents", mock.Anything, mock.Anything).Return([]domain.Event{}, nil)
+	mockRepo.On("AckEvents", mock.Anything, uint64(len(recorded))).Return(nil).Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.DispatchEvents(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		mockRepo.AssertCalled(t, "AckEvents", mock.Anything, uint64(len(recorded)))
+	})
+}
+
+func TestFavouriteService_SubscribeEvents(t *testing.T) {
+	userID, otherID := uuid.New(), uuid.New()
+	assetID, otherAssetID := uuid.New(), uuid.New()
+	fav := domain.NewFavourite(userID, assetID)
+
+	// Only the user favourited assetID, only the other user otherAssetID
+	mockRepo := new(MockRepository)
+	mockRepo.On("IsFavourite", mock.Anything, userID, assetID).Return(true, nil)
+	mockRepo.On("IsFavourite", mock.Anything, userID, otherAssetID).Return(false, nil)
+	mockRepo.On("IsFavourite", mock.Anything, otherID, assetID).Return(false, nil)
+	mockRepo.On("IsFavourite", mock.Anything, otherID, otherAssetID).Return(true, nil)
+	svc := NewFavouriteService(mockRepo)
+	sub := svc.SubscribeEvents(userID, 0)
+	defer sub.Close()
+	otherSub := svc.SubscribeEvents(otherID, 0)
+	defer otherSub.Close()
+
+	dispatchRecorded(t, mockRepo, svc,
+		domain.NewFavouriteEvent(domain.EventFavouriteAdded, fav),
+		domain.NewFavouriteEvent(domain.EventFavouriteRemoved, fav),
+		domain.NewFavouriteEvent(domain.EventFavouriteRemoved, domain.NewFavourite(otherID, assetID)),
+		domain.NewAssetEvent(domain.EventAssetUpdated, assetID, nil),
+		domain.NewAssetEvent(domain.EventAssetUpdated, otherAssetID, nil),
+	)
+
+	// Other users' favourite events are left out, and so are events about assets the user has
+	// not favourited
+	var received []domain.Event
+	for len(received) < 3 {
+		received = append(received, <-sub.Events())
+	}
+	assert.Equal(t, domain.EventFavouriteAdded, received[0].Type)
+	assert.Equal(t, fav.ID, *received[0].FavouriteID)
+	assert.Equal(t, domain.EventFavouriteRemoved, received[1].Type)
+	assert.Equal(t, fav.ID, *received[1].FavouriteID)
+	assert.Equal(t, domain.EventAssetUpdated, received[2].Type)
+	assert.Equal(t, assetID, *received[2].AssetID)
+	assert.Equal(t, uint64(4), received[2].ID, "events keep the IDs they were recorded with")
+
+	var otherReceived []domain.Event
+	for len(otherReceived) < 2 {
+		otherReceived = append(otherReceived, <-otherSub.Events())
+	}
+	assert.Equal(t, uint64(3), otherReceived[0].ID)
+	assert.Equal(t, domain.EventAssetUpdated, otherReceived[1].Type)
+	assert.Equal(t, otherAssetID, *otherReceived[1].AssetID, "no events about the asset only the user favourited")
+
+	// Resuming replays the missed events visible to the user
+	resumed := svc.SubscribeEvents(userID, received[0].ID)
+	defer resumed.Close()
+	assert.False(t, resumed.Lost)
+	assert.Equal(t, received[1:], resumed.Missed)
+
+	svc.CloseEvents()
+	_, open := <-sub.Events()
+	assert.False(t, open)
+	_, open = <-otherSub.Events()
+	assert.False(t, open, "no further events for the other user")
+}
+
+func TestFavouriteService_WatchEvents(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	watchedID, otherID, missingID := uuid.New(), uuid.New(), uuid.New()
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetAsset", ctx, watchedID).Return(createTestAsset(t, domain.AssetTypeChart, watchedID), nil)
+	mockRepo.On("GetAsset", ctx, missingID).Return(nil, domain.ErrNotFound)
+	svc := NewFavouriteService(mockRepo)
+
+	found, notFound, err := svc.ExistingAssets(ctx, []uuid.UUID{watchedID, missingID})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{watchedID}, found)
+	assert.Equal(t, []uuid.UUID{missingID}, notFound)
+	_, _, err = svc.ExistingAssets(ctx, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+
+	sub := svc.WatchEvents(userID, func(assetID uuid.UUID) bool { return assetID == watchedID })
+	defer sub.Close()
+	dispatchRecorded(t, mockRepo, svc,
+		domain.NewAssetEvent(domain.EventAssetUpdated, otherID, nil),
+		domain.NewAssetEvent(domain.EventAssetUpdated, watchedID, nil),
+		domain.NewFavouriteEvent(domain.EventFavouriteAdded, domain.NewFavourite(userID, otherID)),
+	)
+
+	updated := <-sub.Events()
+	assert.Equal(t, domain.EventAssetUpdated, updated.Type)
+	assert.Equal(t, watchedID, *updated.AssetID)
+	assert.Equal(t, domain.EventFavouriteAdded, (<-sub.Events()).Type)
+}
@@ -1,40 +1,90 @@
 // Package service defines the Service Layer, implementing the business logic of handling assets and
 // marking assets as favourites. Note that while users are authenticated via JWT in Presentation layer,
 // there is not yet implemented any user management (registration, login, etc.)
-// or authorization (roles, permissions, no need to specify the user id in non-admin accesses) mechanism.
+// or authorization (roles, permissions, no need to specify the user id in non-admin accesses) mechanism,
+// but for an "admin" token claim granting access to the audit trail and webhooks.
 package service
 
 import (
 	"context"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/gioannid/platform-go-challenge/internal/config"
 	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/events"
+	"github.com/gioannid/platform-go-challenge/internal/outbox"
+	"github.com/gioannid/platform-go-challenge/internal/render"
 	"github.com/gioannid/platform-go-challenge/internal/repository"
+	"github.com/gioannid/platform-go-challenge/internal/search"
+	"github.com/gioannid/platform-go-challenge/internal/webhook"
 	"github.com/google/uuid"
 )
 
 // FavouriteService handles business logic for favourites
 type FavouriteService struct {
-	repo repository.FavouriteRepository
+	repo        repository.FavouriteRepository
+	renderCache *render.Cache
+	events      *events.Bus
+	webhooks    *webhook.Dispatcher
+	outbox      *outbox.Dispatcher
 }
 
 // NewFavouriteService creates a new service instance
 func NewFavouriteService(repo repository.FavouriteRepository) *FavouriteService {
+	cfg := config.Get()
+	bus := events.NewBus(cfg.EventReplaySize)
+	webhooks := webhook.NewDispatcher(repo, webhook.NewClient(cfg.WebhookAllowPrivate), webhook.Policy{
+		Workers:     cfg.WebhookWorkers,
+		Timeout:     cfg.WebhookTimeout,
+		MaxAttempts: cfg.WebhookMaxAttempts,
+		Backoff:     cfg.WebhookRetryBackoff,
+		MaxBackoff:  cfg.WebhookMaxBackoff,
+	})
+
+	// The events recorded with each change are relayed to the change feed and to webhooks, and optionally logged
+	sinks := []outbox.Sink{outbox.NewBusSink(bus), webhooks}
+	if cfg.OutboxLogEvents {
+		sinks = append(sinks, outbox.NewLogSink(log.Default()))
+	}
+	if cfg.OutboxFile != "" {
+		sinks = append(sinks, outbox.NewFileSink(cfg.OutboxFile))
+	}
+
 	return &FavouriteService{
-		repo: repo,
+		repo:        repo,
+		renderCache: render.NewCache(cfg.RenderCacheSize),
+		events:      bus,
+		webhooks:    webhooks,
+		outbox: outbox.NewDispatcher(repo, outbox.Policy{
+			BatchSize:    cfg.OutboxBatchSize,
+			PollInterval: cfg.OutboxPollInterval,
+		}, sinks...),
 	}
 }
 
 // ListFavourites returns paginated list of user's favourites
-func (s *FavouriteService) ListFavourites(ctx context.Context, userID uuid.UUID, query *domain.PageQuery) ([]*domain.Favourite, int, error) {
+func (s *FavouriteService) ListFavourites(ctx context.Context, userID uuid.UUID, query *domain.PageQuery) ([]*domain.Favourite, domain.PageInfo, error) {
 	cfg := config.Get()
 	// Business logic validation
 	if query.Limit > cfg.MaxPageItems {
 		query.Limit = cfg.MaxPageItems // Enforce maximum
 	}
+	if query.SortBy != "created_at" && query.SortBy != "position" {
+		query.IncludeAsset = true // cursors of the other sort fields are keyed by the asset
+	}
+
+	return listPage(query, func(probe *domain.PageQuery) ([]*domain.Favourite, int, error) {
+		return s.repo.ListFavourites(ctx, userID, probe)
+	}, func(fav *domain.Favourite) (string, uuid.UUID) {
+		return domain.FavouriteSortKey(fav, query.SortBy), fav.ID
+	})
+}
 
-	return s.repo.ListFavourites(ctx, userID, query)
+// GetFavourite returns one of the user's favourites with its asset
+func (s *FavouriteService) GetFavourite(ctx context.Context, userID, favouriteID uuid.UUID) (*domain.Favourite, error) {
+	return s.repo.GetFavourite(ctx, userID, favouriteID)
 }
 
 // AddFavourite adds an asset to user's favourites
@@ -60,27 +110,128 @@ func (s *FavouriteService) AddFavourite(ctx context.Context, userID, assetID uui
 		return nil, err
 	}
 
-	// Return favourite with asset data attached
+	// Return favourite with asset data attached, re-read so that its favourite count includes the new favourite
+	if counted, err := s.repo.GetAsset(ctx, assetID); err == nil {
+		asset = counted
+	}
 	fav.Asset = asset
+	s.outbox.Notify()
 	return fav, nil
 }
 
 // RemoveFavourite removes an asset from user's favourites
 func (s *FavouriteService) RemoveFavourite(ctx context.Context, userID, favouriteID uuid.UUID) error {
-	return s.repo.RemoveFavourite(ctx, userID, favouriteID)
+	if err := s.repo.RemoveFavourite(ctx, userID, favouriteID); err != nil {
+		return err
+	}
+	s.outbox.Notify()
+	return nil
+}
+
+// FavouriteStatuses tells, for each of a batch of assets in order, whether the user has favourited it
+func (s *FavouriteService) FavouriteStatuses(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.FavouriteStatus, error) {
+	if err := validateBatch(assetIDs); err != nil {
+		return nil, err
+	}
+	favourited, err := s.repo.FavouritedAssets(ctx, userID, assetIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]domain.FavouriteStatus, len(assetIDs))
+	for i, assetID := range assetIDs {
+		statuses[i] = domain.FavouriteStatus{AssetID: assetID}
+		if favID, ok := favourited[assetID]; ok {
+			statuses[i].Favourited = true
+			statuses[i].FavouriteID = &favID
+		}
+	}
+	return statuses, nil
 }
 
-// CreateAsset creates a new asset
-func (s *FavouriteService) CreateAsset(ctx context.Context, assetType domain.AssetType, description string, data interface{}) (*domain.Asset, error) {
+// FavouritedAssets returns, keyed by asset ID, the favourite IDs of those assets the user has favourited
+func (s *FavouriteService) FavouritedAssets(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	return s.repo.FavouritedAssets(ctx, userID, assetIDs)
+}
+
+// AddFavourites favourites a batch of assets at once, reporting the outcome per asset
+func (s *FavouriteService) AddFavourites(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.BatchResult, error) {
+	if err := validateBatch(assetIDs); err != nil {
+		return nil, err
+	}
+	results, err := s.repo.AddFavourites(ctx, userID, assetIDs)
+	s.outbox.Notify()
+	return results, err
+}
+
+// RemoveFavourites removes the user's favourites of a batch of assets at once, reporting the outcome per asset
+func (s *FavouriteService) RemoveFavourites(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.BatchResult, error) {
+	if err := validateBatch(assetIDs); err != nil {
+		return nil, err
+	}
+	results, err := s.repo.RemoveFavourites(ctx, userID, assetIDs)
+	s.outbox.Notify()
+	return results, err
+}
+
+// validateBatch checks that a batch is neither empty nor larger than configured
+func validateBatch(assetIDs []uuid.UUID) error {
+	if len(assetIDs) == 0 {
+		return fmt.Errorf("%w: no asset IDs given", domain.ErrInvalidBatch)
+	}
+	if limit := config.Get().MaxBatchItems; len(assetIDs) > limit {
+		return fmt.Errorf("%w: more than %d asset IDs", domain.ErrInvalidBatch, limit)
+	}
+	return nil
+}
+
+// UpdateFavourite changes the user's title and note on a favourite
+func (s *FavouriteService) UpdateFavourite(ctx context.Context, userID, favouriteID uuid.UUID, update domain.FavouriteUpdate) (*domain.Favourite, error) {
+	normalized, err := update.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateFavourite(ctx, userID, favouriteID, normalized)
+}
+
+// MoveFavourite places a favourite right after or right before another of the user's favourites
+func (s *FavouriteService) MoveFavourite(ctx context.Context, userID, favouriteID uuid.UUID, afterID, beforeID *uuid.UUID) (*domain.Favourite, error) {
+	if (afterID == nil) == (beforeID == nil) {
+		return nil, fmt.Errorf("%w: exactly one of after_id and before_id is required", domain.ErrInvalidPosition)
+	}
+	return s.repo.MoveFavourite(ctx, userID, favouriteID, afterID, beforeID)
+}
+
+// PinFavourite pins a favourite to the top of the user's ordered favourites, after those already pinned
+func (s *FavouriteService) PinFavourite(ctx context.Context, userID, favouriteID uuid.UUID) (*domain.Favourite, error) {
+	return s.repo.SetFavouritePinned(ctx, userID, favouriteID, true)
+}
+
+// UnpinFavourite unpins a favourite, placing it first among the unpinned ones
+func (s *FavouriteService) UnpinFavourite(ctx context.Context, userID, favouriteID uuid.UUID) (*domain.Favourite, error) {
+	return s.repo.SetFavouritePinned(ctx, userID, favouriteID, false)
+}
+
+// GetAsset returns an asset
+func (s *FavouriteService) GetAsset(ctx context.Context, assetID uuid.UUID) (*domain.Asset, error) {
+	return s.repo.GetAsset(ctx, assetID)
+}
+
+// CreateAsset creates a new asset with optional tags
+func (s *FavouriteService) CreateAsset(ctx context.Context, assetType domain.AssetType, description string, data interface{}, tags []string) (*domain.Asset, error) {
 	asset, err := domain.NewAsset(assetType, description, data)
 	if err != nil {
 		return nil, err
 	}
+	if err := asset.SetTags(tags); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.CreateAsset(ctx, asset); err != nil {
 		return nil, err
 	}
 
+	s.outbox.Notify()
 	return asset, nil
 }
 
@@ -91,23 +242,237 @@ func (s *FavouriteService) UpdateAssetDescription(ctx context.Context, assetID u
 		return fmt.Errorf("description cannot be empty")
 	}
 
-	return s.repo.UpdateAssetDescription(ctx, assetID, description)
+	if err := s.repo.UpdateAssetDescription(ctx, assetID, description); err != nil {
+		return err
+	}
+	s.outbox.Notify()
+	return nil
 }
 
-// DeleteAsset deletes an asset
-func (s *FavouriteService) DeleteAsset(ctx context.Context, assetID uuid.UUID) error {
-	return s.repo.DeleteAsset(ctx, assetID)
+// DeleteAsset deletes an asset and returns the IDs of all deleted assets. Assets referenced by
+// others (audiences used by insights) are only deleted with DeleteCascade, which removes the
+// referencing assets too.
+func (s *FavouriteService) DeleteAsset(ctx context.Context, assetID uuid.UUID, policy domain.DeletePolicy) ([]uuid.UUID, error) {
+	deleted, err := s.repo.DeleteAsset(ctx, assetID, policy)
+	if err != nil {
+		return nil, err
+	}
+	s.outbox.Notify()
+	return deleted, nil
 }
 
 // ListAssets returns paginated list of all assets in the system
-func (s *FavouriteService) ListAssets(ctx context.Context, query *domain.PageQuery) ([]*domain.Asset, int, error) {
+func (s *FavouriteService) ListAssets(ctx context.Context, query *domain.PageQuery) ([]*domain.Asset, domain.PageInfo, error) {
 	cfg := config.Get()
 	// Business logic validation
 	if query.Limit > cfg.MaxPageItems {
 		query.Limit = cfg.MaxPageItems // Enforce maximum
 	}
 
-	return s.repo.ListAssets(ctx, query)
+	return listPage(query, func(probe *domain.PageQuery) ([]*domain.Asset, int, error) {
+		return s.repo.ListAssets(ctx, probe)
+	}, func(asset *domain.Asset) (string, uuid.UUID) {
+		return domain.AssetSortKey(asset, query.SortBy), asset.ID
+	})
+}
+
+// TrendingAssets returns the assets that gained the most favourites within a window up to now,
+// with the time the window starts at once aligned on activity buckets
+func (s *FavouriteService) TrendingAssets(ctx context.Context, window time.Duration, limit int) ([]domain.TrendingAsset, time.Time, error) {
+	if maxItems := config.Get().MaxPageItems; limit <= 0 || limit > maxItems {
+		limit = maxItems
+	}
+	since := time.Now().Add(-window).Truncate(domain.TrendingBucket)
+	assets, err := s.repo.TrendingAssets(ctx, since, limit)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return assets, since, nil
+}
+
+// Recommendations suggests assets to a user based on the assets favourited together with the user's favourites
+func (s *FavouriteService) Recommendations(ctx context.Context, userID uuid.UUID, limit int) ([]domain.Recommendation, error) {
+	if maxItems := config.Get().MaxPageItems; limit <= 0 || limit > maxItems {
+		limit = maxItems
+	}
+	return s.repo.Recommendations(ctx, userID, time.Now(), limit)
+}
+
+// listPage fetches a page of a listing plus one probe item, which tells whether the listing
+// continues past the page, and derives the cursors of the neighbouring pages from the items
+// at the page boundaries
+func listPage[T any](query *domain.PageQuery, list func(*domain.PageQuery) ([]T, int, error), key func(T) (string, uuid.UUID)) ([]T, domain.PageInfo, error) {
+	probe := *query
+	probe.Limit++
+	items, total, err := list(&probe)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+
+	info := domain.PageInfo{Total: total}
+	backward := query.Cursor != nil && query.Cursor.Direction == domain.CursorPrev
+	more := len(items) > query.Limit
+	if more {
+		if backward {
+			items = items[1:] // the probe precedes the page
+		} else {
+			items = items[:query.Limit]
+		}
+	}
+
+	cursor := func(key string, id uuid.UUID, direction domain.CursorDirection) string {
+		return domain.EncodeCursor(domain.Cursor{SortBy: query.SortBy, Order: query.Order, Key: key, ID: id, Direction: direction})
+	}
+
+	// An empty page past either end can still lead back the way it came
+	if len(items) == 0 {
+		if c := query.Cursor; c != nil && backward {
+			info.NextCursor = cursor(c.Key, c.ID, domain.CursorNext)
+		} else if c != nil {
+			info.PrevCursor = cursor(c.Key, c.ID, domain.CursorPrev)
+		}
+		return items, info, nil
+	}
+
+	hasNext, hasPrev := more, query.Cursor != nil || query.Offset > 0
+	if backward {
+		hasNext, hasPrev = true, more
+	}
+	if hasNext {
+		k, id := key(items[len(items)-1])
+		info.NextCursor = cursor(k, id, domain.CursorNext)
+	}
+	if hasPrev {
+		k, id := key(items[0])
+		info.PrevCursor = cursor(k, id, domain.CursorPrev)
+	}
+	return items, info, nil
+}
+
+// AddAssetTags tags an asset and returns the updated asset
+func (s *FavouriteService) AddAssetTags(ctx context.Context, assetID uuid.UUID, tags []string) (*domain.Asset, error) {
+	normalized, err := domain.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: no tags given", domain.ErrInvalidTag)
+	}
+	asset, err := s.repo.AddAssetTags(ctx, assetID, normalized)
+	if err != nil {
+		return nil, err
+	}
+	s.outbox.Notify()
+	return asset, nil
+}
+
+// RemoveAssetTag removes a tag from an asset and returns the updated asset
+func (s *FavouriteService) RemoveAssetTag(ctx context.Context, assetID uuid.UUID, tag string) (*domain.Asset, error) {
+	normalized, err := domain.NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	asset, err := s.repo.RemoveAssetTags(ctx, assetID, []string{normalized})
+	if err != nil {
+		return nil, err
+	}
+	s.outbox.Notify()
+	return asset, nil
+}
+
+// ListTags returns all tags in use with their asset counts
+func (s *FavouriteService) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	return s.repo.ListTags(ctx)
+}
+
+// maxSearchQueryLength bounds the length of full-text queries
+const maxSearchQueryLength = 256
+
+// SearchAssets runs a full-text search over all assets
+func (s *FavouriteService) SearchAssets(ctx context.Context, text string, query *domain.PageQuery) ([]*domain.Asset, int, error) {
+	if err := validateSearchQuery(text); err != nil {
+		return nil, 0, err
+	}
+	cfg := config.Get()
+	if query.Limit > cfg.MaxPageItems {
+		query.Limit = cfg.MaxPageItems // Enforce maximum
+	}
+
+	return s.repo.SearchAssets(ctx, text, query)
+}
+
+// SearchFavourites runs a full-text search over a user's favourites
+func (s *FavouriteService) SearchFavourites(ctx context.Context, userID uuid.UUID, text string, query *domain.PageQuery) ([]*domain.Favourite, int, error) {
+	if err := validateSearchQuery(text); err != nil {
+		return nil, 0, err
+	}
+	cfg := config.Get()
+	if query.Limit > cfg.MaxPageItems {
+		query.Limit = cfg.MaxPageItems // Enforce maximum
+	}
+
+	return s.repo.SearchFavourites(ctx, userID, text, query)
+}
+
+func validateSearchQuery(text string) error {
+	if len(text) > maxSearchQueryLength {
+		return fmt.Errorf("%w: query longer than %d characters", domain.ErrInvalidSearchQuery, maxSearchQueryLength)
+	}
+	if len(search.Tokenize(text)) == 0 {
+		return fmt.Errorf("%w: query has no searchable terms", domain.ErrInvalidSearchQuery)
+	}
+	return nil
+}
+
+// MatchAudience evaluates whether a respondent profile belongs to an audience asset
+func (s *FavouriteService) MatchAudience(ctx context.Context, assetID uuid.UUID, profile *domain.RespondentProfile) (bool, error) {
+	if err := profile.Validate(); err != nil {
+		return false, err
+	}
+
+	asset, err := s.repo.GetAsset(ctx, assetID)
+	if err != nil {
+		return false, err
+	}
+	if asset.Type != domain.AssetTypeAudience {
+		return false, fmt.Errorf("%w: %s is not an audience", domain.ErrAssetTypeMismatch, assetID)
+	}
+
+	audience, err := domain.ParseAudienceData(asset.Data)
+	if err != nil {
+		return false, err
+	}
+
+	return audience.Matches(profile), nil
+}
+
+// RenderAsset renders a chart asset as an image. Rendered output is cached per asset version
+// (UpdatedAt), format and size.
+func (s *FavouriteService) RenderAsset(ctx context.Context, assetID uuid.UUID, opts render.Options) ([]byte, error) {
+	asset, err := s.repo.GetAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset.Type != domain.AssetTypeChart {
+		return nil, fmt.Errorf("%w: only chart assets can be rendered", domain.ErrAssetTypeMismatch)
+	}
+
+	key := render.Key(asset.ID, asset.UpdatedAt, opts)
+	if image, ok := s.renderCache.Get(key); ok {
+		return image, nil
+	}
+
+	chart, err := domain.ParseChartData(asset.Data)
+	if err != nil {
+		return nil, err
+	}
+	image, err := render.Chart(chart, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.renderCache.Put(key, image)
+	return image, nil
 }
 
 // HealthCheck verifies service health
@@ -3,10 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gioannid/platform-go-challenge/internal/config"
 	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/render"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -46,6 +49,161 @@ func (m *MockRepository) IsFavourite(ctx context.Context, userID, assetID uuid.U
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockRepository) CreateCollection(ctx context.Context, collection *domain.Collection) error {
+	args := m.Called(ctx, collection)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetCollection(ctx context.Context, userID, collectionID uuid.UUID) (*domain.Collection, error) {
+	args := m.Called(ctx, userID, collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Collection), args.Error(1)
+}
+
+func (m *MockRepository) ListCollections(ctx context.Context, userID uuid.UUID) ([]*domain.Collection, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*domain.Collection), args.Error(1)
+}
+
+func (m *MockRepository) RenameCollection(ctx context.Context, userID, collectionID uuid.UUID, name string) (*domain.Collection, error) {
+	args := m.Called(ctx, userID, collectionID, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Collection), args.Error(1)
+}
+
+func (m *MockRepository) DeleteCollection(ctx context.Context, userID, collectionID uuid.UUID) error {
+	args := m.Called(ctx, userID, collectionID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddToCollection(ctx context.Context, userID, collectionID, favouriteID uuid.UUID) error {
+	args := m.Called(ctx, userID, collectionID, favouriteID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddFavouriteToCollection(ctx context.Context, favourite *domain.Favourite, collectionID uuid.UUID) (*domain.Favourite, error) {
+	args := m.Called(ctx, favourite, collectionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Favourite), args.Error(1)
+}
+
+func (m *MockRepository) RemoveFromCollection(ctx context.Context, userID, collectionID, favouriteID uuid.UUID) error {
+	args := m.Called(ctx, userID, collectionID, favouriteID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateShare(ctx context.Context, share *domain.Share) error {
+	args := m.Called(ctx, share)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetShare(ctx context.Context, shareID uuid.UUID) (*domain.Share, error) {
+	args := m.Called(ctx, shareID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Share), args.Error(1)
+}
+
+func (m *MockRepository) ListShares(ctx context.Context, ownerID uuid.UUID) ([]*domain.Share, error) {
+	args := m.Called(ctx, ownerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Share), args.Error(1)
+}
+
+func (m *MockRepository) ListSharedWith(ctx context.Context, userID uuid.UUID) ([]*domain.Share, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Share), args.Error(1)
+}
+
+func (m *MockRepository) RedeemShareLink(ctx context.Context, token string, userID uuid.UUID) (*domain.Share, error) {
+	args := m.Called(ctx, token, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Share), args.Error(1)
+}
+
+func (m *MockRepository) DeleteShare(ctx context.Context, ownerID, shareID uuid.UUID) error {
+	args := m.Called(ctx, ownerID, shareID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TrendingAssets(ctx context.Context, since time.Time, limit int) ([]domain.TrendingAsset, error) {
+	args := m.Called(ctx, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TrendingAsset), args.Error(1)
+}
+
+func (m *MockRepository) Recommendations(ctx context.Context, userID uuid.UUID, at time.Time, limit int) ([]domain.Recommendation, error) {
+	args := m.Called(ctx, userID, at, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Recommendation), args.Error(1)
+}
+
+func (m *MockRepository) FavouritedAssets(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) (map[uuid.UUID]uuid.UUID, error) {
+	args := m.Called(ctx, userID, assetIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) AddFavourites(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.BatchResult, error) {
+	args := m.Called(ctx, userID, assetIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BatchResult), args.Error(1)
+}
+
+func (m *MockRepository) RemoveFavourites(ctx context.Context, userID uuid.UUID, assetIDs []uuid.UUID) ([]domain.BatchResult, error) {
+	args := m.Called(ctx, userID, assetIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.BatchResult), args.Error(1)
+}
+
+func (m *MockRepository) UpdateFavourite(ctx context.Context, userID, favouriteID uuid.UUID, update domain.FavouriteUpdate) (*domain.Favourite, error) {
+	args := m.Called(ctx, userID, favouriteID, update)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Favourite), args.Error(1)
+}
+
+func (m *MockRepository) MoveFavourite(ctx context.Context, userID, favouriteID uuid.UUID, afterID, beforeID *uuid.UUID) (*domain.Favourite, error) {
+	args := m.Called(ctx, userID, favouriteID, afterID, beforeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Favourite), args.Error(1)
+}
+
+func (m *MockRepository) SetFavouritePinned(ctx context.Context, userID, favouriteID uuid.UUID, pinned bool) (*domain.Favourite, error) {
+	args := m.Called(ctx, userID, favouriteID, pinned)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Favourite), args.Error(1)
+}
+
 func (m *MockRepository) GetAsset(ctx context.Context, assetID uuid.UUID) (*domain.Asset, error) {
 	args := m.Called(ctx, assetID)
 	if args.Get(0) == nil {
@@ -64,9 +222,52 @@ func (m *MockRepository) UpdateAssetDescription(ctx context.Context, assetID uui
 	return args.Error(0)
 }
 
-func (m *MockRepository) DeleteAsset(ctx context.Context, assetID uuid.UUID) error {
-	args := m.Called(ctx, assetID)
-	return args.Error(0)
+func (m *MockRepository) DeleteAsset(ctx context.Context, assetID uuid.UUID, policy domain.DeletePolicy) ([]uuid.UUID, error) {
+	args := m.Called(ctx, assetID, policy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) AddAssetTags(ctx context.Context, assetID uuid.UUID, tags []string) (*domain.Asset, error) {
+	args := m.Called(ctx, assetID, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Asset), args.Error(1)
+}
+
+func (m *MockRepository) RemoveAssetTags(ctx context.Context, assetID uuid.UUID, tags []string) (*domain.Asset, error) {
+	args := m.Called(ctx, assetID, tags)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Asset), args.Error(1)
+}
+
+func (m *MockRepository) ListTags(ctx context.Context) ([]domain.TagCount, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.TagCount), args.Error(1)
+}
+
+func (m *MockRepository) SearchAssets(ctx context.Context, text string, query *domain.PageQuery) ([]*domain.Asset, int, error) {
+	args := m.Called(ctx, text, query)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Asset), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) SearchFavourites(ctx context.Context, userID uuid.UUID, text string, query *domain.PageQuery) ([]*domain.Favourite, int, error) {
+	args := m.Called(ctx, userID, text, query)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Favourite), args.Int(1), args.Error(2)
 }
 
 // ListAssets mocks the ListAssets method
@@ -77,6 +278,74 @@ func (m *MockRepository) ListAssets(ctx context.Context, query *domain.PageQuery
 	}
 	return args.Get(0).([]*domain.Asset), args.Int(1), args.Error(2)
 }
+func (m *MockRepository) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	args := m.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetWebhook(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error) {
+	args := m.Called(ctx, webhookID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Webhook), args.Error(1)
+}
+
+func (m *MockRepository) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	args := m.Called(ctx, webhookID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, deliveryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockRepository) ListDeliveries(ctx context.Context, webhookID uuid.UUID, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, webhookID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockRepository) PendingEvents(ctx context.Context, limit int) ([]domain.Event, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *MockRepository) AckEvents(ctx context.Context, lastEventID uint64) error {
+	args := m.Called(ctx, lastEventID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) ListAudit(ctx context.Context, query *domain.AuditQuery) ([]*domain.AuditEntry, int, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.AuditEntry), args.Int(1), args.Error(2)
+}
+
 func (m *MockRepository) Ping(ctx context.Context) error {
 	args := m.Called(ctx)
 	return args.Error(0)
@@ -161,14 +430,16 @@ func TestFavouriteService_ListFavourites(t *testing.T) {
 	}
 	query := domain.NewPageQuery(100, 0, "created_at", "desc")
 
-	mockRepo.On("ListFavourites", ctx, userID, query).Return(expectedFavs, 1, nil)
+	mockRepo.On("ListFavourites", ctx, userID, probeOf(query)).Return(expectedFavs, 1, nil)
 
 	svc := NewFavouriteService(mockRepo)
-	favs, total, err := svc.ListFavourites(ctx, userID, query)
+	favs, page, err := svc.ListFavourites(ctx, userID, query)
 
 	require.NoError(t, err)
 	assert.Equal(t, expectedFavs, favs)
-	assert.Equal(t, 1, total)
+	assert.Equal(t, 1, page.Total)
+	assert.Empty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -180,9 +451,9 @@ func TestFavouriteService_ListFavourites_LimitEnforcement(t *testing.T) {
 	mockRepo := new(MockRepository)
 	query := domain.NewPageQuery(cfg.MaxPageItems*2, 0, "created_at", "desc")
 
-	// Mock should be called with enforced limit of 1000
+	// Mock should be called with the enforced limit plus the probe item
 	mockRepo.On("ListFavourites", ctx, userID, mock.MatchedBy(func(q *domain.PageQuery) bool {
-		return q.Limit == cfg.MaxPageItems
+		return q.Limit == cfg.MaxPageItems+1
 	})).Return([]*domain.Favourite{}, 0, nil)
 
 	svc := NewFavouriteService(mockRepo)
@@ -200,6 +471,8 @@ func TestFavouriteService_CreateAsset(t *testing.T) {
 		assetType   domain.AssetType
 		description string
 		data        interface{}
+		tags        []string
+		wantTags    []string
 		wantErr     bool
 	}{
 		{
@@ -214,6 +487,22 @@ func TestFavouriteService_CreateAsset(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:        "tags are normalized",
+			assetType:   domain.AssetTypeInsight,
+			description: "Tagged Insight",
+			data:        domain.InsightData{Text: "text"},
+			tags:        []string{" Social Media ", "gen-z", "social media"},
+			wantTags:    []string{"gen-z", "social-media"},
+		},
+		{
+			name:        "invalid tag",
+			assetType:   domain.AssetTypeInsight,
+			description: "Tagged Insight",
+			data:        domain.InsightData{Text: "text"},
+			tags:        []string{"#hashtag"},
+			wantErr:     true,
+		},
 		{
 			name:        "invalid chart data",
 			assetType:   domain.AssetTypeChart,
@@ -234,7 +523,7 @@ func TestFavouriteService_CreateAsset(t *testing.T) {
 			}
 
 			svc := NewFavouriteService(mockRepo)
-			asset, err := svc.CreateAsset(ctx, tt.assetType, tt.description, tt.data)
+			asset, err := svc.CreateAsset(ctx, tt.assetType, tt.description, tt.data, tt.tags)
 
 			if tt.wantErr {
 				require.Error(t, err)
@@ -244,6 +533,7 @@ func TestFavouriteService_CreateAsset(t *testing.T) {
 				require.NotNil(t, asset)
 				assert.Equal(t, tt.assetType, asset.Type)
 				assert.Equal(t, tt.description, asset.Description)
+				assert.Equal(t, tt.wantTags, asset.Tags)
 				mockRepo.AssertExpectations(t)
 			}
 		})
@@ -307,17 +597,140 @@ func TestFavouriteService_RemoveFavourite(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestFavouriteService_AddFavourites(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	assetIDs := []uuid.UUID{uuid.New(), uuid.New()}
+
+	mockRepo := new(MockRepository)
+	results := []domain.BatchResult{
+		{AssetID: assetIDs[0], Status: domain.BatchCreated},
+		{AssetID: assetIDs[1], Status: domain.BatchAssetNotFound},
+	}
+	mockRepo.On("AddFavourites", ctx, userID, assetIDs).Return(results, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	got, err := svc.AddFavourites(ctx, userID, assetIDs)
+	require.NoError(t, err)
+	assert.Equal(t, results, got)
+
+	// Empty and oversized batches never reach the repository
+	_, err = svc.AddFavourites(ctx, userID, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+	_, err = svc.RemoveFavourites(ctx, userID, make([]uuid.UUID, config.Get().MaxBatchItems+1))
+	assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "RemoveFavourites", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFavouriteService_FavouriteStatuses(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	favourited, other, favID := uuid.New(), uuid.New(), uuid.New()
+	assetIDs := []uuid.UUID{other, favourited}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("FavouritedAssets", ctx, userID, assetIDs).Return(map[uuid.UUID]uuid.UUID{favourited: favID}, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	statuses, err := svc.FavouriteStatuses(ctx, userID, assetIDs)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.FavouriteStatus{
+		{AssetID: other},
+		{AssetID: favourited, Favourited: true, FavouriteID: &favID},
+	}, statuses)
+
+	_, err = svc.FavouriteStatuses(ctx, userID, nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidBatch)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFavouriteService_UpdateFavourite(t *testing.T) {
+	ctx := context.Background()
+	userID, favouriteID := uuid.New(), uuid.New()
+
+	t.Run("normalizes the annotations", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("UpdateFavourite", ctx, userID, favouriteID, mock.MatchedBy(func(u domain.FavouriteUpdate) bool {
+			return *u.Title == "Board deck" && u.Note == nil
+		})).Return(&domain.Favourite{ID: favouriteID, Title: "Board deck"}, nil)
+
+		svc := NewFavouriteService(mockRepo)
+		title := "  Board   deck "
+		fav, err := svc.UpdateFavourite(ctx, userID, favouriteID, domain.FavouriteUpdate{Title: &title})
+		require.NoError(t, err)
+		assert.Equal(t, "Board deck", fav.Title)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects long notes", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		svc := NewFavouriteService(mockRepo)
+		note := strings.Repeat("x", 2001)
+		_, err := svc.UpdateFavourite(ctx, userID, favouriteID, domain.FavouriteUpdate{Note: &note})
+		assert.ErrorIs(t, err, domain.ErrInvalidAnnotation)
+		mockRepo.AssertNotCalled(t, "UpdateFavourite", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFavouriteService_MoveFavourite(t *testing.T) {
+	ctx := context.Background()
+	userID, favouriteID, neighbourID := uuid.New(), uuid.New(), uuid.New()
+
+	t.Run("moves after the neighbour", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		moved := &domain.Favourite{ID: favouriteID, UserID: userID, Position: "a0V"}
+		mockRepo.On("MoveFavourite", ctx, userID, favouriteID, &neighbourID, (*uuid.UUID)(nil)).Return(moved, nil)
+
+		svc := NewFavouriteService(mockRepo)
+		result, err := svc.MoveFavourite(ctx, userID, favouriteID, &neighbourID, nil)
+		require.NoError(t, err)
+		assert.Equal(t, moved, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("requires exactly one neighbour", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		svc := NewFavouriteService(mockRepo)
+
+		_, err := svc.MoveFavourite(ctx, userID, favouriteID, nil, nil)
+		assert.ErrorIs(t, err, domain.ErrInvalidPosition)
+		_, err = svc.MoveFavourite(ctx, userID, favouriteID, &neighbourID, &neighbourID)
+		assert.ErrorIs(t, err, domain.ErrInvalidPosition)
+		mockRepo.AssertNotCalled(t, "MoveFavourite", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFavouriteService_PinFavourite(t *testing.T) {
+	ctx := context.Background()
+	userID, favouriteID := uuid.New(), uuid.New()
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("SetFavouritePinned", ctx, userID, favouriteID, true).Return(&domain.Favourite{ID: favouriteID, Pinned: true}, nil)
+	mockRepo.On("SetFavouritePinned", ctx, userID, favouriteID, false).Return(nil, domain.ErrNotFound)
+
+	svc := NewFavouriteService(mockRepo)
+	pinned, err := svc.PinFavourite(ctx, userID, favouriteID)
+	require.NoError(t, err)
+	assert.True(t, pinned.Pinned)
+
+	_, err = svc.UnpinFavourite(ctx, userID, favouriteID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestFavouriteService_DeleteAsset(t *testing.T) {
 	ctx := context.Background()
 	assetID := uuid.New()
 
 	mockRepo := new(MockRepository)
-	mockRepo.On("DeleteAsset", ctx, assetID).Return(nil)
+	mockRepo.On("DeleteAsset", ctx, assetID, domain.DeleteRestrict).Return([]uuid.UUID{assetID}, nil)
 
 	svc := NewFavouriteService(mockRepo)
-	err := svc.DeleteAsset(ctx, assetID)
+	deleted, err := svc.DeleteAsset(ctx, assetID, domain.DeleteRestrict)
 
 	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{assetID}, deleted)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -334,6 +747,38 @@ func TestFavouriteService_HealthCheck(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestFavouriteService_TrendingAssets(t *testing.T) {
+	ctx := context.Background()
+	trending := []domain.TrendingAsset{{Asset: createTestAsset(t, domain.AssetTypeChart, uuid.New()), Added: 2, Net: 2}}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("TrendingAssets", ctx, mock.MatchedBy(func(since time.Time) bool {
+		return since.Equal(since.Truncate(domain.TrendingBucket)) && time.Since(since) >= 7*24*time.Hour
+	}), config.Get().MaxPageItems).Return(trending, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	got, since, err := svc.TrendingAssets(ctx, 7*24*time.Hour, 0)
+	require.NoError(t, err)
+	assert.Equal(t, trending, got)
+	assert.WithinDuration(t, time.Now().Add(-7*24*time.Hour), since, domain.TrendingBucket)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFavouriteService_Recommendations(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	recommendations := []domain.Recommendation{{Asset: createTestAsset(t, domain.AssetTypeChart, uuid.New()), Score: 0.5}}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("Recommendations", ctx, userID, mock.AnythingOfType("time.Time"), config.Get().MaxPageItems).Return(recommendations, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	got, err := svc.Recommendations(ctx, userID, config.Get().MaxPageItems+1)
+	require.NoError(t, err)
+	assert.Equal(t, recommendations, got)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestFavouriteService_ListAssets(t *testing.T) {
 	ctx := context.Background()
 
@@ -355,12 +800,12 @@ func TestFavouriteService_ListAssets(t *testing.T) {
 		expectedAssets := []*domain.Asset{asset1, asset2}
 		query := domain.NewPageQuery(20, 0, "created_at", "desc")
 
-		mockRepo.On("ListAssets", ctx, query).Return(expectedAssets, 2, nil)
+		mockRepo.On("ListAssets", ctx, probeOf(query)).Return(expectedAssets, 2, nil)
 
-		assets, total, err := service.ListAssets(ctx, query)
+		assets, page, err := service.ListAssets(ctx, query)
 
 		assert.NoError(t, err)
-		assert.Equal(t, 2, total)
+		assert.Equal(t, 2, page.Total)
 		assert.Equal(t, 2, len(assets))
 		assert.Equal(t, asset1.ID, assets[0].ID)
 		assert.Equal(t, asset2.ID, assets[1].ID)
@@ -372,12 +817,12 @@ func TestFavouriteService_ListAssets(t *testing.T) {
 		service := NewFavouriteService(mockRepo)
 
 		query := domain.NewPageQuery(20, 0, "created_at", "desc")
-		mockRepo.On("ListAssets", ctx, query).Return([]*domain.Asset{}, 0, nil)
+		mockRepo.On("ListAssets", ctx, probeOf(query)).Return([]*domain.Asset{}, 0, nil)
 
-		assets, total, err := service.ListAssets(ctx, query)
+		assets, page, err := service.ListAssets(ctx, query)
 
 		assert.NoError(t, err)
-		assert.Equal(t, 0, total)
+		assert.Equal(t, 0, page.Total)
 		assert.Equal(t, 0, len(assets))
 		mockRepo.AssertExpectations(t)
 	})
@@ -391,7 +836,7 @@ func TestFavouriteService_ListAssets(t *testing.T) {
 
 		// The service should enforce the max limit
 		expectedQuery := domain.NewPageQuery(1000, 0, "created_at", "desc")
-		mockRepo.On("ListAssets", ctx, expectedQuery).Return([]*domain.Asset{}, 0, nil)
+		mockRepo.On("ListAssets", ctx, probeOf(expectedQuery)).Return([]*domain.Asset{}, 0, nil)
 
 		_, _, err := service.ListAssets(ctx, query)
 
@@ -405,14 +850,14 @@ func TestFavouriteService_ListAssets(t *testing.T) {
 
 		query := domain.NewPageQuery(20, 0, "created_at", "desc")
 		expectedErr := errors.New("database connection failed")
-		mockRepo.On("ListAssets", ctx, query).Return(nil, 0, expectedErr)
+		mockRepo.On("ListAssets", ctx, probeOf(query)).Return(nil, 0, expectedErr)
 
-		assets, total, err := service.ListAssets(ctx, query)
+		assets, page, err := service.ListAssets(ctx, query)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 		assert.Nil(t, assets)
-		assert.Equal(t, 0, total)
+		assert.Equal(t, 0, page.Total)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -422,22 +867,192 @@ func TestFavouriteService_ListAssets(t *testing.T) {
 
 		asset3, _ := domain.NewAsset(domain.AssetTypeAudience, "Audience 3", domain.AudienceData{
 			Gender:             "Female",
-			BirthCountry:       "UK",
-			AgeGroups:          []string{"25-34"},
-			HoursSocialDaily:   4.5,
-			PurchasesLastMonth: 3,
+			BirthCountry:       "GB",
+			AgeGroups:          []domain.AgeGroup{"25-34"},
+			HoursSocialDaily:   domain.AtLeast(4.5),
+			PurchasesLastMonth: domain.AtLeast(3),
 		})
 
 		expectedAssets := []*domain.Asset{asset3}
 		query := domain.NewPageQuery(10, 20, "created_at", "desc")
 
-		mockRepo.On("ListAssets", ctx, query).Return(expectedAssets, 25, nil)
+		mockRepo.On("ListAssets", ctx, probeOf(query)).Return(expectedAssets, 25, nil)
 
-		assets, total, err := service.ListAssets(ctx, query)
+		assets, page, err := service.ListAssets(ctx, query)
 
 		assert.NoError(t, err)
-		assert.Equal(t, 25, total)
+		assert.Equal(t, 25, page.Total)
 		assert.Equal(t, 1, len(assets))
+		assert.Empty(t, page.NextCursor, "the last page has no successor")
+		assert.NotEmpty(t, page.PrevCursor)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("cursors", func(t *testing.T) {
+		assets := make([]*domain.Asset, 4)
+		for i := range assets {
+			assets[i] = createTestAsset(t, domain.AssetTypeChart, uuid.New())
+			assets[i].CreatedAt = time.Date(2025, 1, 4-i, 0, 0, 0, 0, time.UTC)
+		}
+
+		// First page: the probe item reveals a next page
+		mockRepo := new(MockRepository)
+		service := NewFavouriteService(mockRepo)
+		query := domain.NewPageQuery(2, 0, "created_at", "desc")
+		mockRepo.On("ListAssets", ctx, probeOf(query)).Return(assets[:3], 4, nil)
+
+		page1, info, err := service.ListAssets(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, assets[:2], page1)
+		assert.Empty(t, info.PrevCursor)
+		require.NotEmpty(t, info.NextCursor)
+
+		next, err := domain.DecodeCursor(info.NextCursor)
+		require.NoError(t, err)
+		assert.Equal(t, domain.CursorNext, next.Direction)
+		assert.Equal(t, assets[1].ID, next.ID)
+		assert.Equal(t, domain.AssetSortKey(assets[1], "created_at"), next.Key)
+
+		// Walking backwards from the third item: the probe precedes the page
+		query = domain.NewPageQuery(1, 0, "", "")
+		require.NoError(t, query.SetCursor(domain.EncodeCursor(domain.Cursor{
+			SortBy: "created_at", Order: "desc",
+			Key: domain.AssetSortKey(assets[2], "created_at"), ID: assets[2].ID, Direction: domain.CursorPrev,
+		})))
+		mockRepo = new(MockRepository)
+		service = NewFavouriteService(mockRepo)
+		mockRepo.On("ListAssets", ctx, probeOf(query)).Return(assets[:2], 4, nil)
+
+		page, info, err := service.ListAssets(ctx, query)
+		require.NoError(t, err)
+		assert.Equal(t, assets[1:2], page)
+		assert.NotEmpty(t, info.NextCursor)
+		prev, err := domain.DecodeCursor(info.PrevCursor)
+		require.NoError(t, err)
+		assert.Equal(t, domain.CursorPrev, prev.Direction)
+		assert.Equal(t, assets[1].ID, prev.ID)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+// probeOf returns the query the repository receives for a page: one item more than requested
+func probeOf(query *domain.PageQuery) *domain.PageQuery {
+	probe := *query
+	probe.Limit++
+	return &probe
+}
+
+func TestFavouriteService_MatchAudience(t *testing.T) {
+	ctx := context.Background()
+	assetID := uuid.New()
+
+	audience, err := domain.NewAsset(domain.AssetTypeAudience, "Heavy social users", domain.AudienceData{
+		Gender:           domain.GenderMale,
+		HoursSocialDaily: domain.MoreThan(3),
+	})
+	require.NoError(t, err)
+	audience.ID = assetID
+
+	profile := &domain.RespondentProfile{Gender: domain.GenderMale, BirthCountry: "GB", Age: 30, HoursSocialDaily: 4}
+
+	tests := []struct {
+		name    string
+		profile *domain.RespondentProfile
+		setup   func(*MockRepository)
+		want    bool
+		wantErr error
+	}{
+		{
+			name:    "matching profile",
+			profile: profile,
+			setup: func(m *MockRepository) {
+				m.On("GetAsset", ctx, assetID).Return(audience, nil)
+			},
+			want: true,
+		},
+		{
+			name:    "non matching profile",
+			profile: &domain.RespondentProfile{Gender: domain.GenderFemale, BirthCountry: "GB", Age: 30, HoursSocialDaily: 4},
+			setup: func(m *MockRepository) {
+				m.On("GetAsset", ctx, assetID).Return(audience, nil)
+			},
+			want: false,
+		},
+		{
+			name:    "invalid profile",
+			profile: &domain.RespondentProfile{Gender: "unknown"},
+			setup:   func(m *MockRepository) {},
+			wantErr: domain.ErrInvalidRespondentProfile,
+		},
+		{
+			name:    "not an audience",
+			profile: profile,
+			setup: func(m *MockRepository) {
+				m.On("GetAsset", ctx, assetID).Return(createTestAsset(t, domain.AssetTypeChart, assetID), nil)
+			},
+			wantErr: domain.ErrAssetTypeMismatch,
+		},
+		{
+			name:    "asset not found",
+			profile: profile,
+			setup: func(m *MockRepository) {
+				m.On("GetAsset", ctx, assetID).Return(nil, domain.ErrNotFound)
+			},
+			wantErr: domain.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			tt.setup(mockRepo)
+
+			svc := NewFavouriteService(mockRepo)
+			matched, err := svc.MatchAudience(ctx, assetID, tt.profile)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, matched)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFavouriteService_RenderAsset(t *testing.T) {
+	ctx := context.Background()
+	assetID := uuid.New()
+	opts, err := render.NewOptions("svg", 0, 0)
+	require.NoError(t, err)
+
+	t.Run("renders once per asset version", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetAsset", ctx, assetID).Return(createTestAsset(t, domain.AssetTypeChart, assetID), nil).Twice()
+
+		svc := NewFavouriteService(mockRepo)
+		first, err := svc.RenderAsset(ctx, assetID, opts)
+		require.NoError(t, err)
+		assert.Contains(t, string(first), "Test Chart")
+
+		second, err := svc.RenderAsset(ctx, assetID, opts)
+		require.NoError(t, err)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 1, svc.renderCache.Len())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("not a chart", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		insight, err := domain.NewAsset(domain.AssetTypeInsight, "Insight", domain.InsightData{Text: "text"})
+		require.NoError(t, err)
+		insight.ID = assetID
+		mockRepo.On("GetAsset", ctx, assetID).Return(insight, nil)
+
+		svc := NewFavouriteService(mockRepo)
+		_, err = svc.RenderAsset(ctx, assetID, opts)
+		assert.ErrorIs(t, err, domain.ErrAssetTypeMismatch)
 		mockRepo.AssertExpectations(t)
 	})
 }
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gioannid/platform-go-challenge/internal/config"
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ShareFavourites shares the owner's favourites, or one of the owner's collections, with other
+// users and, with link set, through a link token anyone can redeem
+func (s *FavouriteService) ShareFavourites(ctx context.Context, ownerID uuid.UUID, collectionID *uuid.UUID, userIDs []uuid.UUID, link bool, permission domain.SharePermission) (*domain.Share, error) {
+	share, err := domain.NewShare(ownerID, collectionID, userIDs, link, permission)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateShare(ctx, share); err != nil {
+		return nil, err
+	}
+	return share, nil
+}
+
+// ListShares returns the shares a user created
+func (s *FavouriteService) ListShares(ctx context.Context, ownerID uuid.UUID) ([]*domain.Share, error) {
+	return s.repo.ListShares(ctx, ownerID)
+}
+
+// RevokeShare deletes one of a user's shares, so that neither its recipients nor its link give access any more
+func (s *FavouriteService) RevokeShare(ctx context.Context, ownerID, shareID uuid.UUID) error {
+	return s.repo.DeleteShare(ctx, ownerID, shareID)
+}
+
+// SharedWithMe returns the shares granted to a user
+func (s *FavouriteService) SharedWithMe(ctx context.Context, userID uuid.UUID) ([]*domain.Share, error) {
+	shares, err := s.repo.ListSharedWith(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i, share := range shares {
+		shares[i] = share.RecipientView()
+	}
+	return shares, nil
+}
+
+// RedeemShareLink grants a user access to the share behind a link token
+func (s *FavouriteService) RedeemShareLink(ctx context.Context, userID uuid.UUID, token string) (*domain.Share, error) {
+	share, err := s.repo.RedeemShareLink(ctx, token, userID)
+	if err != nil {
+		return nil, err
+	}
+	return share.RecipientView(), nil
+}
+
+// ListSharedFavourites returns a page of the favourites shared with a user, with the same
+// pagination, sorting and filtering as ListFavourites
+func (s *FavouriteService) ListSharedFavourites(ctx context.Context, userID, shareID uuid.UUID, query *domain.PageQuery) ([]*domain.Favourite, domain.PageInfo, error) {
+	share, err := s.sharedAccess(ctx, userID, shareID, false)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	query.CollectionID = share.CollectionID
+	return s.ListFavourites(ctx, share.OwnerID, query)
+}
+
+// AddSharedFavourite favourites an asset on behalf of the owner of an editable share. With a
+// collection share, the owner's favourite of the asset (new or existing) is put into the
+// collection in the same repository operation, and ErrAlreadyExists reports an asset already in
+// there.
+func (s *FavouriteService) AddSharedFavourite(ctx context.Context, userID, shareID, assetID uuid.UUID) (*domain.Favourite, error) {
+	share, err := s.sharedAccess(ctx, userID, shareID, true)
+	if err != nil {
+		return nil, err
+	}
+	if share.CollectionID == nil {
+		return s.AddFavourite(ctx, share.OwnerID, assetID)
+	}
+
+	fav, err := s.repo.AddFavouriteToCollection(ctx, domain.NewFavourite(share.OwnerID, assetID), *share.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+	s.outbox.Notify()
+	return fav, nil
+}
+
+// RemoveSharedFavourite removes a favourite on behalf of the owner of an editable share. With a
+// collection share the favourite is only taken out of the collection.
+func (s *FavouriteService) RemoveSharedFavourite(ctx context.Context, userID, shareID, favouriteID uuid.UUID) error {
+	share, err := s.sharedAccess(ctx, userID, shareID, true)
+	if err != nil {
+		return err
+	}
+	if share.CollectionID != nil {
+		return s.repo.RemoveFromCollection(ctx, share.OwnerID, *share.CollectionID, favouriteID)
+	}
+	return s.RemoveFavourite(ctx, share.OwnerID, favouriteID)
+}
+
+// CopySharedFavourites favourites, for a user, all the assets of the favourites shared with them,
+// in the owner's order. Assets the user has favourited already are reported as already existing.
+// Shares of any size are copied, in batches of at most MaxBatchItems favourites, each applied on
+// its own: should one fail, the batches before it stay copied.
+func (s *FavouriteService) CopySharedFavourites(ctx context.Context, userID, shareID uuid.UUID) ([]domain.BatchResult, error) {
+	share, err := s.sharedAccess(ctx, userID, shareID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer s.outbox.Notify()
+
+	results := []domain.BatchResult{}
+	limit := config.Get().MaxBatchItems
+	for offset, done := 0, false; !done; {
+		var assetIDs []uuid.UUID
+		for len(assetIDs) < limit {
+			query := domain.NewPageQuery(limit-len(assetIDs), offset, "position", "asc")
+			query.CollectionID = share.CollectionID
+			favs, total, err := s.repo.ListFavourites(ctx, share.OwnerID, query)
+			if err != nil {
+				return nil, err
+			}
+			for _, fav := range favs {
+				assetIDs = append(assetIDs, fav.AssetID)
+			}
+			offset += len(favs)
+			if done = len(favs) == 0 || offset >= total; done {
+				break
+			}
+		}
+		if len(assetIDs) == 0 {
+			break
+		}
+		batch, err := s.repo.AddFavourites(ctx, userID, assetIDs)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+// sharedAccess returns a share granted to a user, with ErrNotFound for shares the user may not see
+// and ErrForbidden when edit access is needed but the share is read only
+func (s *FavouriteService) sharedAccess(ctx context.Context, userID, shareID uuid.UUID, edit bool) (*domain.Share, error) {
+	share, err := s.repo.GetShare(ctx, shareID)
+	if err != nil {
+		return nil, err
+	}
+	if !share.GrantedTo(userID) {
+		return nil, domain.ErrNotFound
+	}
+	if edit && !share.CanEdit() {
+		return nil, domain.ErrForbidden
+	}
+	return share, nil
+}
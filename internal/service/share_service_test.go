@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/config"
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavouriteService_SharedAccess(t *testing.T) {
+	ctx := context.Background()
+	ownerID, userID, strangerID := uuid.New(), uuid.New(), uuid.New()
+	share, err := domain.NewShare(ownerID, nil, []uuid.UUID{userID}, false, domain.SharePermissionRead)
+	require.NoError(t, err)
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetShare", ctx, share.ID).Return(share, nil)
+	svc := NewFavouriteService(mockRepo)
+
+	// Read only shares cannot be edited, and strangers do not get to know the share exists
+	_, err = svc.AddSharedFavourite(ctx, userID, share.ID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	assert.ErrorIs(t, svc.RemoveSharedFavourite(ctx, userID, share.ID, uuid.New()), domain.ErrForbidden)
+	_, _, err = svc.ListSharedFavourites(ctx, strangerID, share.ID, domain.NewPageQuery(10, 0, "", ""))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = svc.CopySharedFavourites(ctx, ownerID, share.ID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	mockRepo.AssertNotCalled(t, "AddFavourite", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "RemoveFavourite", mock.Anything, mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "ListFavourites", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFavouriteService_ListSharedFavourites(t *testing.T) {
+	ctx := context.Background()
+	ownerID, userID, collectionID := uuid.New(), uuid.New(), uuid.New()
+	share, err := domain.NewShare(ownerID, &collectionID, []uuid.UUID{userID}, false, "")
+	require.NoError(t, err)
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetShare", ctx, share.ID).Return(share, nil)
+	mockRepo.On("ListFavourites", ctx, ownerID, mock.MatchedBy(func(q *domain.PageQuery) bool {
+		return q.CollectionID != nil && *q.CollectionID == collectionID
+	})).Return([]*domain.Favourite{}, 0, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	_, page, err := svc.ListSharedFavourites(ctx, userID, share.ID, domain.NewPageQuery(10, 0, "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, 0, page.Total)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFavouriteService_AddSharedFavourite_Collection(t *testing.T) {
+	ctx := context.Background()
+	ownerID, userID, collectionID, assetID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	share, err := domain.NewShare(ownerID, &collectionID, []uuid.UUID{userID}, false, domain.SharePermissionEdit)
+	require.NoError(t, err)
+	added := domain.NewFavourite(ownerID, assetID)
+	added.CollectionIDs = []uuid.UUID{collectionID}
+
+	// The owner's favourite is added and put into the collection in a single repository call
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetShare", ctx, share.ID).Return(share, nil)
+	mockRepo.On("AddFavouriteToCollection", ctx, mock.MatchedBy(func(fav *domain.Favourite) bool {
+		return fav.UserID == ownerID && fav.AssetID == assetID
+	}), collectionID).Return(added, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	got, err := svc.AddSharedFavourite(ctx, userID, share.ID, assetID)
+	require.NoError(t, err)
+	assert.Equal(t, added, got)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "AddFavourite", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "AddToCollection", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFavouriteService_CopySharedFavourites(t *testing.T) {
+	ctx := context.Background()
+	ownerID, userID := uuid.New(), uuid.New()
+	share, err := domain.NewShare(ownerID, nil, []uuid.UUID{userID}, false, "")
+	require.NoError(t, err)
+	shared := []*domain.Favourite{domain.NewFavourite(ownerID, uuid.New()), domain.NewFavourite(ownerID, uuid.New())}
+	assetIDs := []uuid.UUID{shared[0].AssetID, shared[1].AssetID}
+	results := []domain.BatchResult{
+		{AssetID: assetIDs[0], Status: domain.BatchCreated},
+		{AssetID: assetIDs[1], Status: domain.BatchAlreadyExists},
+	}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetShare", ctx, share.ID).Return(share, nil)
+	mockRepo.On("ListFavourites", ctx, ownerID, mock.MatchedBy(func(q *domain.PageQuery) bool {
+		return q.SortBy == "position" && q.Offset == 0
+	})).Return(shared, 2, nil)
+	mockRepo.On("AddFavourites", ctx, userID, assetIDs).Return(results, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	got, err := svc.CopySharedFavourites(ctx, userID, share.ID)
+	require.NoError(t, err)
+	assert.Equal(t, results, got)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFavouriteService_CopySharedFavourites_Batches(t *testing.T) {
+	cfg := config.Get()
+	defer func(limit int) { cfg.MaxBatchItems = limit }(cfg.MaxBatchItems)
+	cfg.MaxBatchItems = 2
+
+	ctx := context.Background()
+	ownerID, userID := uuid.New(), uuid.New()
+	share, err := domain.NewShare(ownerID, nil, []uuid.UUID{userID}, false, "")
+	require.NoError(t, err)
+	shared := make([]*domain.Favourite, 5)
+	assetIDs := make([]uuid.UUID, len(shared))
+	for i := range shared {
+		shared[i] = domain.NewFavourite(ownerID, uuid.New())
+		assetIDs[i] = shared[i].AssetID
+	}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetShare", ctx, share.ID).Return(share, nil)
+	var want []domain.BatchResult
+	for offset := 0; offset < len(shared); offset += cfg.MaxBatchItems {
+		end := min(offset+cfg.MaxBatchItems, len(shared))
+		mockRepo.On("ListFavourites", ctx, ownerID, mock.MatchedBy(func(q *domain.PageQuery) bool {
+			return q.Offset == offset && q.Limit == cfg.MaxBatchItems
+		})).Return(shared[offset:end], len(shared), nil).Once()
+		batch := make([]domain.BatchResult, end-offset)
+		for i := range batch {
+			batch[i] = domain.BatchResult{AssetID: assetIDs[offset+i], Status: domain.BatchCreated}
+		}
+		mockRepo.On("AddFavourites", ctx, userID, assetIDs[offset:end]).Return(batch, nil).Once()
+		want = append(want, batch...)
+	}
+
+	// Shares larger than a batch are copied batch by batch, with the results merged
+	svc := NewFavouriteService(mockRepo)
+	got, err := svc.CopySharedFavourites(ctx, userID, share.ID)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFavouriteService_SharedWithMe(t *testing.T) {
+	ctx := context.Background()
+	ownerID, userID := uuid.New(), uuid.New()
+	share, err := domain.NewShare(ownerID, nil, []uuid.UUID{userID, uuid.New()}, true, "")
+	require.NoError(t, err)
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("ListSharedWith", ctx, userID).Return([]*domain.Share{share}, nil)
+
+	svc := NewFavouriteService(mockRepo)
+	shares, err := svc.SharedWithMe(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, shares, 1)
+	assert.Equal(t, share.ID, shares[0].ID)
+	assert.Empty(t, shares[0].Token, "link tokens are for the owner only")
+	assert.Empty(t, shares[0].UserIDs)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CreateWebhook subscribes a URL to events of the given types, signing deliveries with the secret
+func (s *FavouriteService) CreateWebhook(ctx context.Context, url string, eventTypes []domain.EventType, secret string) (*domain.Webhook, error) {
+	webhook, err := domain.NewWebhook(url, eventTypes, secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// GetWebhook returns a webhook
+func (s *FavouriteService) GetWebhook(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error) {
+	return s.repo.GetWebhook(ctx, webhookID)
+}
+
+// ListWebhooks returns all webhooks, oldest first
+func (s *FavouriteService) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	return s.repo.ListWebhooks(ctx)
+}
+
+// DeleteWebhook deletes a webhook with its delivery history; pending deliveries are dropped
+func (s *FavouriteService) DeleteWebhook(ctx context.Context, webhookID uuid.UUID) error {
+	return s.repo.DeleteWebhook(ctx, webhookID)
+}
+
+// ListWebhookDeliveries returns the delivery history of a webhook, newest first, optionally only
+// the deliveries with a status, e.g. the dead letters
+func (s *FavouriteService) ListWebhookDeliveries(ctx context.Context, webhookID uuid.UUID, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error) {
+	switch status {
+	case "", domain.DeliveryPending, domain.DeliverySucceeded, domain.DeliveryDead:
+	default:
+		return nil, fmt.Errorf("%w: unknown delivery status %q", domain.ErrInvalidWebhook, status)
+	}
+	return s.repo.ListDeliveries(ctx, webhookID, status)
+}
+
+// RedeliverWebhook gives a dead delivery a new round of attempts
+func (s *FavouriteService) RedeliverWebhook(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	return s.webhooks.Redeliver(ctx, webhookID, deliveryID)
+}
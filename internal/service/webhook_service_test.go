@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFavouriteService_CreateWebhook(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(MockRepository)
+	mockRepo.On("CreateWebhook", ctx, mock.AnythingOfType("*domain.Webhook")).Return(nil)
+	svc := NewFavouriteService(mockRepo)
+
+	webhook, err := svc.CreateWebhook(ctx, "https://example.com/hooks", []domain.EventType{domain.EventAssetCreated}, "a-webhook-secret-for-tests")
+	require.NoError(t, err)
+	assert.Equal(t, "a-webhook-secret-for-tests", webhook.Secret)
+
+	// Invalid webhooks never reach the repository
+	_, err = svc.CreateWebhook(ctx, "https://example.com/hooks", nil, "a-webhook-secret-for-tests")
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+	mockRepo.AssertNumberOfCalls(t, "CreateWebhook", 1)
+}
+
+func TestFavouriteService_WebhookDeliveries(t *testing.T) {
+	ctx := context.Background()
+	webhookID := uuid.New()
+	delivery := domain.NewWebhookDelivery(webhookID, domain.Event{ID: 1, Type: domain.EventAssetCreated})
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("ListDeliveries", ctx, webhookID, domain.DeliveryDead).Return([]*domain.WebhookDelivery{}, nil)
+	mockRepo.On("GetDelivery", ctx, webhookID, delivery.ID).Return(delivery, nil)
+	svc := NewFavouriteService(mockRepo)
+
+	deliveries, err := svc.ListWebhookDeliveries(ctx, webhookID, domain.DeliveryDead)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+	_, err = svc.ListWebhookDeliveries(ctx, webhookID, "lost")
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+
+	// Only dead letters can be redelivered
+	_, err = svc.RedeliverWebhook(ctx, webhookID, delivery.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+	mockRepo.AssertNotCalled(t, "SaveDelivery", mock.Anything, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
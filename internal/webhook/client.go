@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+)
+
+// ErrRedirect is the error of deliveries answered with a redirect, which are not followed
+var ErrRedirect = errors.New("webhook redirects are not followed")
+
+// NewClient creates the HTTP client delivering to webhooks. Unless allowPrivate, it refuses to
+// connect to loopback, private and link-local addresses, checked once host names are resolved so
+// that DNS cannot point a webhook at the server's own network. Redirects are never followed, nor
+// proxies from the environment used.
+func NewClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !domain.PublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return ErrRedirect
+		},
+	}
+}
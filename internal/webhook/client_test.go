@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient(t *testing.T) {
+	rcv := newReceiver(t)
+	redirect := httptest.NewServer(http.RedirectHandler(rcv.URL, http.StatusTemporaryRedirect))
+	defer redirect.Close()
+
+	// Loopback receivers are refused once resolved, unless private addresses are allowed
+	_, err := NewClient(false).Post(rcv.URL, "application/json", strings.NewReader(`{}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not public")
+	resp, err := NewClient(true).Post(rcv.URL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// Redirects are not followed
+	_, err = NewClient(true).Post(redirect.URL, "application/json", strings.NewReader(`{}`))
+	assert.ErrorIs(t, err, ErrRedirect)
+	assert.Len(t, rcv.events(), 1, "the receiver got the direct request only")
+}
@@ -0,0 +1,329 @@
+// Package webhook delivers events to the URLs subscribed to them by webhooks. Deliveries are
+// signed with the webhook's secret and retried with exponential backoff; those failing every
+// attempt are left dead, in the webhook's dead-letter list, until redelivered. The dispatcher is
+// an outbox sink: each event handed to it is delivered once per subscribed webhook.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Headers of webhook deliveries
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// maxResponseBody bounds the part of a response read before closing it
+const maxResponseBody = 64 * 1024
+
+// Store keeps the webhooks and their deliveries
+type Store interface {
+	GetWebhook(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*domain.Webhook, error)
+	SaveDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, status domain.DeliveryStatus) ([]*domain.WebhookDelivery, error)
+}
+
+// Policy tells how deliveries are attempted
+type Policy struct {
+	Workers     int           // Deliveries attempted concurrently
+	Timeout     time.Duration // Time allowed to each attempt
+	MaxAttempts int           // Attempts before a delivery is dead
+	Backoff     time.Duration // Wait before the first retry, doubling with each further retry
+	MaxBackoff  time.Duration // Longest wait between retries
+}
+
+// backoff returns the wait before retrying a delivery after its n-th failed attempt
+func (p Policy) backoff(attempts int) time.Duration {
+	wait := p.Backoff
+	for i := 1; i < attempts && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, p.MaxBackoff)
+}
+
+// Sign returns the signature of a delivery body sent at a time: the hex-encoded HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook's secret, prefixed with "sha256="
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether a signature is the one of a delivery body sent at a time
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// job identifies a delivery to attempt
+type job struct {
+	webhookID, deliveryID uuid.UUID
+}
+
+// Dispatcher delivers events to webhooks. Deliveries are created as events are handed to it, and
+// attempted while it runs.
+type Dispatcher struct {
+	store  Store
+	client *http.Client
+	policy Policy
+
+	mu     sync.Mutex
+	jobs   []job
+	queued map[job]struct{} // jobs queued or being attempted, so that each is attempted once at a time
+	ready  chan struct{}    // signalled when jobs are queued
+}
+
+// NewDispatcher creates a dispatcher of the webhooks in a store
+func NewDispatcher(store Store, client *http.Client, policy Policy) *Dispatcher {
+	return &Dispatcher{
+		store:  store,
+		client: client,
+		policy: policy,
+		queued: make(map[job]struct{}),
+		ready:  make(chan struct{}, 1),
+	}
+}
+
+// Run attempts the deliveries until the context is done, starting with those left pending by an
+// earlier run
+func (d *Dispatcher) Run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	var workers sync.WaitGroup
+	for i := 0; i < max(d.policy.Workers, 1); i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			d.work(ctx)
+		}()
+	}
+	defer func() {
+		cancel()
+		workers.Wait()
+	}()
+
+	if err := d.resume(ctx); err != nil {
+		log.Printf("webhooks: resuming pending deliveries: %v", err)
+	}
+	<-ctx.Done()
+}
+
+// resume queues the pending deliveries, oldest first, once their retry is due
+func (d *Dispatcher) resume(ctx context.Context) error {
+	webhooks, err := d.store.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	for _, hook := range webhooks {
+		pending, err := d.store.ListDeliveries(ctx, hook.ID, domain.DeliveryPending)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		for i := len(pending) - 1; i >= 0; i-- {
+			j := job{webhookID: hook.ID, deliveryID: pending[i].ID}
+			if retryAt := pending[i].NextAttemptAt; retryAt != nil && time.Until(*retryAt) > 0 {
+				time.AfterFunc(time.Until(*retryAt), func() { d.enqueue(j) })
+				continue
+			}
+			d.enqueue(j)
+		}
+	}
+	return nil
+}
+
+// Handle creates a pending delivery of each event for each webhook subscribed to its type. An
+// event handed again keeps the delivery created the first time.
+func (d *Dispatcher) Handle(ctx context.Context, events []domain.Event) error {
+	webhooks, err := d.store.ListWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		for _, hook := range webhooks {
+			if !hook.Subscribes(event.Type) {
+				continue
+			}
+			delivery := domain.NewWebhookDelivery(hook.ID, event)
+			switch _, err := d.store.GetDelivery(ctx, hook.ID, delivery.ID); {
+			case err == nil:
+				continue // created already
+			case !errors.Is(err, domain.ErrNotFound):
+				return err
+			}
+			if err := d.store.SaveDelivery(ctx, delivery); err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					continue // deleted meanwhile
+				}
+				return fmt.Errorf("saving delivery of event %d to webhook %s: %w", event.ID, hook.ID, err)
+			}
+			d.enqueue(job{webhookID: hook.ID, deliveryID: delivery.ID})
+		}
+	}
+	return nil
+}
+
+// Redeliver gives a dead delivery a new round of attempts
+func (d *Dispatcher) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	delivery, err := d.store.GetDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	redelivered, err := delivery.Redelivered()
+	if err != nil {
+		return nil, err
+	}
+	if err := d.store.SaveDelivery(ctx, redelivered); err != nil {
+		return nil, err
+	}
+	d.enqueue(job{webhookID: webhookID, deliveryID: deliveryID})
+	return redelivered, nil
+}
+
+// enqueue queues a job unless it is queued or being attempted already
+func (d *Dispatcher) enqueue(j job) {
+	d.mu.Lock()
+	if _, queued := d.queued[j]; queued {
+		d.mu.Unlock()
+		return
+	}
+	d.queued[j] = struct{}{}
+	d.jobs = append(d.jobs, j)
+	d.mu.Unlock()
+	select {
+	case d.ready <- struct{}{}:
+	default:
+	}
+}
+
+// next waits for the next job to attempt, until the context is done
+func (d *Dispatcher) next(ctx context.Context) (job, bool) {
+	for {
+		d.mu.Lock()
+		if len(d.jobs) > 0 {
+			j := d.jobs[0]
+			d.jobs = d.jobs[1:]
+			more := len(d.jobs) > 0
+			d.mu.Unlock()
+			if more {
+				// Wake another worker for the remaining jobs
+				select {
+				case d.ready <- struct{}{}:
+				default:
+				}
+			}
+			return j, true
+		}
+		d.mu.Unlock()
+
+		select {
+		case <-d.ready:
+		case <-ctx.Done():
+			return job{}, false
+		}
+	}
+}
+
+func (d *Dispatcher) work(ctx context.Context) {
+	for {
+		j, ok := d.next(ctx)
+		if !ok {
+			return
+		}
+		retryAt := d.attempt(ctx, j)
+		d.mu.Lock()
+		delete(d.queued, j)
+		d.mu.Unlock()
+		if retryAt != nil {
+			time.AfterFunc(time.Until(*retryAt), func() { d.enqueue(j) })
+		}
+	}
+}
+
+// attempt makes an attempt at a pending delivery, leaving it dead on failure unless it is to be
+// retried, at the time returned
+func (d *Dispatcher) attempt(ctx context.Context, j job) *time.Time {
+	hook, err := d.store.GetWebhook(ctx, j.webhookID)
+	if err != nil {
+		return nil // deleted meanwhile
+	}
+	delivery, err := d.store.GetDelivery(ctx, j.webhookID, j.deliveryID)
+	if err != nil || delivery.Status != domain.DeliveryPending {
+		return nil
+	}
+
+	attempt := d.send(ctx, hook, delivery)
+	if ctx.Err() != nil {
+		return nil // shutting down; the attempt did not complete
+	}
+	status := domain.DeliverySucceeded
+	var nextAttemptAt *time.Time
+	if attempt.Error != "" || attempt.StatusCode < 200 || attempt.StatusCode > 299 {
+		status = domain.DeliveryDead
+		if delivery.RoundAttempts+1 < d.policy.MaxAttempts {
+			status = domain.DeliveryPending
+			retryAt := attempt.At.Add(d.policy.backoff(delivery.RoundAttempts + 1))
+			nextAttemptAt = &retryAt
+		}
+	}
+	if err := d.store.SaveDelivery(ctx, delivery.WithAttempt(attempt, status, nextAttemptAt)); err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			log.Printf("webhooks: saving delivery %s: %v", delivery.ID, err)
+		}
+		return nil
+	}
+	return nextAttemptAt
+}
+
+// send posts a delivery's event to its webhook's URL
+func (d *Dispatcher) send(ctx context.Context, hook *domain.Webhook, delivery *domain.WebhookDelivery) domain.DeliveryAttempt {
+	attempt := domain.DeliveryAttempt{At: time.Now()}
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.policy.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	timestamp := attempt.At.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderEvent, string(delivery.Event.Type))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	attempt.StatusCode = resp.StatusCode
+	return attempt
+}
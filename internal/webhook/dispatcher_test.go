@@ -0,0 +1,267 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gioannid/platform-go-challenge/internal/config"
+	"github.com/gioannid/platform-go-challenge/internal/domain"
+	"github.com/gioannid/platform-go-challenge/internal/repository/memory"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "a-webhook-secret-for-tests"
+
+var testPolicy = Policy{
+	Workers:     2,
+	Timeout:     time.Second,
+	MaxAttempts: 3,
+	Backoff:     10 * time.Millisecond,
+	MaxBackoff:  20 * time.Millisecond,
+}
+
+// TestMain lets webhooks target the local receivers
+func TestMain(m *testing.M) {
+	config.Get().WebhookAllowPrivate = true
+	os.Exit(m.Run())
+}
+
+// receiver is a local webhook endpoint answering with the queued status codes, then 200
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	received []domain.Event
+	invalid  int
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	rcv := &receiver{statuses: statuses}
+	rcv.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+
+		rcv.mu.Lock()
+		defer rcv.mu.Unlock()
+		if !Verify(testSecret, timestamp, body, r.Header.Get(HeaderSignature)) {
+			rcv.invalid++
+		}
+		status := http.StatusOK
+		if len(rcv.statuses) > 0 {
+			status, rcv.statuses = rcv.statuses[0], rcv.statuses[1:]
+		}
+		if status == http.StatusOK {
+			var event domain.Event
+			if err := json.Unmarshal(body, &event); err == nil && r.Header.Get(HeaderEvent) == string(event.Type) {
+				rcv.received = append(rcv.received, event)
+			}
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rcv.Close)
+	return rcv
+}
+
+// unsigned counts the requests received without a valid signature
+func (rcv *receiver) unsigned() int {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return rcv.invalid
+}
+
+func (rcv *receiver) events() []domain.Event {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	return append([]domain.Event(nil), rcv.received...)
+}
+
+// start runs a dispatcher of the repository's webhooks
+func start(t *testing.T, repo *memory.MemoryRepository) *Dispatcher {
+	t.Helper()
+
+	dispatcher := NewDispatcher(repo, NewClient(true), testPolicy)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dispatcher.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return dispatcher
+}
+
+// handle hands events to a dispatcher, numbered from 1 in order
+func handle(t *testing.T, dispatcher *Dispatcher, events ...domain.Event) {
+	t.Helper()
+
+	for i := range events {
+		events[i].ID = uint64(i + 1)
+	}
+	require.NoError(t, dispatcher.Handle(context.Background(), events))
+}
+
+func subscribe(t *testing.T, repo *memory.MemoryRepository, url string, eventTypes ...domain.EventType) *domain.Webhook {
+	t.Helper()
+
+	hook, err := domain.NewWebhook(url, eventTypes, testSecret)
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateWebhook(context.Background(), hook))
+	return hook
+}
+
+// settled waits until a webhook has no pending deliveries left and returns its deliveries
+func settled(t *testing.T, repo *memory.MemoryRepository, hook *domain.Webhook, count int) []*domain.WebhookDelivery {
+	t.Helper()
+
+	var deliveries []*domain.WebhookDelivery
+	require.Eventually(t, func() bool {
+		var err error
+		deliveries, err = repo.ListDeliveries(context.Background(), hook.ID, "")
+		require.NoError(t, err)
+		if len(deliveries) != count {
+			return false
+		}
+		for _, delivery := range deliveries {
+			if delivery.Status == domain.DeliveryPending {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 5*time.Millisecond)
+	return deliveries
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign(testSecret, 1700000000, body)
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+	assert.True(t, Verify(testSecret, 1700000000, body, signature))
+	assert.False(t, Verify(testSecret, 1700000001, body, signature))
+	assert.False(t, Verify("another-secret-of-some-length", 1700000000, body, signature))
+	assert.False(t, Verify(testSecret, 1700000000, []byte(`{"id":2}`), signature))
+}
+
+func TestPolicy_Backoff(t *testing.T) {
+	policy := Policy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	var waits []time.Duration
+	for attempts := 1; attempts <= 5; attempts++ {
+		waits = append(waits, policy.backoff(attempts))
+	}
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}, waits)
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	repo := memory.NewRepository()
+	assets := newReceiver(t)
+	favourites := newReceiver(t)
+	assetHook := subscribe(t, repo, assets.URL, domain.EventAssetCreated, domain.EventAssetDeleted)
+	favouriteHook := subscribe(t, repo, favourites.URL, domain.EventFavouriteAdded)
+	dispatcher := start(t, repo)
+
+	assetID := uuid.New()
+	handle(t, dispatcher,
+		domain.NewAssetEvent(domain.EventAssetCreated, assetID, &domain.Asset{ID: assetID}),
+		domain.NewFavouriteEvent(domain.EventFavouriteAdded, domain.NewFavourite(uuid.New(), assetID)),
+		domain.NewAssetEvent(domain.EventAssetDeleted, assetID, nil),
+	)
+
+	// Each webhook receives signed deliveries of the event types it subscribed to only
+	deliveries := settled(t, repo, assetHook, 2)
+	settled(t, repo, favouriteHook, 1)
+	for _, delivery := range deliveries {
+		assert.Equal(t, domain.DeliverySucceeded, delivery.Status)
+		require.Len(t, delivery.Attempts, 1)
+		assert.Equal(t, http.StatusOK, delivery.Attempts[0].StatusCode)
+	}
+	received := assets.events()
+	require.Len(t, received, 2)
+	assert.ElementsMatch(t, []domain.EventType{domain.EventAssetCreated, domain.EventAssetDeleted}, []domain.EventType{received[0].Type, received[1].Type})
+	assert.Equal(t, domain.EventFavouriteAdded, favourites.events()[0].Type)
+	assert.Zero(t, assets.unsigned()+favourites.unsigned(), "every delivery is signed with the secret")
+}
+
+func TestDispatcher_Retry(t *testing.T) {
+	repo := memory.NewRepository()
+	flaky := newReceiver(t, http.StatusInternalServerError, http.StatusServiceUnavailable)
+	hook := subscribe(t, repo, flaky.URL, domain.EventAssetCreated)
+	dispatcher := start(t, repo)
+
+	handle(t, dispatcher, domain.NewAssetEvent(domain.EventAssetCreated, uuid.New(), nil))
+
+	delivery := settled(t, repo, hook, 1)[0]
+	assert.Equal(t, domain.DeliverySucceeded, delivery.Status)
+	require.Len(t, delivery.Attempts, 3)
+	assert.Equal(t, []int{500, 503, 200}, []int{delivery.Attempts[0].StatusCode, delivery.Attempts[1].StatusCode, delivery.Attempts[2].StatusCode})
+	assert.GreaterOrEqual(t, delivery.Attempts[1].At.Sub(delivery.Attempts[0].At), testPolicy.Backoff)
+	assert.GreaterOrEqual(t, delivery.Attempts[2].At.Sub(delivery.Attempts[1].At), 2*testPolicy.Backoff)
+	assert.Len(t, flaky.events(), 1)
+}
+
+func TestDispatcher_DeadLetters(t *testing.T) {
+	repo := memory.NewRepository()
+	down := newReceiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	hook := subscribe(t, repo, down.URL, domain.EventAssetCreated)
+	unreachable := subscribe(t, repo, "http://127.0.0.1:1/hooks", domain.EventAssetCreated)
+	dispatcher := start(t, repo)
+
+	handle(t, dispatcher, domain.NewAssetEvent(domain.EventAssetCreated, uuid.New(), nil))
+
+	// Deliveries failing every attempt are dead, unreachable receivers included
+	delivery := settled(t, repo, hook, 1)[0]
+	assert.Equal(t, domain.DeliveryDead, delivery.Status)
+	assert.Len(t, delivery.Attempts, testPolicy.MaxAttempts)
+	assert.Nil(t, delivery.NextAttemptAt)
+	failed := settled(t, repo, unreachable, 1)[0]
+	assert.Equal(t, domain.DeliveryDead, failed.Status)
+	assert.NotEmpty(t, failed.Attempts[0].Error)
+	deadLetters, err := repo.ListDeliveries(context.Background(), hook.ID, domain.DeliveryDead)
+	require.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+
+	// Redelivering a dead letter gives it a new round of attempts, once the receiver is back
+	redelivered, err := dispatcher.Redeliver(context.Background(), hook.ID, delivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeliveryPending, redelivered.Status)
+	delivery = settled(t, repo, hook, 1)[0]
+	assert.Equal(t, domain.DeliverySucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Redeliveries)
+	assert.Len(t, delivery.Attempts, testPolicy.MaxAttempts+1)
+	assert.Len(t, down.events(), 1)
+
+	_, err = dispatcher.Redeliver(context.Background(), hook.ID, delivery.ID)
+	assert.ErrorIs(t, err, domain.ErrInvalidWebhook)
+	_, err = dispatcher.Redeliver(context.Background(), hook.ID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestDispatcher_AtLeastOnce(t *testing.T) {
+	repo := memory.NewRepository()
+	rcv := newReceiver(t)
+	hook := subscribe(t, repo, rcv.URL, domain.EventAssetCreated)
+
+	// Deliveries left pending, e.g. by a dispatcher that stopped, are attempted by the next one run
+	stopped := NewDispatcher(repo, http.DefaultClient, testPolicy)
+	handle(t, stopped, domain.NewAssetEvent(domain.EventAssetCreated, uuid.New(), nil), domain.NewAssetEvent(domain.EventAssetCreated, uuid.New(), nil))
+	dispatcher := start(t, repo)
+	settled(t, repo, hook, 2)
+
+	// Events handed again are not delivered twice
+	handle(t, dispatcher, domain.NewAssetEvent(domain.EventAssetCreated, uuid.New(), nil), domain.NewAssetEvent(domain.EventAssetCreated, uuid.New(), nil))
+	deliveries, err := repo.ListDeliveries(context.Background(), hook.ID, "")
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 2)
+	assert.Len(t, rcv.events(), 2)
+}
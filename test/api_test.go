@@ -3,11 +3,20 @@
 package test
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"image/png"
+	"io"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -18,7 +27,10 @@ import (
 	"github.com/gioannid/platform-go-challenge/internal/repository/memory"
 	"github.com/gioannid/platform-go-challenge/internal/server"
 	"github.com/gioannid/platform-go-challenge/internal/service"
+	"github.com/gioannid/platform-go-challenge/internal/webhook"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -35,14 +47,41 @@ func setupTestServer(t *testing.T) (*httptest.Server, *memory.MemoryRepository)
 	repo := memory.NewRepository()
 	svc := service.NewFavouriteService(repo)
 	h := handler.NewHandler(svc)
-	mw := server.NewChain(middleware.Logger())
+	mw := server.NewChain(middleware.RequestID(), middleware.Logger())
 
 	srv := server.New(cfg, h, mw)
 	testServer := httptest.NewServer(srv.Router())
+	dispatchEvents(t, svc)
 
 	return testServer, repo
 }
 
+// dispatched waits until the events recorded in a repository are relayed, e.g. so that changes
+// made before subscribing to events are not streamed
+func dispatched(t *testing.T, repo *memory.MemoryRepository) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		pending, err := repo.PendingEvents(context.Background(), 0)
+		require.NoError(t, err)
+		return len(pending) == 0
+	}, 5*time.Second, 5*time.Millisecond)
+}
+
+// dispatchEvents relays the events recorded by a service's changes until the test ends
+func dispatchEvents(t *testing.T, svc *service.FavouriteService) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.DispatchEvents(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
 func TestIntegration_CompleteWorkflow(t *testing.T) {
 	ts, repo := setupTestServer(t)
 	defer ts.Close()
@@ -215,6 +254,65 @@ func TestIntegration_DeleteAssetRemovesFavourite(t *testing.T) {
 	assert.Empty(t, listData["favourites"], "Favourites list should be empty after asset deletion")
 }
 
+func TestIntegration_DeleteReferencedAudience(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	audience, err := domain.NewAsset(domain.AssetTypeAudience, "Millennials", domain.AudienceData{AgeGroups: []domain.AgeGroup{"25-34"}})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, audience))
+
+	createInsight := func(audienceID string) (int, handler.Response) {
+		body, _ := json.Marshal(map[string]interface{}{
+			"type":        "insight",
+			"description": "Social media usage",
+			"data": map[string]interface{}{
+				"text": "40% of millennials spend more than 3 hours on social media daily", "value": 40, "unit": "%",
+				"audience_id": audienceID,
+			},
+		})
+		resp, err := http.Post(ts.URL+"/api/v1/assets", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		var createResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&createResp))
+		return resp.StatusCode, createResp
+	}
+
+	// Dangling references are rejected
+	status, _ := createInsight(uuid.New().String())
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, createResp := createInsight(audience.ID.String())
+	require.Equal(t, http.StatusCreated, status)
+	insightID := createResp.Data.(map[string]interface{})["id"].(string)
+
+	deleteAudience := func(query string) (int, handler.Response) {
+		req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/api/v1/assets/"+audience.ID.String()+query, nil)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		var deleteResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&deleteResp))
+		return resp.StatusCode, deleteResp
+	}
+
+	// A referenced audience cannot be deleted without cascading
+	status, _ = deleteAudience("")
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = deleteAudience("?cascade=maybe")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, deleteResp := deleteAudience("?cascade=true")
+	assert.Equal(t, http.StatusOK, status)
+	deletedIDs := deleteResp.Data.(map[string]interface{})["deleted_asset_ids"].([]interface{})
+	assert.ElementsMatch(t, []interface{}{audience.ID.String(), insightID}, deletedIDs)
+
+	_, err = repo.GetAsset(ctx, uuid.MustParse(insightID))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.NoError(t, repo.Sanity(ctx))
+}
+
 func TestIntegration_PaginationAndSorting(t *testing.T) {
 	ts, repo := setupTestServer(t)
 	defer ts.Close()
@@ -269,6 +367,1411 @@ func TestIntegration_PaginationAndSorting(t *testing.T) {
 	resp, err = http.Get(ts.URL + "/api/v1/users/" + userID.String() + "/favourites?sortBy=description&order=asc")
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Test cursor pagination: walk forwards through all pages, then one page back
+	fetch := func(query string) (int, []string, map[string]interface{}) {
+		resp, err := http.Get(ts.URL + "/api/v1/users/" + userID.String() + "/favourites?include=asset&" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var pageResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&pageResp))
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil, nil
+		}
+		data := pageResp.Data.(map[string]interface{})
+		var names []string
+		for _, fav := range data["favourites"].([]interface{}) {
+			asset := fav.(map[string]interface{})["asset"].(map[string]interface{})
+			names = append(names, asset["description"].(string)[:1])
+		}
+		return resp.StatusCode, names, data
+	}
+
+	var walked []string
+	_, names, data := fetch("limit=4&sortBy=description&order=asc")
+	assert.Nil(t, data["prev_cursor"])
+	for {
+		walked = append(walked, names...)
+		next, ok := data["next_cursor"].(string)
+		if !ok {
+			break
+		}
+		_, names, data = fetch("limit=4&cursor=" + url.QueryEscape(next))
+	}
+	assert.Equal(t, []string{"A", "B", "C", "D", "E", "F", "G", "H", "I", "J"}, walked)
+
+	prev, ok := data["prev_cursor"].(string)
+	require.True(t, ok)
+	_, names, _ = fetch("limit=4&cursor=" + url.QueryEscape(prev))
+	assert.Equal(t, []string{"E", "F", "G", "H"}, names)
+
+	status, _, _ := fetch("cursor=not-a-cursor")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestIntegration_Collections(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	userID := uuid.New()
+	base := ts.URL + "/api/v1/users/" + userID.String()
+
+	favIDs := make([]string, 2)
+	for i, description := range []string{"UK sales", "US sales"} {
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, description, domain.InsightData{Text: description})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+		fav := domain.NewFavourite(userID, asset.ID)
+		require.NoError(t, repo.AddFavourite(ctx, fav))
+		favIDs[i] = fav.ID.String()
+	}
+
+	do := func(method, url, body string) (int, interface{}) {
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
+		return resp.StatusCode, apiResp.Data
+	}
+
+	// Create, with duplicate and invalid names
+	status, data := do(http.MethodPost, base+"/collections", `{"name": "Q4 review"}`)
+	require.Equal(t, http.StatusCreated, status)
+	collectionID := data.(map[string]interface{})["id"].(string)
+	status, _ = do(http.MethodPost, base+"/collections", `{"name": "q4 REVIEW"}`)
+	assert.Equal(t, http.StatusConflict, status)
+	status, _ = do(http.MethodPost, base+"/collections", `{"name": " "}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Membership
+	collection := base + "/collections/" + collectionID
+	status, _ = do(http.MethodPut, collection+"/favourites/"+favIDs[1], "")
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = do(http.MethodPut, collection+"/favourites/"+uuid.NewString(), "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, data = do(http.MethodGet, collection+"/favourites?include=asset", "")
+	require.Equal(t, http.StatusOK, status)
+	listData := data.(map[string]interface{})
+	assert.Equal(t, float64(1), listData["total"])
+	fav := listData["favourites"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, favIDs[1], fav["id"])
+	assert.Equal(t, []interface{}{collectionID}, fav["collection_ids"])
+	assert.Equal(t, "US sales", fav["asset"].(map[string]interface{})["description"])
+
+	// Rename and list
+	status, data = do(http.MethodPatch, collection, `{"name": "Q4 board review"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Q4 board review", data.(map[string]interface{})["name"])
+
+	status, data = do(http.MethodGet, base+"/collections", "")
+	require.Equal(t, http.StatusOK, status)
+	collections := data.(map[string]interface{})["collections"].([]interface{})
+	require.Len(t, collections, 1)
+	assert.Equal(t, float64(1), collections[0].(map[string]interface{})["favourite_count"])
+
+	// Removing the favourite removes it from the collection
+	status, _ = do(http.MethodDelete, base+"/favourites/"+favIDs[1], "")
+	require.Equal(t, http.StatusOK, status)
+	status, data = do(http.MethodGet, collection+"/favourites", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(0), data.(map[string]interface{})["total"])
+
+	// Deleting the collection keeps the remaining favourites
+	status, _ = do(http.MethodPut, collection+"/favourites/"+favIDs[0], "")
+	require.Equal(t, http.StatusOK, status)
+	status, _ = do(http.MethodDelete, collection, "")
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = do(http.MethodGet, collection+"/favourites", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, data = do(http.MethodGet, base+"/favourites", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(1), data.(map[string]interface{})["total"])
+}
+
+func TestIntegration_ManualOrdering(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	userID := uuid.New()
+	base := ts.URL + "/api/v1/users/" + userID.String() + "/favourites"
+
+	favIDs := make([]string, 4)
+	for i := range favIDs {
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, "insight", domain.InsightData{Text: "text"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+		fav := domain.NewFavourite(userID, asset.ID)
+		require.NoError(t, repo.AddFavourite(ctx, fav))
+		favIDs[i] = fav.ID.String()
+	}
+
+	do := func(method, url, body string) (int, interface{}) {
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
+		return resp.StatusCode, apiResp.Data
+	}
+
+	// order walks the manual order with cursors, two favourites per page
+	order := func() []string {
+		ids := []string{}
+		next := base + "?sortBy=position&limit=2"
+		for next != "" {
+			status, data := do(http.MethodGet, next, "")
+			require.Equal(t, http.StatusOK, status)
+			page := data.(map[string]interface{})
+			for _, fav := range page["favourites"].([]interface{}) {
+				ids = append(ids, fav.(map[string]interface{})["id"].(string))
+			}
+			next = ""
+			if cursor, ok := page["next_cursor"].(string); ok && cursor != "" {
+				next = base + "?limit=2&cursor=" + url.QueryEscape(cursor)
+			}
+		}
+		return ids
+	}
+	assert.Equal(t, favIDs, order())
+
+	status, data := do(http.MethodPut, base+"/"+favIDs[3]+"/position", `{"before_id": "`+favIDs[0]+`"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.NotEmpty(t, data.(map[string]interface{})["position"])
+	assert.Equal(t, []string{favIDs[3], favIDs[0], favIDs[1], favIDs[2]}, order())
+
+	status, data = do(http.MethodPut, base+"/"+favIDs[2]+"/pin", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, data.(map[string]interface{})["pinned"])
+	assert.Equal(t, []string{favIDs[2], favIDs[3], favIDs[0], favIDs[1]}, order())
+
+	// Neighbours must be in the same pin group, and exactly one must be given
+	status, _ = do(http.MethodPut, base+"/"+favIDs[0]+"/position", `{"before_id": "`+favIDs[2]+`"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = do(http.MethodPut, base+"/"+favIDs[0]+"/position", `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = do(http.MethodPut, base+"/"+favIDs[0]+"/position", `{"after_id": "`+uuid.NewString()+`"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, _ = do(http.MethodDelete, base+"/"+favIDs[2]+"/pin", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []string{favIDs[2], favIDs[3], favIDs[0], favIDs[1]}, order())
+	status, _ = do(http.MethodDelete, base+"/"+uuid.NewString()+"/pin", "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestIntegration_FavouriteAnnotations(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	userID := uuid.New()
+	base := ts.URL + "/api/v1/users/" + userID.String() + "/favourites"
+
+	asset, err := domain.NewAsset(domain.AssetTypeInsight, "Social media", domain.InsightData{Text: "Usage doubled"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, asset))
+	fav := domain.NewFavourite(userID, asset.ID)
+	require.NoError(t, repo.AddFavourite(ctx, fav))
+	favURL := base + "/" + fav.ID.String()
+
+	do := func(method, url, body string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
+		data, _ := apiResp.Data.(map[string]interface{})
+		return resp.StatusCode, data
+	}
+
+	status, data := do(http.MethodPatch, favURL, `{"title": " Campaign  evidence ", "note": "Quote in the retail pitch"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Campaign evidence", data["title"])
+	assert.Equal(t, "Quote in the retail pitch", data["note"])
+
+	// Only the given fields change
+	status, data = do(http.MethodPatch, favURL, `{"note": ""}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Campaign evidence", data["title"])
+	assert.NotContains(t, data, "note")
+
+	// The asset itself is untouched, and annotations show up in listings and search
+	stored, err := repo.GetAsset(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Social media", stored.Description)
+
+	status, data = do(http.MethodGet, base+"?fields=id,title", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": fav.ID.String(), "title": "Campaign evidence"}}, data["favourites"])
+
+	status, data = do(http.MethodGet, base+"/search?q=campaign", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(1), data["total"])
+	status, data = do(http.MethodGet, base+"/search?q=campaign+social", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(1), data["total"])
+
+	status, _ = do(http.MethodPatch, favURL, `{"title": "`+strings.Repeat("x", 201)+`"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = do(http.MethodPatch, base+"/"+uuid.NewString(), `{"title": "x"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestIntegration_BatchFavourites(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	userID := uuid.New()
+	batchURL := ts.URL + "/api/v1/users/" + userID.String() + "/favourites:batch"
+
+	assetIDs := make([]string, 3)
+	for i := range assetIDs {
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, "insight", domain.InsightData{Text: "text"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+		assetIDs[i] = asset.ID.String()
+	}
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, uuid.MustParse(assetIDs[0]))))
+	missing := uuid.NewString()
+
+	do := func(method string, ids ...string) (int, map[string]interface{}) {
+		body, err := json.Marshal(map[string][]string{"asset_ids": ids})
+		require.NoError(t, err)
+		req, err := http.NewRequest(method, batchURL, bytes.NewReader(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
+		data, _ := apiResp.Data.(map[string]interface{})
+		return resp.StatusCode, data
+	}
+	results := func(data map[string]interface{}) []string {
+		var out []string
+		for _, item := range data["results"].([]interface{}) {
+			item := item.(map[string]interface{})
+			out = append(out, item["status"].(string))
+		}
+		return out
+	}
+
+	status, data := do(http.MethodPost, assetIDs[0], assetIDs[1], missing, assetIDs[2])
+	require.Equal(t, http.StatusMultiStatus, status)
+	assert.Equal(t, []string{"already_exists", "created", "asset_not_found", "created"}, results(data))
+	first := data["results"].([]interface{})[1].(map[string]interface{})
+	assert.Equal(t, assetIDs[1], first["asset_id"])
+	assert.Equal(t, float64(http.StatusCreated), first["code"])
+	assert.NotEmpty(t, first["favourite"].(map[string]interface{})["id"])
+	assert.Equal(t, map[string]interface{}{"created": float64(2), "already_exists": float64(1), "asset_not_found": float64(1)}, data["summary"])
+
+	status, data = do(http.MethodDelete, assetIDs[1], missing)
+	require.Equal(t, http.StatusMultiStatus, status)
+	assert.Equal(t, []string{"removed", "not_found"}, results(data))
+
+	isFav, err := repo.IsFavourite(ctx, userID, uuid.MustParse(assetIDs[1]))
+	require.NoError(t, err)
+	assert.False(t, isFav)
+
+	status, _ = do(http.MethodPost)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = do(http.MethodDelete, "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestIntegration_FavouriteStatus(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// Serve with authentication on, so that favouritedBy=me resolves to the token's user
+	cfg := &config.Config{ServerAddress: ":0", AuthEnabled: true, JWTSecret: "test-secret"}
+	repo := memory.NewRepository()
+	srv := server.New(cfg, handler.NewHandler(service.NewFavouriteService(repo)), server.NewChain())
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": userID.String()}).SignedString([]byte(cfg.JWTSecret))
+	require.NoError(t, err)
+
+	assetIDs := make([]string, 3)
+	var favID string
+	for i := range assetIDs {
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, "insight", domain.InsightData{Text: "text"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+		assetIDs[i] = asset.ID.String()
+		if i == 1 {
+			fav := domain.NewFavourite(userID, asset.ID)
+			require.NoError(t, repo.AddFavourite(ctx, fav))
+			favID = fav.ID.String()
+		}
+	}
+
+	get := func(url string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
+		data, _ := apiResp.Data.(map[string]interface{})
+		return resp.StatusCode, data
+	}
+
+	status, data := get(ts.URL + "/api/v1/users/" + userID.String() + "/favourites/status?assetId=" + assetIDs[0] + "&assetId=" + assetIDs[1])
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"asset_id": assetIDs[0], "favourited": false},
+		map[string]interface{}{"asset_id": assetIDs[1], "favourited": true, "favourite_id": favID},
+	}, data["statuses"])
+
+	status, _ = get(ts.URL + "/api/v1/users/" + userID.String() + "/favourites/status")
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = get(ts.URL + "/api/v1/users/" + userID.String() + "/favourites/status?assetId=nope")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Asset listings mark the user's favourites
+	for _, favouritedBy := range []string{"me", userID.String()} {
+		status, data = get(ts.URL + "/api/v1/assets?sortBy=created_at&order=asc&favouritedBy=" + favouritedBy)
+		require.Equal(t, http.StatusOK, status)
+		assets := data["assets"].([]interface{})
+		require.Len(t, assets, 3)
+		for i, asset := range assets {
+			asset := asset.(map[string]interface{})
+			assert.Equal(t, assetIDs[i], asset["id"])
+			assert.Equal(t, i == 1, asset["favourited"])
+			if i == 1 {
+				assert.Equal(t, favID, asset["favourite_id"])
+			} else {
+				assert.NotContains(t, asset, "favourite_id")
+			}
+		}
+	}
+
+	status, data = get(ts.URL + "/api/v1/assets")
+	require.Equal(t, http.StatusOK, status)
+	assert.NotContains(t, data["assets"].([]interface{})[0], "favourited")
+	status, _ = get(ts.URL + "/api/v1/assets?favouritedBy=someone")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Without authentication there is no "me"
+	anonymous, _ := setupTestServer(t)
+	defer anonymous.Close()
+	status, _ = get(anonymous.URL + "/api/v1/assets?favouritedBy=me")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Authenticated users only stream their own favourite events
+	status, _ = get(ts.URL + "/api/v1/users/" + uuid.New().String() + "/favourites/events")
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestIntegration_ConditionalGet(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	userID := uuid.New()
+	base := ts.URL + "/api/v1/users/" + userID.String() + "/favourites"
+
+	asset, err := domain.NewAsset(domain.AssetTypeInsight, "Social media", domain.InsightData{Text: "Usage doubled"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, asset))
+	other, err := domain.NewAsset(domain.AssetTypeInsight, "Radio", domain.InsightData{Text: "Usage halved"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, other))
+	fav := domain.NewFavourite(userID, asset.ID)
+	require.NoError(t, repo.AddFavourite(ctx, fav))
+	favURL := base + "/" + fav.ID.String()
+
+	do := func(method, url, body, etag string) (*http.Response, map[string]interface{}) {
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		raw, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		if len(raw) == 0 {
+			return resp, nil
+		}
+		var apiResp handler.Response
+		require.NoError(t, json.Unmarshal(raw, &apiResp))
+		data, _ := apiResp.Data.(map[string]interface{})
+		return resp, data
+	}
+
+	// A single favourite comes with its asset and an ETag
+	resp, data := do(http.MethodGet, favURL, "", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, fav.ID.String(), data["id"])
+	assert.Equal(t, "Social media", data["asset"].(map[string]interface{})["description"])
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	resp, data = do(http.MethodGet, favURL, "", etag)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	assert.Nil(t, data)
+	assert.Equal(t, etag, resp.Header.Get("ETag"))
+	resp, _ = do(http.MethodGet, favURL, "", `"other", W/`+etag)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	// A change yields a new representation
+	resp, _ = do(http.MethodPatch, favURL, `{"title": "Campaign evidence"}`, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, data = do(http.MethodGet, favURL, "", etag)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Campaign evidence", data["title"])
+	assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+
+	resp, _ = do(http.MethodGet, base+"/"+uuid.NewString(), "", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	// Listings can be narrowed down to the favourite of one asset
+	resp, data = do(http.MethodGet, base+"?assetId="+asset.ID.String(), "", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, float64(1), data["total"])
+	listETag := resp.Header.Get("ETag")
+	require.NotEmpty(t, listETag)
+	resp, _ = do(http.MethodGet, base+"?assetId="+asset.ID.String(), "", listETag)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	resp, data = do(http.MethodGet, base+"?assetId="+other.ID.String(), "", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, float64(0), data["total"])
+	resp, _ = do(http.MethodGet, base+"?assetId=nope", "", "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Assets too
+	assetURL := ts.URL + "/api/v1/assets/" + asset.ID.String()
+	resp, data = do(http.MethodGet, assetURL, "", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "Social media", data["description"])
+	etag = resp.Header.Get("ETag")
+	resp, _ = do(http.MethodGet, assetURL, "", etag)
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+	resp, _ = do(http.MethodGet, assetURL, "", "*")
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	resp, _ = do(http.MethodPatch, assetURL+"/description", `{"description": "Social networks"}`, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, _ = do(http.MethodGet, assetURL, "", etag)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, _ = do(http.MethodGet, ts.URL+"/api/v1/assets/"+uuid.NewString(), "", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestIntegration_Sharing(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	ownerID, teammateID, recipientID := uuid.New(), uuid.New(), uuid.New()
+	users := ts.URL + "/api/v1/users/"
+
+	assets := make([]*domain.Asset, 3)
+	for i := range assets {
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, "insight", domain.InsightData{Text: "text"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+		assets[i] = asset
+	}
+	for _, asset := range assets[:2] {
+		require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(ownerID, asset.ID)))
+	}
+	// The recipient has favourited one of the shared assets already
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(recipientID, assets[1].ID)))
+
+	do := func(method, url, body string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(method, url, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
+		data, _ := apiResp.Data.(map[string]interface{})
+		return resp.StatusCode, data
+	}
+
+	// Share read only with a teammate, and editable through a link
+	status, data := do(http.MethodPost, users+ownerID.String()+"/shares", `{"user_ids": ["`+teammateID.String()+`"]}`)
+	require.Equal(t, http.StatusCreated, status)
+	readShareID := data["id"].(string)
+	assert.Equal(t, "read", data["permission"])
+	assert.NotContains(t, data, "token")
+
+	status, data = do(http.MethodPost, users+ownerID.String()+"/shares", `{"link": true, "permission": "edit"}`)
+	require.Equal(t, http.StatusCreated, status)
+	editShareID, token := data["id"].(string), data["token"].(string)
+	require.NotEmpty(t, token)
+
+	status, _ = do(http.MethodPost, users+ownerID.String()+"/shares", `{"permission": "edit"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, data = do(http.MethodGet, users+ownerID.String()+"/shares", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, data["shares"], 2)
+
+	// The teammate can read and copy, but not edit
+	status, data = do(http.MethodGet, users+teammateID.String()+"/shared", "")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, data["shares"], 1)
+	assert.Equal(t, readShareID, data["shares"].([]interface{})[0].(map[string]interface{})["id"])
+
+	status, data = do(http.MethodGet, users+teammateID.String()+"/shared/"+readShareID+"/favourites", "")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, float64(2), data["total"])
+	status, _ = do(http.MethodPost, users+teammateID.String()+"/shared/"+readShareID+"/favourites", `{"asset_id": "`+assets[2].ID.String()+`"}`)
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = do(http.MethodGet, users+recipientID.String()+"/shared/"+readShareID+"/favourites", "")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// The recipient redeems the link, edits the owner's favourites and copies them
+	status, data = do(http.MethodPost, users+recipientID.String()+"/shared", `{"token": "`+token+`"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, editShareID, data["id"])
+	assert.NotContains(t, data, "token")
+
+	status, data = do(http.MethodPost, users+recipientID.String()+"/shared/"+editShareID+"/favourites", `{"asset_id": "`+assets[2].ID.String()+`"}`)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, ownerID.String(), data["user_id"])
+	status, _ = do(http.MethodPost, users+recipientID.String()+"/shared/"+editShareID+"/favourites", `{"asset_id": "`+assets[2].ID.String()+`"}`)
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, data = do(http.MethodPost, users+recipientID.String()+"/shared/"+editShareID+"/copy", "")
+	require.Equal(t, http.StatusMultiStatus, status)
+	assert.Equal(t, map[string]interface{}{"created": float64(2), "already_exists": float64(1)}, data["summary"])
+	favs, total, err := repo.ListFavourites(ctx, recipientID, domain.NewPageQuery(10, 0, "", ""))
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Len(t, favs, 3)
+
+	// Revoking the link shuts the recipient out
+	status, _ = do(http.MethodDelete, users+ownerID.String()+"/shares/"+editShareID, "")
+	require.Equal(t, http.StatusOK, status)
+	status, _ = do(http.MethodGet, users+recipientID.String()+"/shared/"+editShareID+"/favourites", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	status, _ = do(http.MethodPost, users+uuid.NewString()+"/shared", `{"token": "`+token+`"}`)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestIntegration_SharingAuthorization(t *testing.T) {
+	ownerID, recipientID, strangerID := uuid.New(), uuid.New(), uuid.New()
+
+	// Serve with authentication on: users only manage their own shares and use those shared with them
+	cfg := &config.Config{ServerAddress: ":0", AuthEnabled: true, JWTSecret: "test-secret"}
+	ts := httptest.NewServer(server.New(cfg, handler.NewHandler(service.NewFavouriteService(memory.NewRepository())), server.NewChain(middleware.Logger())).Router())
+	defer ts.Close()
+	tokens := make(map[uuid.UUID]string)
+	for _, id := range []uuid.UUID{ownerID, recipientID, strangerID} {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": id.String()}).SignedString([]byte(cfg.JWTSecret))
+		require.NoError(t, err)
+		tokens[id] = token
+	}
+	do := func(as uuid.UUID, method, path, body string) (int, map[string]interface{}) {
+		req, err := http.NewRequest(method, ts.URL+"/api/v1/users/"+path, bytes.NewBufferString(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+tokens[as])
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		json.NewDecoder(resp.Body).Decode(&apiResp)
+		data, _ := apiResp.Data.(map[string]interface{})
+		return resp.StatusCode, data
+	}
+
+	status, data := do(ownerID, http.MethodPost, ownerID.String()+"/shares", `{"user_ids": ["`+recipientID.String()+`"], "link": true, "permission": "edit"}`)
+	require.Equal(t, http.StatusCreated, status)
+	shareID := data["id"].(string)
+	assert.NotEmpty(t, data["token"])
+
+	// Nobody else lists the owner's shares and their link tokens, nor acts as the owner or a recipient
+	for _, request := range []struct{ method, path, body string }{
+		{http.MethodGet, ownerID.String() + "/shares", ""},
+		{http.MethodPost, ownerID.String() + "/shares", `{"link": true}`},
+		{http.MethodDelete, ownerID.String() + "/shares/" + shareID, ""},
+		{http.MethodGet, recipientID.String() + "/shared", ""},
+		{http.MethodGet, recipientID.String() + "/shared/" + shareID + "/favourites", ""},
+		{http.MethodPost, recipientID.String() + "/shared/" + shareID + "/favourites", `{"asset_id": "` + uuid.NewString() + `"}`},
+		{http.MethodDelete, recipientID.String() + "/shared/" + shareID + "/favourites/" + uuid.NewString(), ""},
+		{http.MethodPost, recipientID.String() + "/shared/" + shareID + "/copy", ""},
+	} {
+		status, _ := do(strangerID, request.method, request.path, request.body)
+		assert.Equal(t, http.StatusForbidden, status, "%s %s", request.method, request.path)
+	}
+
+	// The recipient sees the share, without its link token
+	status, data = do(recipientID, http.MethodGet, recipientID.String()+"/shared", "")
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, data["shares"], 1)
+	assert.NotContains(t, data["shares"].([]interface{})[0], "token")
+}
+
+func TestIntegration_Popularity(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	assets := make([]*domain.Asset, 3)
+	for i, description := range []string{"Sales", "Costs", "Margins"} {
+		asset, err := domain.NewAsset(domain.AssetTypeInsight, description, domain.InsightData{Text: description})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+		assets[i] = asset
+	}
+	// Costs was starred by two users this week, Sales by three users a fortnight ago
+	for i := 0; i < 3; i++ {
+		fav := domain.NewFavourite(uuid.New(), assets[0].ID)
+		fav.CreatedAt = time.Now().Add(-14 * 24 * time.Hour)
+		require.NoError(t, repo.AddFavourite(ctx, fav))
+	}
+	userID := uuid.New()
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(uuid.New(), assets[1].ID)))
+
+	get := func(url string) (int, map[string]interface{}) {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
+		data, _ := apiResp.Data.(map[string]interface{})
+		return resp.StatusCode, data
+	}
+
+	body, _ := json.Marshal(handler.AddFavouriteRequest{AssetID: assets[1].ID})
+	resp, err := http.Post(ts.URL+"/api/v1/users/"+userID.String()+"/favourites", "application/json", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	var added handler.Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&added))
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, float64(2), added.Data.(map[string]interface{})["asset"].(map[string]interface{})["favourite_count"])
+
+	status, data := get(ts.URL + "/api/v1/assets?sortBy=popularity")
+	require.Equal(t, http.StatusOK, status)
+	var counts []interface{}
+	for _, asset := range data["assets"].([]interface{}) {
+		asset := asset.(map[string]interface{})
+		counts = append(counts, []interface{}{asset["description"], asset["favourite_count"]})
+	}
+	assert.Equal(t, []interface{}{
+		[]interface{}{"Sales", float64(3)},
+		[]interface{}{"Costs", float64(2)},
+		[]interface{}{"Margins", float64(0)},
+	}, counts)
+
+	status, data = get(ts.URL + "/api/v1/assets/trending?window=7d")
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "7d", data["window"])
+	trending := data["assets"].([]interface{})
+	require.Len(t, trending, 1)
+	assert.Equal(t, "Costs", trending[0].(map[string]interface{})["asset"].(map[string]interface{})["description"])
+	assert.Equal(t, float64(2), trending[0].(map[string]interface{})["net"])
+
+	status, data = get(ts.URL + "/api/v1/assets/trending?window=30d")
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, data["assets"], 2)
+	status, _ = get(ts.URL + "/api/v1/assets/trending?window=1y")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	require.NoError(t, repo.Sanity(ctx))
+}
+
+func TestIntegration_Recommendations(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	assets := make(map[string]*domain.Asset)
+	for _, description := range []string{"Sales by region", "Sales by channel", "Costs"} {
+		asset, err := domain.NewAsset(domain.AssetTypeChart, description, domain.ChartData{Title: description})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, asset))
+		assets[description] = asset
+	}
+	for _, description := range []string{"Sales by region", "Sales by channel"} {
+		for i := 0; i < 2; i++ {
+			require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(uuid.UUID{byte(i + 1)}, assets[description].ID)))
+		}
+	}
+	other := uuid.New()
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(other, assets["Sales by region"].ID)))
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(other, assets["Costs"].ID)))
+
+	userID := uuid.New()
+	url := ts.URL + "/api/v1/users/" + userID.String() + "/recommendations"
+	get := func(url string) (int, []interface{}) {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var apiResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&apiResp))
+		data, _ := apiResp.Data.(map[string]interface{})
+		recommendations, _ := data["recommendations"].([]interface{})
+		return resp.StatusCode, recommendations
+	}
+
+	status, recommendations := get(url)
+	require.Equal(t, http.StatusOK, status)
+	assert.Empty(t, recommendations)
+
+	// Favouriting updates the suggestions, which leave out what the user favourited
+	body, _ := json.Marshal(handler.AddFavouriteRequest{AssetID: assets["Sales by region"].ID})
+	resp, err := http.Post(ts.URL+"/api/v1/users/"+userID.String()+"/favourites", "application/json", bytes.NewBuffer(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	status, recommendations = get(url)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, recommendations, 2)
+	first := recommendations[0].(map[string]interface{})
+	assert.Equal(t, "Sales by channel", first["asset"].(map[string]interface{})["description"])
+	assert.Equal(t, []interface{}{assets["Sales by region"].ID.String()}, first["based_on"])
+	assert.Equal(t, "Costs", recommendations[1].(map[string]interface{})["asset"].(map[string]interface{})["description"])
+
+	status, recommendations = get(url + "?limit=1")
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, recommendations, 1)
+	status, _ = get(url + "?limit=x")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestIntegration_Live(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	// Serve with authentication on: browsers pass the token as subprotocol or query parameter, which
+	// is not logged
+	logged := &lockedBuffer{}
+	log.SetOutput(logged)
+	defer log.SetOutput(os.Stderr)
+	cfg := &config.Config{ServerAddress: ":0", AuthEnabled: true, JWTSecret: "test-secret"}
+	repo := memory.NewRepository()
+	svc := service.NewFavouriteService(repo)
+	srv := server.New(cfg, handler.NewHandler(svc), server.NewChain(middleware.Logger()))
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	dispatchEvents(t, svc)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": userID.String()}).SignedString([]byte(cfg.JWTSecret))
+	require.NoError(t, err)
+
+	assets := make([]*domain.Asset, 2)
+	for i := range assets {
+		assets[i], err = domain.NewAsset(domain.AssetTypeInsight, "insight", domain.InsightData{Text: "text"})
+		require.NoError(t, err)
+		require.NoError(t, repo.CreateAsset(ctx, assets[i]))
+	}
+	dispatched(t, repo)
+	do := func(method, path string, payload interface{}) int {
+		body, _ := json.Marshal(payload)
+		req, err := http.NewRequest(method, ts.URL+"/api/v1"+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	liveURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/v1/users/" + userID.String() + "/live"
+	_, resp, err := websocket.DefaultDialer.Dial(liveURL, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	_, resp, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/api/v1/users/"+uuid.New().String()+"/live?access_token="+token, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	queried, _, err := websocket.DefaultDialer.Dial(liveURL+"?access_token="+token, nil)
+	require.NoError(t, err)
+	queried.Close()
+	dialer := websocket.Dialer{Subprotocols: []string{middleware.WebSocketProtocol, token}}
+	conn, resp, err := dialer.Dial(liveURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, middleware.WebSocketProtocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+	request := func(message handler.LiveRequest) {
+		require.NoError(t, conn.WriteJSON(message))
+	}
+	next := func() handler.LiveMessage {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		var message handler.LiveMessage
+		require.NoError(t, conn.ReadJSON(&message))
+		return message
+	}
+
+	unknown := uuid.New()
+	request(handler.LiveRequest{Type: handler.LiveSubscribe, AssetIDs: []uuid.UUID{assets[0].ID, unknown}})
+	assert.Equal(t, handler.LiveMessage{Type: handler.LiveSubscribed, AssetIDs: []uuid.UUID{assets[0].ID}, NotFound: []uuid.UUID{unknown}}, next())
+
+	// Subscribed assets' changes and the user's favourites are streamed, other assets' changes are not
+	require.Equal(t, http.StatusOK, do(http.MethodPatch, "/assets/"+assets[1].ID.String()+"/description", handler.UpdateAssetDescriptionRequest{Description: "other"}))
+	require.Equal(t, http.StatusOK, do(http.MethodPatch, "/assets/"+assets[0].ID.String()+"/description", handler.UpdateAssetDescriptionRequest{Description: "watched"}))
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/users/"+userID.String()+"/favourites", handler.AddFavouriteRequest{AssetID: assets[1].ID}))
+	updated := next()
+	require.Equal(t, handler.LiveEvent, updated.Type)
+	assert.Equal(t, domain.EventAssetUpdated, updated.Event.Type)
+	assert.Equal(t, "watched", updated.Event.Asset.Description)
+	added := next()
+	assert.Equal(t, domain.EventFavouriteAdded, added.Event.Type)
+	assert.Equal(t, assets[1].ID, added.Event.Favourite.AssetID)
+
+	request(handler.LiveRequest{Type: handler.LiveUnsubscribe, AssetIDs: []uuid.UUID{assets[0].ID}})
+	assert.Equal(t, handler.LiveMessage{Type: handler.LiveSubscribed}, next())
+	request(handler.LiveRequest{Type: "publish"})
+	assert.Equal(t, handler.LiveError, next().Type)
+	request(handler.LiveRequest{Type: handler.LiveSubscribe})
+	assert.Contains(t, next().Error, "no asset IDs")
+
+	// Shutting down closes the connection gracefully
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, srv.Shutdown(shutdownCtx))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "got %v", err)
+	assert.Contains(t, logged.String(), "access_token=REDACTED")
+	assert.NotContains(t, logged.String(), token)
+}
+
+// lockedBuffer collects the log written by the server's goroutines
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestIntegration_FavouriteEvents(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	asset, err := domain.NewAsset(domain.AssetTypeInsight, "Heavy social media users", domain.InsightData{Text: "40%"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, asset))
+	userID := uuid.New()
+	eventsURL := ts.URL + "/api/v1/users/" + userID.String() + "/favourites/events"
+
+	type sseEvent struct {
+		ID, Type string
+		Data     domain.Event
+	}
+	subscribe := func(lastEventID string) (*http.Response, func() sseEvent) {
+		req, err := http.NewRequest(http.MethodGet, eventsURL, nil)
+		require.NoError(t, err)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		reader := bufio.NewReader(resp.Body)
+		return resp, func() sseEvent {
+			var event sseEvent
+			for {
+				line, err := reader.ReadString('\n')
+				require.NoError(t, err)
+				line = strings.TrimSuffix(line, "\n")
+				switch {
+				case line == "":
+					return event
+				case strings.HasPrefix(line, "id: "):
+					event.ID = strings.TrimPrefix(line, "id: ")
+				case strings.HasPrefix(line, "event: "):
+					event.Type = strings.TrimPrefix(line, "event: ")
+				case strings.HasPrefix(line, "data: "):
+					require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.Data))
+				}
+			}
+		}
+	}
+
+	dispatched(t, repo)
+	stream, next := subscribe("")
+
+	// Another user's favourites are not streamed, this user's are, and so is the removal of the
+	// favourite when its asset is deleted
+	body, _ := json.Marshal(handler.AddFavouriteRequest{AssetID: asset.ID})
+	for _, user := range []uuid.UUID{uuid.New(), userID} {
+		resp, err := http.Post(ts.URL+"/api/v1/users/"+user.String()+"/favourites", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/api/v1/assets/"+asset.ID.String(), nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	added := next()
+	assert.Equal(t, "favourite_added", added.Type)
+	assert.Equal(t, "3", added.ID, "numbered after the asset's creation and the other user's favourite")
+	assert.Equal(t, userID, *added.Data.UserID)
+	assert.Equal(t, asset.ID, added.Data.Favourite.AssetID)
+	removed := next()
+	assert.Equal(t, "favourite_removed", removed.Type)
+	assert.Equal(t, userID, *removed.Data.UserID)
+	assert.Equal(t, asset.ID, *removed.Data.AssetID)
+	stream.Body.Close()
+
+	// Reconnecting replays what was missed, unknown events ask for a reset
+	stream, next = subscribe(added.ID)
+	assert.Equal(t, removed, next())
+	stream.Body.Close()
+	stream, next = subscribe("99")
+	reset := next()
+	assert.Equal(t, "reset", reset.Type)
+	assert.Equal(t, "6", reset.ID, "the asset_deleted event, after both favourites' removal")
+	stream.Body.Close()
+
+	resp, err = http.Get(eventsURL + "?lastEventId=abc")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestIntegration_Webhooks(t *testing.T) {
+	// The receiver is local, which webhooks may only target when configured to
+	config.Get().WebhookAllowPrivate = true
+	defer func() { config.Get().WebhookAllowPrivate = false }()
+	repo := memory.NewRepository()
+	svc := service.NewFavouriteService(repo)
+	ts := httptest.NewServer(server.New(&config.Config{ServerAddress: ":0"}, handler.NewHandler(svc), server.NewChain(middleware.Logger())).Router())
+	defer ts.Close()
+
+	const secret = "integration-webhook-secret"
+	var mu sync.Mutex
+	var received []domain.Event
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		if !webhook.Verify(secret, timestamp, body, r.Header.Get(webhook.HeaderSignature)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event domain.Event
+		if err := json.Unmarshal(body, &event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		received = append(received, event)
+		mu.Unlock()
+	}))
+	defer receiver.Close()
+
+	do := func(method, path string, payload interface{}) (int, handler.Response) {
+		var body io.Reader
+		if payload != nil {
+			data, _ := json.Marshal(payload)
+			body = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, ts.URL+"/api/v1"+path, body)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp.StatusCode, result
+	}
+
+	// Subscriptions are validated, and their secret is never returned
+	status, _ := do(http.MethodPost, "/webhooks", handler.CreateWebhookRequest{URL: receiver.URL, EventTypes: []domain.EventType{domain.EventAssetCreated}, Secret: "short"})
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, result := do(http.MethodPost, "/webhooks", handler.CreateWebhookRequest{URL: receiver.URL, EventTypes: []domain.EventType{domain.EventAssetCreated}, Secret: secret})
+	require.Equal(t, http.StatusCreated, status)
+	created := result.Data.(map[string]interface{})
+	assert.NotContains(t, created, "secret")
+	webhookPath := "/webhooks/" + created["id"].(string)
+	status, result = do(http.MethodGet, "/webhooks", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, result.Data.(map[string]interface{})["webhooks"], 1)
+	status, _ = do(http.MethodGet, webhookPath, nil)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = do(http.MethodGet, "/webhooks/"+uuid.New().String(), nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// Assets created before events are dispatched are delivered signed once they are
+	status, _ = do(http.MethodPost, "/assets", map[string]interface{}{
+		"type": "insight",
+		"data": map[string]interface{}{"text": "40% of millennials spend more than 3 hours on social media daily"},
+	})
+	require.Equal(t, http.StatusCreated, status)
+	dispatchEvents(t, svc)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Len(t, received, 1)
+	assert.Equal(t, domain.EventAssetCreated, received[0].Type)
+	require.NotNil(t, received[0].Asset)
+	mu.Unlock()
+
+	// The delivery history records the succeeded delivery; there are no dead letters
+	var deliveries []interface{}
+	require.Eventually(t, func() bool {
+		status, result := do(http.MethodGet, webhookPath+"/deliveries?status=succeeded", nil)
+		require.Equal(t, http.StatusOK, status)
+		deliveries = result.Data.(map[string]interface{})["deliveries"].([]interface{})
+		return len(deliveries) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	delivery := deliveries[len(deliveries)-1].(map[string]interface{})
+	assert.Len(t, delivery["attempts"], 1)
+	status, result = do(http.MethodGet, webhookPath+"/dead-letters", nil)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Empty(t, result.Data.(map[string]interface{})["deliveries"])
+	status, _ = do(http.MethodGet, webhookPath+"/deliveries?status=lost", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Only dead letters can be redelivered
+	status, _ = do(http.MethodPost, webhookPath+"/deliveries/"+delivery["id"].(string)+"/redeliver", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = do(http.MethodDelete, webhookPath, nil)
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = do(http.MethodGet, webhookPath+"/deliveries", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestIntegration_WebhooksAdminOnly(t *testing.T) {
+	// Serve with authentication on: webhooks receive every user's events, so only administrators manage them
+	cfg := &config.Config{ServerAddress: ":0", AuthEnabled: true, JWTSecret: "test-secret"}
+	ts := httptest.NewServer(server.New(cfg, handler.NewHandler(service.NewFavouriteService(memory.NewRepository())), server.NewChain(middleware.Logger())).Router())
+	defer ts.Close()
+	sign := func(claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWTSecret))
+		require.NoError(t, err)
+		return token
+	}
+	userToken := sign(jwt.MapClaims{"user_id": uuid.New().String()})
+	adminToken := sign(jwt.MapClaims{"user_id": uuid.New().String(), "admin": true})
+	do := func(token, method, path string, payload interface{}) int {
+		body, _ := json.Marshal(payload)
+		req, err := http.NewRequest(method, ts.URL+"/api/v1"+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	request := handler.CreateWebhookRequest{URL: "https://hooks.example.com/gwi", EventTypes: []domain.EventType{domain.EventFavouriteAdded}, Secret: "integration-webhook-secret"}
+	assert.Equal(t, http.StatusForbidden, do(userToken, http.MethodPost, "/webhooks", request))
+	assert.Equal(t, http.StatusForbidden, do(userToken, http.MethodGet, "/webhooks", nil))
+	assert.Equal(t, http.StatusForbidden, do(userToken, http.MethodGet, "/webhooks/"+uuid.New().String()+"/deliveries", nil))
+	assert.Equal(t, http.StatusCreated, do(adminToken, http.MethodPost, "/webhooks", request))
+
+	// Local targets are refused
+	request.URL = "http://169.254.169.254/latest/meta-data"
+	assert.Equal(t, http.StatusBadRequest, do(adminToken, http.MethodPost, "/webhooks", request))
+}
+
+func TestIntegration_Audit(t *testing.T) {
+	userID, adminID := uuid.New(), uuid.New()
+
+	// Serve with authentication on: the audit trail is for administrators
+	cfg := &config.Config{ServerAddress: ":0", AuthEnabled: true, JWTSecret: "test-secret"}
+	repo := memory.NewRepository()
+	srv := server.New(cfg, handler.NewHandler(service.NewFavouriteService(repo)), server.NewChain(middleware.RequestID(), middleware.Logger()))
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	sign := func(claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.JWTSecret))
+		require.NoError(t, err)
+		return token
+	}
+	userToken := sign(jwt.MapClaims{"user_id": userID.String()})
+	adminToken := sign(jwt.MapClaims{"user_id": adminID.String(), "admin": true})
+	do := func(token, method, path, requestID string, payload interface{}) (*http.Response, handler.Response) {
+		body, _ := json.Marshal(payload)
+		req, err := http.NewRequest(method, ts.URL+"/api/v1"+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if requestID != "" {
+			req.Header.Set("X-Request-ID", requestID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result handler.Response
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp, result
+	}
+
+	// Requests are tagged with the client's request ID, or a generated one
+	resp, result := do(userToken, http.MethodPost, "/assets", "create-asset", map[string]interface{}{
+		"type": "insight", "description": "Audited", "data": map[string]interface{}{"text": "text"},
+	})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "create-asset", resp.Header.Get("X-Request-ID"))
+	assetID := result.Data.(map[string]interface{})["id"].(string)
+	resp, _ = do(userToken, http.MethodPost, "/users/"+userID.String()+"/favourites", "", map[string]string{"asset_id": assetID})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	generated := resp.Header.Get("X-Request-ID")
+	_, err := uuid.Parse(generated)
+	assert.NoError(t, err)
+	resp, _ = do(userToken, http.MethodPatch, "/assets/"+assetID+"/description", "", map[string]string{"description": "Changed"})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Only administrators read the trail
+	resp, _ = do(userToken, http.MethodGet, "/audit", "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	resp, _ = do(userToken, http.MethodGet, "/audit/verify", "", nil)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	resp, result = do(adminToken, http.MethodGet, "/audit", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	page := result.Data.(map[string]interface{})
+	assert.Equal(t, float64(3), page["total"])
+	entries := page["entries"].([]interface{})
+	require.Len(t, entries, 3)
+	created := entries[0].(map[string]interface{})
+	assert.Equal(t, "asset.create", created["action"])
+	assert.Equal(t, userID.String(), created["actor_id"])
+	assert.Equal(t, "create-asset", created["request_id"])
+	assert.Equal(t, generated, entries[1].(map[string]interface{})["request_id"])
+	updated := entries[2].(map[string]interface{})
+	assert.Equal(t, "Audited", updated["before"].(map[string]interface{})["description"])
+	assert.Equal(t, "Changed", updated["after"].(map[string]interface{})["description"])
+	assert.Equal(t, created["hash"], entries[1].(map[string]interface{})["prev_hash"])
+
+	// Filters and pages
+	_, result = do(adminToken, http.MethodGet, "/audit?action=asset&limit=1", "", nil)
+	page = result.Data.(map[string]interface{})
+	assert.Equal(t, float64(2), page["total"])
+	require.Len(t, page["entries"], 1)
+	assert.Equal(t, float64(1), page["next"])
+	_, result = do(adminToken, http.MethodGet, "/audit?action=asset&limit=1&after=1", "", nil)
+	page = result.Data.(map[string]interface{})
+	assert.Equal(t, "asset.update", page["entries"].([]interface{})[0].(map[string]interface{})["action"])
+	_, result = do(adminToken, http.MethodGet, "/audit?request_id="+generated+"&actor_id="+userID.String(), "", nil)
+	assert.Equal(t, float64(1), result.Data.(map[string]interface{})["total"])
+	_, result = do(adminToken, http.MethodGet, "/audit?since="+time.Now().Add(time.Hour).UTC().Format(time.RFC3339), "", nil)
+	assert.Equal(t, float64(0), result.Data.(map[string]interface{})["total"])
+	for _, query := range []string{"limit=x", "limit=5000", "after=-1", "actor_id=me", "since=yesterday"} {
+		resp, _ = do(adminToken, http.MethodGet, "/audit?"+query, "", nil)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	// The chain verifies
+	resp, result = do(adminToken, http.MethodGet, "/audit/verify", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	verification := result.Data.(map[string]interface{})
+	assert.Equal(t, true, verification["valid"])
+	assert.Equal(t, float64(3), verification["entries"])
+	assert.Equal(t, updated["hash"], verification["head_hash"])
+}
+
+func TestIntegration_Idempotency(t *testing.T) {
+	userID, otherID := uuid.New(), uuid.New()
+
+	cfg := &config.Config{ServerAddress: ":0", AuthEnabled: true, JWTSecret: "test-secret", IdempotencyWindow: time.Hour}
+	repo := memory.NewRepository()
+	srv := server.New(cfg, handler.NewHandler(service.NewFavouriteService(repo)), server.NewChain(middleware.RequestID(), middleware.Logger()))
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	base := ts.URL
+	tokens := make(map[uuid.UUID]string)
+	for _, id := range []uuid.UUID{userID, otherID} {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": id.String()}).SignedString([]byte(cfg.JWTSecret))
+		require.NoError(t, err)
+		tokens[id] = token
+	}
+	post := func(user uuid.UUID, path, key string, payload interface{}) (*http.Response, handler.Response) {
+		body, _ := json.Marshal(payload)
+		req, err := http.NewRequest(http.MethodPost, base+"/api/v1"+path, bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+tokens[user])
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result handler.Response
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp, result
+	}
+	asset := func(description string) map[string]interface{} {
+		return map[string]interface{}{"type": "insight", "description": description, "data": map[string]interface{}{"text": "text"}}
+	}
+	assetCount := func() int {
+		_, total, err := repo.ListAssets(context.Background(), domain.NewPageQuery(1, 0, "", ""))
+		require.NoError(t, err)
+		return total
+	}
+
+	// A retry gets the first response back, without creating another asset
+	resp, first := post(userID, "/assets", "create-1", asset("Retried"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	resp, retried := post(userID, "/assets", "create-1", asset("Retried"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, first.Data, retried.Data)
+	assert.Equal(t, 1, assetCount())
+	assetID := first.Data.(map[string]interface{})["id"].(string)
+
+	// Reusing a key for another request is rejected; keys are scoped per user; requests without one are never replayed
+	resp, _ = post(userID, "/assets", "create-1", asset("Different"))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	resp, _ = post(userID, "/users/"+userID.String()+"/favourites", "create-1", map[string]string{"asset_id": assetID})
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	resp, _ = post(otherID, "/assets", "create-1", asset("Different"))
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	post(userID, "/assets", "", asset("Unkeyed"))
+	post(userID, "/assets", "", asset("Unkeyed"))
+	assert.Equal(t, 4, assetCount())
+
+	// The query is part of the request, and keyed bodies are bounded
+	resp, _ = post(userID, "/assets?dry=1", "create-1", asset("Retried"))
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	resp, _ = post(userID, "/assets", "too-large", asset(strings.Repeat("x", 1<<20)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.Equal(t, 4, assetCount())
+
+	// Retried favourites replay their creation rather than conflicting
+	resp, _ = post(userID, "/users/"+userID.String()+"/favourites", "favourite-1", map[string]string{"asset_id": assetID})
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, _ = post(userID, "/users/"+userID.String()+"/favourites", "favourite-1", map[string]string{"asset_id": assetID})
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+
+	// Concurrent duplicates are handled once, the others waiting for the response
+	var wg sync.WaitGroup
+	ids := make([]interface{}, 8)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, result := post(userID, "/assets", "concurrent", asset("Concurrent"))
+			if assert.Equal(t, http.StatusCreated, resp.StatusCode) {
+				ids[i] = result.Data.(map[string]interface{})["id"]
+			}
+		}()
+	}
+	wg.Wait()
+	for _, id := range ids {
+		assert.Equal(t, ids[0], id)
+	}
+	assert.Equal(t, 5, assetCount())
+
+	// Responses are kept for the window only
+	cfg.IdempotencyWindow = 20 * time.Millisecond
+	short := httptest.NewServer(server.New(cfg, handler.NewHandler(service.NewFavouriteService(repo)), server.NewChain()).Router())
+	defer short.Close()
+	base = short.URL
+	resp, first = post(userID, "/assets", "expiring", asset("Expiring"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	time.Sleep(50 * time.Millisecond)
+	resp, retried = post(userID, "/assets", "expiring", asset("Expiring"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	assert.NotEqual(t, first.Data.(map[string]interface{})["id"], retried.Data.(map[string]interface{})["id"])
+
+	// Beyond the bounds, the oldest responses are forgotten early
+	cfg.IdempotencyWindow, cfg.IdempotencyMaxKeys = time.Hour, 1
+	bounded := httptest.NewServer(server.New(cfg, handler.NewHandler(service.NewFavouriteService(repo)), server.NewChain()).Router())
+	defer bounded.Close()
+	base = bounded.URL
+	resp, first = post(userID, "/assets", "oldest", asset("Oldest"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, _ = post(userID, "/assets", "newest", asset("Newest"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, _ = post(userID, "/assets", "newest", asset("Newest"))
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+	resp, retried = post(userID, "/assets", "oldest", asset("Oldest"))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	assert.NotEqual(t, first.Data.(map[string]interface{})["id"], retried.Data.(map[string]interface{})["id"])
+}
+
+func TestIntegration_FavouriteFields(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	userID := uuid.New()
+	asset, err := domain.NewAsset(domain.AssetTypeInsight, "Social media", domain.InsightData{Text: "Usage doubled"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, asset))
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, asset.ID)))
+
+	list := func(query string) (int, map[string]interface{}) {
+		resp, err := http.Get(ts.URL + "/api/v1/users/" + userID.String() + "/favourites?" + query)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var listResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		data := listResp.Data.(map[string]interface{})
+		assert.Equal(t, float64(1), data["total"])
+		return resp.StatusCode, data["favourites"].([]interface{})[0].(map[string]interface{})
+	}
+
+	// Assets are embedded unless a field selection leaves them out
+	_, fav := list("")
+	assert.Equal(t, asset.ID.String(), fav["asset_id"])
+	require.Contains(t, fav, "asset")
+	assert.Equal(t, "Social media", fav["asset"].(map[string]interface{})["description"])
+
+	_, fav = list("include=asset")
+	require.Contains(t, fav, "asset")
+	assert.Equal(t, "Social media", fav["asset"].(map[string]interface{})["description"])
+
+	// Sparse fieldsets
+	_, fav = list("fields=id,created_at")
+	assert.Len(t, fav, 2, "the asset is left out")
+	assert.Contains(t, fav, "id")
+	assert.Contains(t, fav, "created_at")
+
+	_, fav = list("fields=id,asset.description,asset.type")
+	assert.Len(t, fav, 2)
+	assert.Equal(t, map[string]interface{}{"description": "Social media", "type": "insight"}, fav["asset"])
+
+	_, fav = list("include=asset&fields=asset_id")
+	assert.Len(t, fav, 2)
+	assert.Contains(t, fav["asset"], "data", "the whole asset is embedded")
+
+	for _, query := range []string{"fields=id,colour", "fields=asset.colour", "include=user"} {
+		status, _ := list(query)
+		assert.Equal(t, http.StatusBadRequest, status, query)
+	}
 }
 
 func TestIntegration_ListAssets(t *testing.T) {
@@ -301,9 +1804,9 @@ func TestIntegration_ListAssets(t *testing.T) {
 		domain.AudienceData{
 			Gender:             "Female",
 			BirthCountry:       "USA",
-			AgeGroups:          []string{"25-34", "35-44"},
-			HoursSocialDaily:   2.5,
-			PurchasesLastMonth: 5,
+			AgeGroups:          []domain.AgeGroup{"25-34", "35-44"},
+			HoursSocialDaily:   domain.AtLeast(2.5),
+			PurchasesLastMonth: domain.AtLeast(5),
 		},
 	)
 	time.Sleep(10 * time.Millisecond)
@@ -374,6 +1877,292 @@ func TestIntegration_ListAssets(t *testing.T) {
 	assets = listData["assets"].([]interface{})
 	assert.Equal(t, "Epsilon Insight", assets[0].(map[string]interface{})["description"])
 	assert.Equal(t, "Delta Chart", assets[1].(map[string]interface{})["description"])
+
+	// Test filtering by type and description
+	resp, err = http.Get(ts.URL + "/api/v1/assets?sortBy=description&order=asc&filter=" +
+		url.QueryEscape("type:chart|insight,description~ta ") + "&filter=" + url.QueryEscape("created_at>=2000-01-01"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	listResp = handler.Response{}
+	err = json.NewDecoder(resp.Body).Decode(&listResp)
+	require.NoError(t, err)
+	listData = listResp.Data.(map[string]interface{})
+	assert.Equal(t, float64(2), listData["total"])
+	assets = listData["assets"].([]interface{})
+	assert.Equal(t, "Beta Insight", assets[0].(map[string]interface{})["description"])
+	assert.Equal(t, "Delta Chart", assets[1].(map[string]interface{})["description"])
+
+	// Unknown fields are rejected, including favourite-only ones
+	for _, filter := range []string{"colour:red", "favourited_at>2025-01-01", "created_at>soon"} {
+		resp, err = http.Get(ts.URL + "/api/v1/assets?filter=" + url.QueryEscape(filter))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, filter)
+	}
+}
+
+func TestIntegration_MatchAudience(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	// Males aged 25-34 that spend more than 3 hours on social media daily
+	createAssetReq := map[string]interface{}{
+		"type":        "audience",
+		"description": "Heavy social media users",
+		"data": map[string]interface{}{
+			"gender":             "Male",
+			"age_groups":         []string{"25-34"},
+			"hours_social_daily": map[string]interface{}{"gt": 3},
+		},
+	}
+	body, _ := json.Marshal(createAssetReq)
+	resp, err := http.Post(ts.URL+"/api/v1/assets", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var createAssetResp handler.Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&createAssetResp))
+	assetID := createAssetResp.Data.(map[string]interface{})["id"].(string)
+
+	match := func(profile map[string]interface{}) (int, handler.Response) {
+		body, _ := json.Marshal(profile)
+		resp, err := http.Post(ts.URL+"/api/v1/assets/"+assetID+"/match", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		var matchResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&matchResp))
+		return resp.StatusCode, matchResp
+	}
+
+	status, matchResp := match(map[string]interface{}{
+		"gender": "Male", "birth_country": "GB", "age": 29, "hours_social_daily": 3.5, "purchases_last_month": 1,
+	})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, true, matchResp.Data.(map[string]interface{})["matched"])
+
+	status, matchResp = match(map[string]interface{}{
+		"gender": "Male", "birth_country": "GB", "age": 29, "hours_social_daily": 2, "purchases_last_month": 1,
+	})
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, false, matchResp.Data.(map[string]interface{})["matched"])
+
+	status, _ = match(map[string]interface{}{"gender": "Male", "birth_country": "Atlantis", "age": 29})
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Matching against a non-audience asset is rejected
+	insight, err := domain.NewAsset(domain.AssetTypeInsight, "Insight", domain.InsightData{Text: "text"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, insight))
+
+	body, _ = json.Marshal(map[string]interface{}{"gender": "Male", "birth_country": "GB", "age": 29})
+	resp, err = http.Post(ts.URL+"/api/v1/assets/"+insight.ID.String()+"/match", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestIntegration_RenderAsset(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	chart, err := domain.NewAsset(domain.AssetTypeChart, "Quarterly revenue", domain.ChartData{
+		Title:      "Revenue",
+		Kind:       domain.ChartKindBar,
+		Categories: []string{"Q1", "Q2"},
+		Series:     []domain.ChartSeries{{Name: "2025", Values: []float64{10, 12}}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, chart))
+
+	resp, err := http.Get(ts.URL + "/api/v1/assets/" + chart.ID.String() + "/render")
+	require.NoError(t, err)
+	svg, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "image/svg+xml", resp.Header.Get("Content-Type"))
+	assert.Contains(t, string(svg), ">Revenue</text>")
+
+	resp, err = http.Get(ts.URL + "/api/v1/assets/" + chart.ID.String() + "/render?format=png&width=400&height=300")
+	require.NoError(t, err)
+	img, err := png.Decode(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", resp.Header.Get("Content-Type"))
+	assert.Equal(t, 400, img.Bounds().Dx())
+	assert.Equal(t, 300, img.Bounds().Dy())
+
+	for _, query := range []string{"?format=gif", "?width=abc", "?width=10"} {
+		resp, err = http.Get(ts.URL + "/api/v1/assets/" + chart.ID.String() + "/render" + query)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	// Only charts can be rendered
+	insight, err := domain.NewAsset(domain.AssetTypeInsight, "Insight", domain.InsightData{Text: "text"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, insight))
+
+	resp, err = http.Get(ts.URL + "/api/v1/assets/" + insight.ID.String() + "/render")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/api/v1/assets/" + uuid.New().String() + "/render")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestIntegration_Tags(t *testing.T) {
+	ts, _ := setupTestServer(t)
+	defer ts.Close()
+
+	userID := uuid.New()
+
+	createAsset := func(description string, tags []string) string {
+		body, _ := json.Marshal(map[string]interface{}{
+			"type":        "insight",
+			"description": description,
+			"data":        map[string]interface{}{"text": description},
+			"tags":        tags,
+		})
+		resp, err := http.Post(ts.URL+"/api/v1/assets", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var createResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&createResp))
+		return createResp.Data.(map[string]interface{})["id"].(string)
+	}
+	listDescriptions := func(url string) []interface{} {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var listResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&listResp))
+		data := listResp.Data.(map[string]interface{})
+		out := []interface{}{}
+		for _, key := range []string{"assets", "favourites"} {
+			if items, ok := data[key].([]interface{}); ok {
+				for _, item := range items {
+					item := item.(map[string]interface{})
+					if asset, ok := item["asset"].(map[string]interface{}); ok {
+						item = asset
+					}
+					out = append(out, item["description"])
+				}
+			}
+		}
+		return out
+	}
+
+	sportsID := createAsset("Sports fans", []string{"Sports", "Gen Z"})
+	tvID := createAsset("TV viewers", []string{"tv"})
+	createAsset("Untagged", nil)
+
+	// Tag the TV asset too and favourite both tagged assets
+	body, _ := json.Marshal(map[string]interface{}{"tags": []string{"gen z"}})
+	resp, err := http.Post(ts.URL+"/api/v1/assets/"+tvID+"/tags", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var tagResp handler.Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&tagResp))
+	assert.Equal(t, []interface{}{"gen-z", "tv"}, tagResp.Data.(map[string]interface{})["tags"])
+
+	for _, id := range []string{sportsID, tvID} {
+		body, _ := json.Marshal(map[string]interface{}{"asset_id": id})
+		resp, err := http.Post(ts.URL+"/api/v1/users/"+userID.String()+"/favourites", "application/json", bytes.NewReader(body))
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+	}
+
+	assets := ts.URL + "/api/v1/assets?sortBy=description&order=asc"
+	assert.Equal(t, []interface{}{"Sports fans", "TV viewers"}, listDescriptions(assets+"&tag=gen-z"))
+	assert.Equal(t, []interface{}{"Sports fans"}, listDescriptions(assets+"&tag=gen-z&tag=sports"))
+	assert.Equal(t, []interface{}{"Sports fans", "TV viewers"}, listDescriptions(assets+"&tag=sports&tag=tv&tagMatch=any"))
+
+	favourites := ts.URL + "/api/v1/users/" + userID.String() + "/favourites?include=asset&sortBy=created_at&order=asc"
+	assert.Equal(t, []interface{}{"TV viewers"}, listDescriptions(favourites+"&tag=tv"))
+
+	resp, err = http.Get(assets + "&tag=not%20valid!")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, err = http.Get(assets + "&tag=tv&tagMatch=some")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Untag and check the counts
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL+"/api/v1/assets/"+tvID+"/tags/tv", nil)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, err = http.Get(ts.URL + "/api/v1/tags")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var tagsResp handler.Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&tagsResp))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"tag": "gen-z", "count": float64(2)},
+		map[string]interface{}{"tag": "sports", "count": float64(1)},
+	}, tagsResp.Data)
+}
+
+func TestIntegration_Search(t *testing.T) {
+	ts, repo := setupTestServer(t)
+	defer ts.Close()
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	sales, err := domain.NewAsset(domain.AssetTypeChart, "Q4 sales chart", domain.ChartData{Title: "Sales by region"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, sales))
+	social, err := domain.NewAsset(domain.AssetTypeInsight, "Social media", domain.InsightData{Text: "Sales via social media doubled"})
+	require.NoError(t, err)
+	require.NoError(t, repo.CreateAsset(ctx, social))
+	require.NoError(t, repo.AddFavourite(ctx, domain.NewFavourite(userID, social.ID)))
+
+	search := func(url string) (int, []interface{}) {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		var searchResp handler.Response
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&searchResp))
+		if resp.StatusCode != http.StatusOK {
+			return resp.StatusCode, nil
+		}
+		data := searchResp.Data.(map[string]interface{})
+		var ids []interface{}
+		for _, key := range []string{"assets", "favourites"} {
+			items, _ := data[key].([]interface{})
+			for _, item := range items {
+				item := item.(map[string]interface{})
+				if key == "favourites" {
+					item = item["asset"].(map[string]interface{})
+				}
+				ids = append(ids, item["id"])
+			}
+		}
+		return resp.StatusCode, ids
+	}
+
+	status, ids := search(ts.URL + "/api/v1/assets/search?q=sales")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []interface{}{sales.ID.String(), social.ID.String()}, ids, "title matches rank above body matches")
+
+	status, ids = search(ts.URL + "/api/v1/assets/search?q=soc%20med")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []interface{}{social.ID.String()}, ids)
+
+	status, ids = search(ts.URL + "/api/v1/users/" + userID.String() + "/favourites/search?q=sales")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, []interface{}{social.ID.String()}, ids)
+
+	status, _ = search(ts.URL + "/api/v1/assets/search?q=")
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = search(ts.URL + "/api/v1/assets/search?q=the")
+	assert.Equal(t, http.StatusBadRequest, status)
 }
 
 func TestIntegration_ErrorCases(t *testing.T) {
@@ -436,6 +2225,95 @@ func TestIntegration_ErrorCases(t *testing.T) {
 	}
 }
 
+func TestIntegration_ProblemDetails(t *testing.T) {
+	cfg := &config.Config{ServerAddress: ":0", AuthEnabled: true, JWTSecret: "test-secret", IdempotencyWindow: time.Hour}
+	srv := server.New(cfg, handler.NewHandler(service.NewFavouriteService(memory.NewRepository())), server.NewChain(middleware.RequestID(), middleware.Logger()))
+	ts := httptest.NewServer(srv.Router())
+	defer ts.Close()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": uuid.NewString()}).SignedString([]byte(cfg.JWTSecret))
+	require.NoError(t, err)
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": uuid.NewString(), "exp": time.Now().Add(-time.Minute).Unix(),
+	}).SignedString([]byte(cfg.JWTSecret))
+	require.NoError(t, err)
+	do := func(authorization, accept, key, body string) (*http.Response, map[string]interface{}) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/api/v1/assets?dry=1", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("X-Request-ID", "problem-request")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var result map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+		return resp, result
+	}
+	untitled := `{"type":"chart","description":"Untitled","data":{"kind":"bar"}}`
+
+	// Clients asking for problem details get them, with the invalid fields
+	resp, result := do("Bearer "+token, "application/problem+json", "", untitled)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, map[string]interface{}{
+		"type":       "/problems/invalid_chart_data",
+		"title":      "invalid chart data",
+		"status":     float64(http.StatusBadRequest),
+		"detail":     "invalid chart data: title is required",
+		"instance":   "/api/v1/assets?dry=1",
+		"code":       "invalid_chart_data",
+		"errors":     []interface{}{map[string]interface{}{"field": "title", "message": "title is required"}},
+		"request_id": "problem-request",
+	}, result)
+
+	// Others get the legacy envelope, with the same code and fields
+	resp, result = do("Bearer "+token, "application/json", "", untitled)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, false, result["success"])
+	assert.Equal(t, "invalid chart data: title is required", result["error"])
+	assert.Equal(t, "invalid_chart_data", result["code"])
+	assert.Len(t, result["details"], 1)
+
+	_, result = do("Bearer "+token, "application/problem+json", "", `{"type":"chart","description":42}`)
+	assert.Equal(t, "invalid_json", result["code"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"field": "description", "message": "must be string"}}, result["errors"])
+
+	// Middleware errors read alike, and do not leak the token parser's reasons
+	for _, tt := range []struct {
+		authorization string
+		code          string
+	}{
+		{"", "missing_token"},
+		{"Token " + token, "malformed_authorization"},
+		{"Bearer not-a-token", "invalid_token"},
+		{"Bearer " + expired, "expired_token"},
+	} {
+		resp, result = do(tt.authorization, "application/problem+json", "", untitled)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, tt.code)
+		assert.Equal(t, tt.code, result["code"])
+		assert.Equal(t, "/problems/"+tt.code, result["type"])
+		assert.NotContains(t, result["detail"], "signature", tt.code)
+
+		resp, result = do(tt.authorization, "", "", untitled)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		assert.Equal(t, tt.code, result["code"])
+	}
+
+	resp, _ = do("Bearer "+token, "", "asset-1", `{"type":"insight","description":"First","data":{"text":"text"}}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	resp, result = do("Bearer "+token, "application/problem+json", "asset-1", `{"type":"insight","description":"Second","data":{"text":"text"}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "idempotency_key_reused", result["code"])
+}
+
 func TestIntegration_HealthCheck(t *testing.T) {
 	ts, _ := setupTestServer(t)
 	defer ts.Close()